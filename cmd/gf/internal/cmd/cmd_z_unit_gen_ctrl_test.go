@@ -13,6 +13,7 @@ import (
 	"github.com/gogf/gf/cmd/gf/v2/internal/cmd/genctrl"
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/text/gstr"
 	"github.com/gogf/gf/v2/util/guid"
 	"github.com/gogf/gf/v2/util/gutil"
 )
@@ -307,3 +308,751 @@ func expectFilesContent(t *gtest.T, paths []string, expectPaths []string) {
 		t.Assert(val, expect)
 	}
 }
+
+func Test_Gen_Ctrl_RouteConflict(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-route-conflict", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+				Strict:    true,
+			}
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNE(err, nil)
+		t.Assert(gstr.Contains(err.Error(), `route conflict "GET /user/info"`), true)
+		t.Assert(gstr.Contains(err.Error(), filepath.Join(apiFolder, "user", "v1", "user.go")), true)
+		t.Assert(gstr.Contains(err.Error(), filepath.Join(apiFolder, "admin", "v1", "admin.go")), true)
+		t.Assert(gstr.Contains(err.Error(), `PUT /user/info`), false)
+
+		// nothing is generated if conflicts found in strict mode.
+		files, err := gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+		t.Assert(len(files), 0)
+	})
+}
+
+func Test_Gen_Ctrl_GenValidation(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-validation", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder:     apiFolder,
+				DstFolder:     ctrlPath,
+				GenValidation: true,
+			}
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(apiFolder + filepath.FromSlash("/article/article.go"))
+
+		var (
+			testPath = gtest.DataPath("genctrl-validation", "controller")
+			files    = []string{
+				"/article/article_v1_create.go",
+				"/article/article_v1_get_list.go",
+			}
+		)
+		for _, file := range files {
+			t.Assert(
+				gfile.GetContents(ctrlPath+filepath.FromSlash(file)),
+				gfile.GetContents(testPath+filepath.FromSlash(file)),
+			)
+		}
+	})
+}
+
+func Test_Gen_Ctrl_Middleware_Group(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-middleware", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+			}
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(apiFolder + filepath.FromSlash("/article/article.go"))
+
+		var (
+			routerFile = "/article/article_router.go"
+			testPath   = gtest.DataPath("genctrl-middleware", "controller")
+			content    = gfile.GetContents(ctrlPath + filepath.FromSlash(routerFile))
+		)
+		t.Assert(content, gfile.GetContents(testPath+filepath.FromSlash(routerFile)))
+		// The three endpoints sharing "auth" annotation are registered under one group.
+		t.Assert(gstr.Count(content, `group.Middleware(`), 1)
+		t.Assert(gstr.Count(content, `middlewares["auth"]`), 1)
+	})
+}
+
+func Test_Gen_Ctrl_GroupByPrefix(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-route-prefix", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder:     apiFolder,
+				DstFolder:     ctrlPath,
+				GroupByPrefix: true,
+			}
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(apiFolder + filepath.FromSlash("/article/article.go"))
+
+		content := gfile.GetContents(ctrlPath + filepath.FromSlash("/article/article_router.go"))
+		t.Assert(gstr.Count(content, `// routes with prefix "/article".`), 2)
+		t.Assert(gstr.Count(content, `// routes with prefix "/category".`), 1)
+		t.Assert(gstr.Count(content, `group.Middleware(middlewares["auth"])`), 1)
+		t.Assert(gstr.Contains(content, "group.Bind(\n\t\tctrlV1.GetCategoryList,\n\t\tctrlV1.CreateCategory,\n\t)"), true)
+	})
+}
+
+func Test_Gen_Ctrl_Template(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-template", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+				Template:  gtest.DataPath("genctrl-template", "method.tpl"),
+			}
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(apiFolder + filepath.FromSlash("/article/article.go"))
+
+		content := gfile.GetContents(ctrlPath + filepath.FromSlash("/article/article_v1_get_list.go"))
+		t.Assert(gstr.Contains(content, `// article/v1.GetList is implemented by custom template.`), true)
+		t.Assert(gstr.Contains(content, `res = &v1.GetListRes{}`), true)
+		// The imports of built-in method body are removed as they are not used.
+		t.Assert(gstr.Contains(content, `gcode.CodeNotImplemented`), false)
+		t.Assert(gstr.Contains(content, `"github.com/gogf/gf/v2/errors/gcode"`), false)
+	})
+	// Invalid template.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-template", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+				Template:  gtest.DataPath("genctrl-template", "method_invalid.tpl"),
+			}
+		)
+
+		_, err := genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNE(err, nil)
+		// No files are written if the template fails parsing.
+		t.Assert(gfile.Exists(ctrlPath), false)
+		t.Assert(gfile.Exists(apiFolder+filepath.FromSlash("/article/article.go")), false)
+	})
+}
+
+func Test_Gen_Ctrl_Skip_ResLess_Api(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-res-less", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+			}
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+
+		var genApi = apiFolder + filepath.FromSlash("/article/article.go")
+		defer gfile.Remove(genApi)
+
+		// The api "CreateReq" without "CreateRes" is skipped.
+		t.Assert(gstr.Contains(gfile.GetContents(genApi), `GetList(`), true)
+		t.Assert(gstr.Contains(gfile.GetContents(genApi), `Create(`), false)
+		t.Assert(gfile.Exists(ctrlPath+filepath.FromSlash("/article/article_v1_get_list.go")), true)
+		t.Assert(gfile.Exists(ctrlPath+filepath.FromSlash("/article/article_v1_create.go")), false)
+	})
+}
+
+func Test_Gen_Ctrl_ResMissing(t *testing.T) {
+	var apiFolder = gtest.DataPath("genctrl-res-less", "api")
+	// Strict mode exits with error before any files are written.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath = gfile.Temp(guid.S())
+			in       = genctrl.CGenCtrlInput{
+				SrcFolder:  apiFolder,
+				DstFolder:  ctrlPath,
+				ResMissing: "strict",
+			}
+		)
+		defer gfile.Remove(ctrlPath)
+
+		_, err := genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNE(err, nil)
+		t.Assert(gstr.Contains(err.Error(), `"CreateRes"`), true)
+		t.Assert(gfile.Exists(ctrlPath), false)
+		t.Assert(gfile.Exists(apiFolder+filepath.FromSlash("/article/article.go")), false)
+	})
+	// Lenient mode generates the controller method with TODO placeholder.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath = gfile.Temp(guid.S())
+			in       = genctrl.CGenCtrlInput{
+				SrcFolder:  apiFolder,
+				DstFolder:  ctrlPath,
+				ResMissing: "lenient",
+			}
+			genApi = apiFolder + filepath.FromSlash("/article/article.go")
+		)
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(genApi)
+
+		content := gfile.GetContents(ctrlPath + filepath.FromSlash("/article/article_v1_create.go"))
+		t.Assert(gstr.Contains(content, `// TODO: define the response struct "CreateRes" in api definition`), true)
+		t.Assert(gstr.Contains(
+			gfile.GetContents(ctrlPath+filepath.FromSlash("/article/article_v1_get_list.go")), `TODO`,
+		), false)
+	})
+	// Invalid mode.
+	gtest.C(t, func(t *gtest.T) {
+		_, err := genctrl.CGenCtrl{}.Ctrl(ctx, genctrl.CGenCtrlInput{
+			SrcFolder:  apiFolder,
+			DstFolder:  gfile.Temp(guid.S()),
+			ResMissing: "unknown",
+		})
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_Gen_Ctrl_Method_Conflict(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-method-conflict", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+			}
+		)
+		defer gfile.Remove(ctrlPath)
+
+		_, err := genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNE(err, nil)
+		t.Assert(gstr.Contains(err.Error(), `method conflict "article.GetList"`), true)
+		t.Assert(gstr.Contains(err.Error(), filepath.FromSlash("v1/article.go")), true)
+		t.Assert(gstr.Contains(err.Error(), filepath.FromSlash("v1/article_legacy.go")), true)
+		// Nothing is written.
+		t.Assert(gfile.Exists(ctrlPath), false)
+		t.Assert(gfile.Exists(apiFolder+filepath.FromSlash("/article/article.go")), false)
+	})
+}
+
+func Test_Gen_Ctrl_Incremental(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-validation", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+			}
+			createFile  = ctrlPath + filepath.FromSlash("/article/article_v1_create.go")
+			getListFile = ctrlPath + filepath.FromSlash("/article/article_v1_get_list.go")
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(apiFolder + filepath.FromSlash("/article/article.go"))
+
+		// Manual edits of the existing controller methods.
+		var (
+			createContent = gstr.Replace(
+				gfile.GetContents(createFile),
+				`return nil, gerror.NewCode(gcode.CodeNotImplemented)`,
+				`return &v1.CreateRes{}, nil`,
+			)
+			getListContent = gstr.Replace(
+				gfile.GetContents(getListFile),
+				`(res *v1.GetListRes, err error) {
+	return nil, gerror.NewCode(gcode.CodeNotImplemented)`,
+				`(*v1.GetListRes, error) {
+	return &v1.GetListRes{}, nil`,
+			)
+		)
+		t.AssertNil(gfile.PutContents(createFile, createContent))
+		t.AssertNil(gfile.PutContents(getListFile, getListContent))
+
+		// The existing controller methods are kept.
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		t.Assert(gfile.GetContents(createFile), createContent)
+		t.Assert(gfile.GetContents(getListFile), getListContent)
+
+		// The existing controller methods are regenerated in force mode.
+		in.Force = true
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		t.Assert(gstr.Contains(gfile.GetContents(createFile), `CodeNotImplemented`), true)
+		t.Assert(gstr.Contains(gfile.GetContents(getListFile), `CodeNotImplemented`), true)
+		t.Assert(gstr.Count(gfile.GetContents(getListFile), `) GetList(`), 1)
+	})
+}
+
+func Test_Gen_Ctrl_ReqSuffix(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-req-suffix", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+				ReqSuffix: "Request",
+			}
+			genApi   = apiFolder + filepath.FromSlash("/article/article.go")
+			ctrlFile = ctrlPath + filepath.FromSlash("/article/article_v1_get_list.go")
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(genApi)
+
+		t.Assert(gstr.Contains(
+			gfile.GetContents(genApi),
+			`GetList(ctx context.Context, req *v1.GetListRequest) (res *v1.GetListRes, err error)`,
+		), true)
+		content := gfile.GetContents(ctrlFile)
+		t.Assert(gstr.Contains(
+			content,
+			`) GetList(ctx context.Context, req *v1.GetListRequest) (res *v1.GetListRes, err error)`,
+		), true)
+
+		// The generated controller methods are recognized using the same suffix.
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		t.Assert(gfile.GetContents(ctrlFile), content)
+	})
+}
+
+// The methods in merged files are recognized and not generated again
+// when the controller files are generated without merge.
+func Test_Gen_Ctrl_Merged_Methods_Recognized(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("issue", "3460", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+				Merge:     true,
+			}
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+
+		in.Merge = false
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+
+		files, err := gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+		t.Assert(files, []string{
+			filepath.Join(ctrlPath, "/hello/hello.go"),
+			filepath.Join(ctrlPath, "/hello/hello_new.go"),
+			filepath.Join(ctrlPath, "/hello/hello_v1_req.go"),
+			filepath.Join(ctrlPath, "/hello/hello_v2_req.go"),
+		})
+	})
+}
+
+func Test_Gen_Ctrl_Prune(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-validation", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+			}
+			importPath = "github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-validation/api/article/v1"
+			deleteFile = ctrlPath + filepath.FromSlash("/article/article_v1_delete.go")
+			extraFile  = ctrlPath + filepath.FromSlash("/article/article_v1_extra.go")
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(apiFolder + filepath.FromSlash("/article/article.go"))
+
+		// Orphaned controller methods whose api definitions are missing.
+		err = gfile.PutContents(deleteFile, `package article
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+
+	"`+importPath+`"
+)
+
+func (c *ControllerV1) Delete(ctx context.Context, req *v1.DeleteReq) (res *v1.DeleteRes, err error) {
+	return nil, gerror.NewCode(gcode.CodeNotImplemented)
+}
+`)
+		t.AssertNil(err)
+		err = gfile.PutContents(extraFile, `package article
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+
+	"`+importPath+`"
+)
+
+func (c *ControllerV1) Archive(ctx context.Context, req *v1.ArchiveReq) (res *v1.ArchiveRes, err error) {
+	return nil, gerror.NewCode(gcode.CodeNotImplemented)
+}
+
+func (c *ControllerV1) Publish(ctx context.Context, req *v1.PublishReq) (res *v1.PublishRes, err error) {
+	return &v1.PublishRes{}, nil
+}
+`)
+		t.AssertNil(err)
+
+		// Only reported without prune.
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		t.Assert(gfile.Exists(deleteFile), true)
+		t.Assert(gstr.Contains(gfile.GetContents(extraFile), `) Archive(`), true)
+
+		// The stubs are removed, and the implemented method is kept.
+		in.Prune = true
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		t.Assert(gfile.Exists(deleteFile), false)
+		var extraContent = gfile.GetContents(extraFile)
+		t.Assert(gstr.Contains(extraContent, `) Archive(`), false)
+		t.Assert(gstr.Contains(extraContent, `) Publish(`), true)
+		t.Assert(gstr.Contains(extraContent, `gcode`), false)
+		// The controller methods of api definitions are kept.
+		t.Assert(gfile.Exists(ctrlPath+filepath.FromSlash("/article/article_v1_create.go")), true)
+		t.Assert(gfile.Exists(ctrlPath+filepath.FromSlash("/article/article_v1_get_list.go")), true)
+	})
+}
+
+func Test_Gen_Ctrl_Nested_Module(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-nested", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+			}
+			genUserApi    = apiFolder + filepath.FromSlash("/admin/user/user.go")
+			genProfileApi = apiFolder + filepath.FromSlash("/admin/user/profile/profile.go")
+			genArticleApi = apiFolder + filepath.FromSlash("/article/article.go")
+		)
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(genUserApi)
+		defer gfile.Remove(genProfileApi)
+		defer gfile.Remove(genArticleApi)
+
+		// The api interface file is generated in the nested module folder.
+		t.Assert(gfile.Exists(apiFolder+filepath.FromSlash("/admin/admin.go")), false)
+		// The sub package of api version folder is not an api module.
+		t.Assert(gfile.Exists(apiFolder+filepath.FromSlash("/admin/user/v1/v1.go")), false)
+		t.Assert(gstr.Contains(gfile.GetContents(genProfileApi), "package profile"), true)
+		t.Assert(gstr.Contains(
+			gfile.GetContents(genProfileApi),
+			`"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-nested/api/admin/user/profile/v1"`,
+		), true)
+		t.Assert(gstr.Contains(gfile.GetContents(genUserApi), "package user"), true)
+		t.Assert(gstr.Contains(
+			gfile.GetContents(genUserApi),
+			`"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-nested/api/admin/user/v1"`,
+		), true)
+
+		// The controller folder keeps the relative path of the nested module.
+		files, err := gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+		t.Assert(files, []string{
+			ctrlPath + filepath.FromSlash("/admin/user/profile/profile.go"),
+			ctrlPath + filepath.FromSlash("/admin/user/profile/profile_new.go"),
+			ctrlPath + filepath.FromSlash("/admin/user/profile/profile_v1_get_one.go"),
+			ctrlPath + filepath.FromSlash("/admin/user/user.go"),
+			ctrlPath + filepath.FromSlash("/admin/user/user_new.go"),
+			ctrlPath + filepath.FromSlash("/admin/user/user_v1_get_list.go"),
+			ctrlPath + filepath.FromSlash("/article/article.go"),
+			ctrlPath + filepath.FromSlash("/article/article_new.go"),
+			ctrlPath + filepath.FromSlash("/article/article_v1_get_list.go"),
+		})
+		content := gfile.GetContents(ctrlPath + filepath.FromSlash("/admin/user/user_v1_get_list.go"))
+		t.Assert(gstr.Contains(content, "package user"), true)
+		t.Assert(gstr.Contains(
+			content, `"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-nested/api/admin/user/v1"`,
+		), true)
+		t.Assert(gstr.Contains(
+			content, `) GetList(ctx context.Context, req *v1.GetListReq) (res *v1.GetListRes, err error)`,
+		), true)
+		content = gfile.GetContents(ctrlPath + filepath.FromSlash("/admin/user/profile/profile_v1_get_one.go"))
+		t.Assert(gstr.Contains(content, "package profile"), true)
+		t.Assert(gstr.Contains(
+			content, `"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-nested/api/admin/user/profile/v1"`,
+		), true)
+		t.Assert(gstr.Contains(
+			content, `) GetOne(ctx context.Context, req *v1.GetOneReq) (res *v1.GetOneRes, err error)`,
+		), true)
+
+		// The generated controllers of nested module are not orphaned ones of others.
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, genctrl.CGenCtrlInput{
+			SrcFolder: apiFolder,
+			DstFolder: ctrlPath,
+			Prune:     true,
+		})
+		t.AssertNil(err)
+		files2, err := gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+		t.Assert(files2, files)
+	})
+}
+
+func Test_Gen_Ctrl_Import_Alias(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath   = gfile.Temp(guid.S())
+			apiFolder  = gtest.DataPath("genctrl-import-alias", "api")
+			ctrlFolder = gtest.DataPath("genctrl-import-alias", "controller")
+			in         = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+				Prune:     true,
+			}
+			createFile = ctrlPath + filepath.FromSlash("/article/article_create.go")
+		)
+		err := gfile.CopyDir(ctrlFolder, ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+		createContent := gfile.GetContents(createFile)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(apiFolder + filepath.FromSlash("/article/article.go"))
+
+		// The controller method using aliased import is recognized as implemented,
+		// which is neither regenerated nor pruned as orphaned one.
+		files, err := gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+		t.Assert(files, []string{
+			ctrlPath + filepath.FromSlash("/article/article.go"),
+			createFile,
+			ctrlPath + filepath.FromSlash("/article/article_new.go"),
+			ctrlPath + filepath.FromSlash("/article/article_v1_get_list.go"),
+		})
+		t.Assert(gfile.GetContents(createFile), createContent)
+	})
+}
+
+func Test_Gen_Ctrl_DryRun(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath   = gfile.Temp(guid.S())
+			apiFolder  = gtest.DataPath("genctrl-import-alias", "api")
+			ctrlFolder = gtest.DataPath("genctrl-import-alias", "controller")
+			in         = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+				DryRun:    true,
+			}
+		)
+		err := gfile.CopyDir(ctrlFolder, ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+		files, err := gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+
+		// No files are written in dry-run mode.
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		t.Assert(gfile.Exists(apiFolder+filepath.FromSlash("/article/article.go")), false)
+		files2, err := gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+		t.Assert(files2, files)
+
+		// Nor in merge and force mode.
+		in.Merge = true
+		in.Force = true
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		t.Assert(gfile.Exists(apiFolder+filepath.FromSlash("/article/article.go")), false)
+		files2, err = gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+		t.Assert(files2, files)
+
+		// Dry-run mode is not supported in file watcher.
+		in.WatchFile = apiFolder + filepath.FromSlash("/article/v1/article.go")
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_Gen_Ctrl_Check(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath   = gfile.Temp(guid.S())
+			apiFolder  = gtest.DataPath("genctrl-import-alias", "api")
+			ctrlFolder = gtest.DataPath("genctrl-import-alias", "controller")
+			in         = genctrl.CGenCtrlInput{
+				SrcFolder: apiFolder,
+				DstFolder: ctrlPath,
+				Check:     true,
+			}
+		)
+		err := gfile.CopyDir(ctrlFolder, ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+		files, err := gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+
+		// The missing controller methods are reported without writing any files.
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNE(err, nil)
+		t.Assert(gstr.Contains(err.Error(), `missing controller method "GetList" of article.ControllerV1`), true)
+		t.Assert(gstr.Contains(err.Error(), `"Create"`), false)
+		t.Assert(gfile.Exists(apiFolder+filepath.FromSlash("/article/article.go")), false)
+		files2, err := gfile.ScanDir(ctrlPath, "*.go", true)
+		t.AssertNil(err)
+		t.Assert(files2, files)
+
+		// The controllers are up to date after generating.
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, genctrl.CGenCtrlInput{
+			SrcFolder: apiFolder,
+			DstFolder: ctrlPath,
+		})
+		t.AssertNil(err)
+		defer gfile.Remove(apiFolder + filepath.FromSlash("/article/article.go"))
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+	})
+}
+
+func Test_Gen_Ctrl_MethodNaming(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctrlPath  = gfile.Temp(guid.S())
+			apiFolder = gtest.DataPath("genctrl-template", "api")
+			in        = genctrl.CGenCtrlInput{
+				SrcFolder:     apiFolder,
+				DstFolder:     ctrlPath,
+				GroupByPrefix: true,
+				MethodNaming:  `{{UcFirst .Version}}{{.MethodName}}`,
+			}
+		)
+
+		err := gfile.Mkdir(ctrlPath)
+		t.AssertNil(err)
+		defer gfile.Remove(ctrlPath)
+
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		defer gfile.Remove(apiFolder + filepath.FromSlash("/article/article.go"))
+
+		// The controller files are still named by the method names of api definitions.
+		content := gfile.GetContents(ctrlPath + filepath.FromSlash("/article/article_v1_get_list.go"))
+		t.Assert(gstr.Contains(content, `func (c *ControllerV1) V1GetList(ctx context.Context, req *v1.GetListReq)`), true)
+		content = gfile.GetContents(apiFolder + filepath.FromSlash("/article/article.go"))
+		t.Assert(gstr.Contains(content, `V1GetList(ctx context.Context, req *v1.GetListReq) (res *v1.GetListRes, err error)`), true)
+		content = gfile.GetContents(ctrlPath + filepath.FromSlash("/article/article_router.go"))
+		t.Assert(gstr.Contains(content, `.V1GetList,`), true)
+
+		// The controller methods with custom names are recognized as implemented.
+		_, err = genctrl.CGenCtrl{}.Ctrl(ctx, in)
+		t.AssertNil(err)
+		content = gfile.GetContents(ctrlPath + filepath.FromSlash("/article/article_v1_get_list.go"))
+		t.Assert(gstr.Count(content, `V1GetList(`), 1)
+	})
+	// Invalid method naming template.
+	gtest.C(t, func(t *gtest.T) {
+		for _, methodNaming := range []string{`{{.MethodName`, `{{.Version}}{{.MethodName}}`, `{{.MethodName}}-{{.Version}}`} {
+			var (
+				ctrlPath  = gfile.Temp(guid.S())
+				apiFolder = gtest.DataPath("genctrl-template", "api")
+				in        = genctrl.CGenCtrlInput{
+					SrcFolder:    apiFolder,
+					DstFolder:    ctrlPath,
+					MethodNaming: methodNaming,
+				}
+			)
+			_, err := genctrl.CGenCtrl{}.Ctrl(ctx, in)
+			t.AssertNE(err, nil)
+			// No files are written if the method names are invalid.
+			t.Assert(gfile.Exists(ctrlPath), false)
+			t.Assert(gfile.Exists(apiFolder+filepath.FromSlash("/article/article.go")), false)
+		}
+	})
+}
@@ -8,12 +8,17 @@ package genctrl
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"text/template"
 
 	"github.com/gogf/gf/cmd/gf/v2/internal/utility/mlog"
 	"github.com/gogf/gf/v2/container/gset"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
 	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/gogf/gf/v2/util/gtag"
 )
@@ -32,15 +37,37 @@ gf gen ctrl
 	CGenCtrlBriefSdkStdVersion = `use standard version prefix for generated sdk request path`
 	CGenCtrlBriefSdkNoV1       = `do not add version suffix for interface module name if version is v1`
 	CGenCtrlBriefClear         = `auto delete generated and unimplemented controller go files if api definitions are missing`
+	CGenCtrlBriefPrune         = `remove the generated and unimplemented controller methods if api definitions are missing, which warns for the implemented ones instead`
 	CGenCtrlControllerMerge    = `generate all controller files into one go file by name of api definition source go file`
+	CGenCtrlBriefStrict        = `exit with error if any route conflict is found in api definitions`
+	CGenCtrlBriefGenValidation = `generate request validation for controller methods whose request has "v" tags`
+	CGenCtrlBriefReqSuffix     = `suffix of request struct names in api definitions, eg: Request, Input. default: Req`
+	CGenCtrlBriefForce         = `regenerate controller go files of all api definitions, which overwrites the existing controller methods and discards manual edits`
+	CGenCtrlBriefGroupByPrefix = `also group the routes by their shared route prefix in the generated router go file, eg: /user`
+	CGenCtrlBriefResMissing    = `how to handle the api definitions whose response struct is missing: "skip" skips them with warnings, "strict" exits with error, "lenient" generates the controller methods with TODO placeholder. default: skip`
+	CGenCtrlBriefCheck         = `check that all api definitions have their controller methods generated without writing any files, which exits with error listing the missing ones and warns for the orphaned ones`
+	CGenCtrlBriefDryRun        = `print the controller files and methods to be generated or skipped, and the content of new methods, without writing any files`
+	CGenCtrlBriefTemplate      = `custom Go text/template file path for the body of generated controller methods, which receives fields Module, Version, MethodName, Import, Path, HTTPMethod, Summary and Tags of the api definition`
+	CGenCtrlBriefMethodNaming  = `custom Go text/template for the names of generated controller methods, which receives fields Module, Version and MethodName of the api definition, and supports functions UcFirst and CaseCamel, eg: {{UcFirst .Version}}{{.MethodName}}. default: {{.MethodName}}`
 )
 
 const (
-	PatternCtrlDefinition = `func\s+\(.+?\)\s+\w+\(.+?\*(\w+)\.(\w+)Req\)\s+\(.+?\*(\w+)\.(\w+)Res,\s+\w+\s+error\)\s+{`
+	// PatternCtrlDefinition is the pattern of controller method definitions,
+	// in which "{ReqSuffix}" is replaced with the quoted suffix of request struct names.
+	PatternCtrlDefinition = `func\s+\(.+?\)\s+(\w+)\(.+?\*(\w+)\.(\w+){ReqSuffix}\)\s+\(.*?\*(\w+)\.(\w+)Res,\s*(?:\w+\s+)?error\)\s+{`
+	// PatternApiVersion is the pattern of api version folder names, eg: v1, v2, v1beta.
+	PatternApiVersion = `^v\d+`
 )
 
 const (
 	genCtrlFileLockSeconds = 10
+	defaultReqSuffix       = "Req"
+)
+
+const (
+	resMissingSkip    = "skip"
+	resMissingStrict  = "strict"
+	resMissingLenient = "lenient"
 )
 
 func init() {
@@ -56,7 +83,18 @@ func init() {
 		`CGenCtrlBriefSdkStdVersion`: CGenCtrlBriefSdkStdVersion,
 		`CGenCtrlBriefSdkNoV1`:       CGenCtrlBriefSdkNoV1,
 		`CGenCtrlBriefClear`:         CGenCtrlBriefClear,
+		`CGenCtrlBriefPrune`:         CGenCtrlBriefPrune,
 		`CGenCtrlControllerMerge`:    CGenCtrlControllerMerge,
+		`CGenCtrlBriefStrict`:        CGenCtrlBriefStrict,
+		`CGenCtrlBriefGenValidation`: CGenCtrlBriefGenValidation,
+		`CGenCtrlBriefReqSuffix`:     CGenCtrlBriefReqSuffix,
+		`CGenCtrlBriefForce`:         CGenCtrlBriefForce,
+		`CGenCtrlBriefGroupByPrefix`: CGenCtrlBriefGroupByPrefix,
+		`CGenCtrlBriefResMissing`:    CGenCtrlBriefResMissing,
+		`CGenCtrlBriefCheck`:         CGenCtrlBriefCheck,
+		`CGenCtrlBriefDryRun`:        CGenCtrlBriefDryRun,
+		`CGenCtrlBriefTemplate`:      CGenCtrlBriefTemplate,
+		`CGenCtrlBriefMethodNaming`:  CGenCtrlBriefMethodNaming,
 	})
 }
 
@@ -71,15 +109,53 @@ type (
 		SdkStdVersion bool   `short:"v" name:"sdkStdVersion" brief:"{CGenCtrlBriefSdkStdVersion}" orphan:"true"`
 		SdkNoV1       bool   `short:"n" name:"sdkNoV1"       brief:"{CGenCtrlBriefSdkNoV1}" orphan:"true"`
 		Clear         bool   `short:"c" name:"clear"         brief:"{CGenCtrlBriefClear}" orphan:"true"`
+		Prune         bool   `short:"u" name:"prune"         brief:"{CGenCtrlBriefPrune}" orphan:"true"`
 		Merge         bool   `short:"m" name:"merge"         brief:"{CGenCtrlControllerMerge}" orphan:"true"`
+		Strict        bool   `short:"t" name:"strict"        brief:"{CGenCtrlBriefStrict}" orphan:"true"`
+		GenValidation bool   `short:"l" name:"genValidation" brief:"{CGenCtrlBriefGenValidation}" orphan:"true"`
+		ReqSuffix     string `short:"r" name:"reqSuffix"     brief:"{CGenCtrlBriefReqSuffix}" d:"Req"`
+		Force         bool   `short:"f" name:"force"         brief:"{CGenCtrlBriefForce}" orphan:"true"`
+		GroupByPrefix bool   `short:"g" name:"groupByPrefix" brief:"{CGenCtrlBriefGroupByPrefix}" orphan:"true"`
+		ResMissing    string `short:"e" name:"resMissing"    brief:"{CGenCtrlBriefResMissing}" d:"skip"`
+		Check         bool   `short:"x" name:"check"         brief:"{CGenCtrlBriefCheck}" orphan:"true"`
+		DryRun        bool   `short:"y" name:"dryRun"        brief:"{CGenCtrlBriefDryRun}" orphan:"true"`
+		Template      string `short:"p" name:"template"      brief:"{CGenCtrlBriefTemplate}"`
+		MethodNaming  string `short:"a" name:"methodNaming"  brief:"{CGenCtrlBriefMethodNaming}"`
 	}
 	CGenCtrlOutput struct{}
 )
 
 func (c CGenCtrl) Ctrl(ctx context.Context, in CGenCtrlInput) (out *CGenCtrlOutput, err error) {
+	// the custom template is validated before any files are written.
+	methodTemplate, err := c.parseMethodTemplate(in.Template)
+	if err != nil {
+		return nil, err
+	}
+	namingTemplate, err := c.parseMethodNaming(in.MethodNaming)
+	if err != nil {
+		return nil, err
+	}
+	if in.ReqSuffix == "" {
+		in.ReqSuffix = defaultReqSuffix
+	}
+	switch in.ResMissing {
+	case "":
+		in.ResMissing = resMissingSkip
+	case resMissingSkip, resMissingStrict, resMissingLenient:
+	default:
+		return nil, gerror.Newf(
+			`invalid resMissing "%s", it should be one of: %s, %s, %s`,
+			in.ResMissing, resMissingSkip, resMissingStrict, resMissingLenient,
+		)
+	}
 	if in.WatchFile != "" {
+		if in.DryRun || in.Check {
+			return nil, gerror.New(`dryRun and check are not supported in file watcher`)
+		}
 		err = c.generateByWatchFile(
-			in.WatchFile, in.SdkPath, in.SdkStdVersion, in.SdkNoV1, in.Clear, in.Merge,
+			in.WatchFile, in.SdkPath, in.ReqSuffix, in.ResMissing,
+			in.SdkStdVersion, in.SdkNoV1, in.Clear, in.Prune, in.Merge, in.GenValidation, in.Force, in.GroupByPrefix,
+			methodTemplate, namingTemplate,
 		)
 		mlog.Print(`done!`)
 		return
@@ -88,34 +164,106 @@ func (c CGenCtrl) Ctrl(ctx context.Context, in CGenCtrlInput) (out *CGenCtrlOutp
 	if !gfile.Exists(in.SrcFolder) {
 		mlog.Fatalf(`source folder path "%s" does not exist`, in.SrcFolder)
 	}
-	// retrieve all api modules.
-	apiModuleFolderPaths, err := gfile.ScanDir(in.SrcFolder, "*", false)
+	// retrieve all api modules, which might be nested in grouping folders.
+	apiModuleFolderPaths, err := c.getApiModuleFolderPaths(in.SrcFolder)
 	if err != nil {
 		return nil, err
 	}
+	// check route conflicts across all api modules.
+	var apiItemsInSrc []apiItem
 	for _, apiModuleFolderPath := range apiModuleFolderPaths {
-		if !gfile.IsDir(apiModuleFolderPath) {
+		items, err := c.getApiItemsInSrc(apiModuleFolderPath, in.ReqSuffix)
+		if err != nil {
+			return nil, err
+		}
+		// the names of controller methods are validated before any files are written.
+		if items, err = c.applyMethodNaming(items, namingTemplate); err != nil {
+			return nil, err
+		}
+		apiItemsInSrc = append(apiItemsInSrc, items...)
+	}
+	// check controller method conflicts across all api modules before any files are written.
+	if err = newMethodConflictChecker().Check(apiItemsInSrc); err != nil {
+		return nil, err
+	}
+	if err = newRouteConflictChecker().Check(apiItemsInSrc, in.Strict); err != nil {
+		return nil, err
+	}
+	// the missing response structs of all api modules are reported before any files are written.
+	if in.ResMissing == resMissingStrict {
+		if _, err = c.filterResLessApiItems(apiItemsInSrc, in.ResMissing); err != nil {
+			return nil, err
+		}
+	}
+	var missingCtrlMethods []string
+	for _, apiModuleFolderPath := range apiModuleFolderPaths {
+		// generate go files by api module,
+		// the controller folder of nested api module keeps the same relative path, eg: admin/user.
+		modulePath, err := filepath.Rel(in.SrcFolder, apiModuleFolderPath)
+		if err != nil {
+			return nil, err
+		}
+		var dstModuleFolderPath = gfile.Join(in.DstFolder, modulePath)
+		// only check the controller methods missing for api definitions in check mode.
+		if in.Check {
+			missing, err := c.checkByModule(apiModuleFolderPath, dstModuleFolderPath, in.ReqSuffix, in.ResMissing)
+			if err != nil {
+				return nil, err
+			}
+			missingCtrlMethods = append(missingCtrlMethods, missing...)
+			continue
+		}
+		// only print the planned file operations in dry-run mode.
+		if in.DryRun {
+			err = c.planByModule(
+				apiModuleFolderPath, dstModuleFolderPath, in.ReqSuffix, in.ResMissing,
+				in.Merge, in.GenValidation, in.Force, methodTemplate, namingTemplate,
+			)
+			if err != nil {
+				return nil, err
+			}
 			continue
 		}
-		// generate go files by api module.
-		var (
-			module              = gfile.Basename(apiModuleFolderPath)
-			dstModuleFolderPath = gfile.Join(in.DstFolder, module)
-		)
 		err = c.generateByModule(
-			apiModuleFolderPath, dstModuleFolderPath, in.SdkPath,
-			in.SdkStdVersion, in.SdkNoV1, in.Clear, in.Merge,
+			apiModuleFolderPath, dstModuleFolderPath, in.SdkPath, in.ReqSuffix, in.ResMissing,
+			in.SdkStdVersion, in.SdkNoV1, in.Clear, in.Prune, in.Merge, in.GenValidation, in.Force, in.GroupByPrefix,
+			methodTemplate, namingTemplate,
 		)
 		if err != nil {
 			return nil, err
 		}
 	}
+	if len(missingCtrlMethods) > 0 {
+		return nil, gerror.Newf(
+			"controllers are not up to date, please generate them:\n%s", gstr.Join(missingCtrlMethods, "\n"),
+		)
+	}
 
 	mlog.Print(`done!`)
 	return
 }
 
-func (c CGenCtrl) generateByWatchFile(watchFile, sdkPath string, sdkStdVersion, sdkNoV1, clear, merge bool) (err error) {
+// parseMethodTemplate reads and parses the custom template file for the body of controller methods.
+// It returns nil template if `templatePath` is empty, which uses the built-in template.
+func (c CGenCtrl) parseMethodTemplate(templatePath string) (*template.Template, error) {
+	if templatePath == "" {
+		return nil, nil
+	}
+	if !gfile.IsFile(templatePath) {
+		return nil, gerror.Newf(`template file "%s" does not exist`, templatePath)
+	}
+	methodTemplate, err := template.New(gfile.Basename(templatePath)).Parse(gfile.GetContents(templatePath))
+	if err != nil {
+		return nil, gerror.Wrapf(err, `parse template file "%s" failed`, templatePath)
+	}
+	return methodTemplate, nil
+}
+
+func (c CGenCtrl) generateByWatchFile(
+	watchFile, sdkPath, reqSuffix, resMissing string,
+	sdkStdVersion, sdkNoV1, clear, prune, merge, genValidation, force, groupByPrefix bool,
+	methodTemplate, namingTemplate *template.Template,
+) (err error) {
 	// File lock to avoid multiple processes.
 	var (
 		flockFilePath = gfile.Temp("gf.cli.gen.service.lock")
@@ -132,18 +280,26 @@ func (c CGenCtrl) generateByWatchFile(watchFile, sdkPath string, sdkStdVersion,
 	_ = gfile.PutContents(flockFilePath, gtime.TimestampStr())
 
 	// check this updated file is an api file.
-	// watch file should be in standard goframe project structure.
+	// watch file should be in standard goframe project structure,
+	// in which the api module might be nested in grouping folders under "api".
 	var (
 		apiVersionPath      = gfile.Dir(watchFile)
 		apiModuleFolderPath = gfile.Dir(apiVersionPath)
-		shouldBeNameOfAPi   = gfile.Basename(gfile.Dir(apiModuleFolderPath))
+		apiFolderPath       = gfile.Dir(apiModuleFolderPath)
 	)
-	if shouldBeNameOfAPi != "api" {
+	if !c.isApiVersionFolder(apiVersionPath) {
 		return nil
 	}
+	for gfile.Basename(apiFolderPath) != "api" {
+		// it stops searching at project root folder or file system root folder.
+		if gfile.Exists(gfile.Join(apiFolderPath, "go.mod")) || gfile.Dir(apiFolderPath) == apiFolderPath {
+			return nil
+		}
+		apiFolderPath = gfile.Dir(apiFolderPath)
+	}
 	// watch file should have api definitions.
 	if gfile.Exists(watchFile) {
-		structsInfo, err := c.getStructsNameInSrc(watchFile)
+		structsInfo, err := c.getStructsInfoInSrc(watchFile, reqSuffix)
 		if err != nil {
 			return err
 		}
@@ -152,27 +308,43 @@ func (c CGenCtrl) generateByWatchFile(watchFile, sdkPath string, sdkStdVersion,
 		}
 	}
 
+	modulePath, err := filepath.Rel(apiFolderPath, apiModuleFolderPath)
+	if err != nil {
+		return err
+	}
 	var (
-		projectRootPath     = gfile.Dir(gfile.Dir(apiModuleFolderPath))
-		module              = gfile.Basename(apiModuleFolderPath)
-		dstModuleFolderPath = gfile.Join(projectRootPath, "internal", "controller", module)
+		projectRootPath     = gfile.Dir(apiFolderPath)
+		dstModuleFolderPath = gfile.Join(projectRootPath, "internal", "controller", modulePath)
 	)
 	return c.generateByModule(
-		apiModuleFolderPath, dstModuleFolderPath, sdkPath, sdkStdVersion, sdkNoV1, clear, merge,
+		apiModuleFolderPath, dstModuleFolderPath, sdkPath, reqSuffix, resMissing,
+		sdkStdVersion, sdkNoV1, clear, prune, merge, genValidation, force, groupByPrefix,
+		methodTemplate, namingTemplate,
 	)
 }
 
 // parseApiModule parses certain api and generate associated go files by certain module, not all api modules.
 func (c CGenCtrl) generateByModule(
-	apiModuleFolderPath, dstModuleFolderPath, sdkPath string,
-	sdkStdVersion, sdkNoV1, clear, merge bool,
+	apiModuleFolderPath, dstModuleFolderPath, sdkPath, reqSuffix, resMissing string,
+	sdkStdVersion, sdkNoV1, clear, prune, merge, genValidation, force, groupByPrefix bool,
+	methodTemplate, namingTemplate *template.Template,
 ) (err error) {
 	// parse src and dst folder go files.
-	apiItemsInSrc, err := c.getApiItemsInSrc(apiModuleFolderPath)
+	apiItemsInSrc, err := c.getApiItemsInSrc(apiModuleFolderPath, reqSuffix)
+	if err != nil {
+		return err
+	}
+	if err = newMethodConflictChecker().Check(apiItemsInSrc); err != nil {
+		return err
+	}
+	if apiItemsInSrc, err = c.applyMethodNaming(apiItemsInSrc, namingTemplate); err != nil {
+		return err
+	}
+	apiItemsInSrc, err = c.filterResLessApiItems(apiItemsInSrc, resMissing)
 	if err != nil {
 		return err
 	}
-	apiItemsInDst, err := c.getApiItemsInDst(dstModuleFolderPath)
+	apiItemsInDst, err := c.getApiItemsInDst(dstModuleFolderPath, reqSuffix)
 	if err != nil {
 		return err
 	}
@@ -183,48 +355,47 @@ func (c CGenCtrl) generateByModule(
 	}
 
 	// generate controller go files.
-	// api filtering for already implemented api controllers.
-	var (
-		alreadyImplementedCtrlSet = gset.NewStrSet()
-		toBeImplementedApiItems   = make([]apiItem, 0)
-	)
-	for _, item := range apiItemsInDst {
-		alreadyImplementedCtrlSet.Add(item.String())
-	}
-	for _, item := range apiItemsInSrc {
-		if alreadyImplementedCtrlSet.Contains(item.String()) {
-			continue
-		}
-		toBeImplementedApiItems = append(toBeImplementedApiItems, item)
-	}
+	toBeImplementedApiItems, _ := c.getToBeImplementedApiItems(apiItemsInSrc, apiItemsInDst, force)
 	if len(toBeImplementedApiItems) > 0 {
-		err = newControllerGenerator().Generate(dstModuleFolderPath, toBeImplementedApiItems, merge)
+		err = newControllerGenerator(methodTemplate).Generate(
+			dstModuleFolderPath, toBeImplementedApiItems, merge, genValidation, force,
+		)
 		if err != nil {
 			return
 		}
 	}
 
-	// delete unimplemented controllers if api definitions are missing.
-	if clear {
-		var (
-			apiDefinitionSet    = gset.NewStrSet()
-			extraApiItemsInCtrl = make([]apiItem, 0)
-		)
-		for _, item := range apiItemsInSrc {
-			apiDefinitionSet.Add(item.String())
-		}
-		for _, item := range apiItemsInDst {
-			if apiDefinitionSet.Contains(item.String()) {
-				continue
-			}
-			extraApiItemsInCtrl = append(extraApiItemsInCtrl, item)
+	// generate router go file grouping routes by middleware annotations.
+	if err = newRouterGenerator().Generate(dstModuleFolderPath, apiItemsInSrc, groupByPrefix); err != nil {
+		return
+	}
+
+	// controllers whose api definitions are missing.
+	var (
+		apiDefinitionSet    = gset.NewStrSet()
+		extraApiItemsInCtrl = make([]apiItem, 0)
+	)
+	for _, item := range apiItemsInSrc {
+		apiDefinitionSet.Add(item.String())
+	}
+	for _, item := range apiItemsInDst {
+		if apiDefinitionSet.Contains(item.String()) {
+			continue
 		}
-		if len(extraApiItemsInCtrl) > 0 {
+		extraApiItemsInCtrl = append(extraApiItemsInCtrl, item)
+	}
+	if len(extraApiItemsInCtrl) > 0 {
+		// delete unimplemented controller files if api definitions are missing.
+		if clear {
 			err = newControllerClearer().Clear(dstModuleFolderPath, extraApiItemsInCtrl)
 			if err != nil {
 				return
 			}
 		}
+		// report the orphaned controller methods, and remove the unimplemented ones if `prune`.
+		if err = newControllerPruner().Prune(extraApiItemsInCtrl, prune); err != nil {
+			return
+		}
 	}
 
 	// generate sdk go files.
@@ -235,3 +406,125 @@ func (c CGenCtrl) generateByModule(
 	}
 	return
 }
+
+// filterResLessApiItems handles the api items whose response struct is not defined,
+// as the generated controller referencing the undefined response struct cannot be compiled.
+// It removes and warns these items in "skip" mode, returns error listing all of them in "strict" mode,
+// or warns and keeps them in "lenient" mode, whose controller methods are generated with TODO placeholder.
+func (c CGenCtrl) filterResLessApiItems(items []apiItem, resMissing string) ([]apiItem, error) {
+	var (
+		filteredItems = make([]apiItem, 0, len(items))
+		missingItems  = make([]string, 0)
+	)
+	for _, item := range items {
+		if item.HasResponse {
+			filteredItems = append(filteredItems, item)
+			continue
+		}
+		switch resMissing {
+		case resMissingStrict:
+			missingItems = append(missingItems, fmt.Sprintf(
+				`"%sRes" of api "%s%s" in "%s"`, item.MethodName, item.MethodName, item.ReqSuffix, item.FilePath,
+			))
+		case resMissingLenient:
+			mlog.Printf(
+				`generate api "%s%s" in "%s" with TODO placeholder, as its response struct "%sRes" is not defined`,
+				item.MethodName, item.ReqSuffix, item.FilePath, item.MethodName,
+			)
+			filteredItems = append(filteredItems, item)
+		default:
+			mlog.Printf(
+				`skip api "%s%s" in "%s", as its response struct "%sRes" is not defined`,
+				item.MethodName, item.ReqSuffix, item.FilePath, item.MethodName,
+			)
+		}
+	}
+	if len(missingItems) > 0 {
+		return nil, gerror.Newf(
+			"response structs are not defined:\n%s", gstr.Join(missingItems, "\n"),
+		)
+	}
+	return filteredItems, nil
+}
+
+// planByModule prints the controller files and methods to be generated or skipped of certain module
+// without writing any files, which is used in dry-run mode.
+func (c CGenCtrl) planByModule(
+	apiModuleFolderPath, dstModuleFolderPath, reqSuffix, resMissing string,
+	merge, genValidation, force bool, methodTemplate, namingTemplate *template.Template,
+) (err error) {
+	apiItemsInSrc, err := c.getApiItemsInSrc(apiModuleFolderPath, reqSuffix)
+	if err != nil {
+		return err
+	}
+	if err = newMethodConflictChecker().Check(apiItemsInSrc); err != nil {
+		return err
+	}
+	if apiItemsInSrc, err = c.applyMethodNaming(apiItemsInSrc, namingTemplate); err != nil {
+		return err
+	}
+	apiItemsInSrc, err = c.filterResLessApiItems(apiItemsInSrc, resMissing)
+	if err != nil {
+		return err
+	}
+	apiItemsInDst, err := c.getApiItemsInDst(dstModuleFolderPath, reqSuffix)
+	if err != nil {
+		return err
+	}
+	toBeImplementedApiItems, implementedApiItems := c.getToBeImplementedApiItems(apiItemsInSrc, apiItemsInDst, force)
+	return newControllerGenerator(methodTemplate).Plan(
+		dstModuleFolderPath, toBeImplementedApiItems, implementedApiItems, merge, genValidation, force,
+	)
+}
+
+// checkByModule returns the descriptions of the controller methods missing for the api definitions
+// of certain module without writing any files, which is used in check mode.
+func (c CGenCtrl) checkByModule(
+	apiModuleFolderPath, dstModuleFolderPath, reqSuffix, resMissing string,
+) (missing []string, err error) {
+	apiItemsInSrc, err := c.getApiItemsInSrc(apiModuleFolderPath, reqSuffix)
+	if err != nil {
+		return nil, err
+	}
+	// the api definitions skipped in generating are not expected to have controller methods.
+	apiItemsInSrc, err = c.filterResLessApiItems(apiItemsInSrc, resMissing)
+	if err != nil {
+		return nil, err
+	}
+	apiItemsInDst, err := c.getApiItemsInDst(dstModuleFolderPath, reqSuffix)
+	if err != nil {
+		return nil, err
+	}
+	return newDriftChecker().Check(apiItemsInSrc, apiItemsInDst), nil
+}
+
+// getToBeImplementedApiItems filters out the api items in `apiItemsInSrc` whose controller methods
+// are already implemented, which are never rewritten to protect the hand-written controller code,
+// unless `force` is true. It also returns the api items of the implemented controller methods in dst.
+func (c CGenCtrl) getToBeImplementedApiItems(
+	apiItemsInSrc, apiItemsInDst []apiItem, force bool,
+) (toBeImplementedApiItems, implementedApiItems []apiItem) {
+	var (
+		apiDefinitionSet          = gset.NewStrSet()
+		alreadyImplementedCtrlSet = gset.NewStrSet()
+	)
+	for _, item := range apiItemsInSrc {
+		apiDefinitionSet.Add(item.String())
+	}
+	if !force {
+		for _, item := range apiItemsInDst {
+			if apiDefinitionSet.Contains(item.String()) {
+				alreadyImplementedCtrlSet.Add(item.String())
+				implementedApiItems = append(implementedApiItems, item)
+			}
+		}
+	}
+	toBeImplementedApiItems = make([]apiItem, 0)
+	for _, item := range apiItemsInSrc {
+		if alreadyImplementedCtrlSet.Contains(item.String()) {
+			continue
+		}
+		toBeImplementedApiItems = append(toBeImplementedApiItems, item)
+	}
+	return
+}
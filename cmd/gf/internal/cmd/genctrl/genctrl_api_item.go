@@ -9,11 +9,31 @@ package genctrl
 import "github.com/gogf/gf/v2/text/gstr"
 
 type apiItem struct {
-	Import     string `eg:"demo.com/api/user/v1"`
-	FileName   string `eg:"user"`
-	Module     string `eg:"user"`
-	Version    string `eg:"v1"`
-	MethodName string `eg:"GetList"`
+	Import        string `eg:"demo.com/api/user/v1"`
+	FileName      string `eg:"user"`
+	FilePath      string `eg:"api/user/v1/user.go"` // file path of api definition, or of controller for items parsed from controller.
+	Module        string `eg:"user"`
+	Version       string `eg:"v1"`
+	MethodName    string `eg:"GetList"`
+	FuncName      string `eg:"GetList"`    // name of controller method, which is MethodName rendered by method naming template.
+	ReqSuffix     string `eg:"Req"`        // suffix of request struct name, the request struct name is MethodName + ReqSuffix.
+	Path          string `eg:"/user/list"` // route path from g.Meta, only available for items parsed from api source.
+	HTTPMethod    string `eg:"get"`        // route method from g.Meta, only available for items parsed from api source.
+	HasValidation bool   `eg:"true"`       // request has "v" validation tags, only available for items parsed from api source.
+	Middleware    string `eg:"auth"`       // middleware annotation from g.Meta, only available for items parsed from api source.
+	Summary       string `eg:"Get list"`   // OpenAPI summary from g.Meta, only available for items parsed from api source.
+	Tags          string `eg:"User"`       // OpenAPI tags from g.Meta, only available for items parsed from api source.
+	HasResponse   bool   `eg:"true"`       // response struct is defined, only available for items parsed from api source.
+}
+
+// RoutePrefix returns the first segment of the route path from g.Meta, eg: /user,
+// or "/" if the route path is empty.
+func (a apiItem) RoutePrefix() string {
+	var segments = gstr.SplitAndTrim(a.Path, "/")
+	if len(segments) == 0 {
+		return "/"
+	}
+	return "/" + segments[0]
 }
 
 func (a apiItem) String() string {
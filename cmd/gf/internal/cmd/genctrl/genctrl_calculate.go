@@ -12,14 +12,52 @@ import (
 	"go/parser"
 	"go/printer"
 	"go/token"
+	"path/filepath"
+	"reflect"
+	"strconv"
 
 	"github.com/gogf/gf/cmd/gf/v2/internal/utility/utils"
+	"github.com/gogf/gf/v2/container/garray"
+	"github.com/gogf/gf/v2/container/gset"
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/gogf/gf/v2/text/gregex"
 	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gtag"
 )
 
-func (c CGenCtrl) getApiItemsInSrc(apiModuleFolderPath string) (items []apiItem, err error) {
+// getApiModuleFolderPaths retrieves and returns the api module folder paths under `srcFolder` recursively,
+// which are the parent folders of api version folders. The api version folders are the folders
+// containing go files whose names match PatternApiVersion, so that api modules can be nested in
+// grouping folders of any depth, eg: api/user/v1 and api/admin/user/v1.
+func (c CGenCtrl) getApiModuleFolderPaths(srcFolder string) ([]string, error) {
+	paths, err := gfile.ScanDir(srcFolder, "*.go", true)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		srcFolderPath     = filepath.Clean(srcFolder)
+		moduleFolderPaths = garray.NewSortedStrArray().SetUnique(true)
+	)
+	for _, path := range paths {
+		var (
+			versionFolderPath = filepath.Dir(path)
+			moduleFolderPath  = filepath.Dir(versionFolderPath)
+		)
+		if !c.isApiVersionFolder(versionFolderPath) || moduleFolderPath == srcFolderPath {
+			continue
+		}
+		moduleFolderPaths.Add(moduleFolderPath)
+	}
+	return moduleFolderPaths.Slice(), nil
+}
+
+// isApiVersionFolder checks and returns whether `folderPath` is an api version folder,
+// whose name matches PatternApiVersion.
+func (c CGenCtrl) isApiVersionFolder(folderPath string) bool {
+	return gregex.IsMatchString(PatternApiVersion, gfile.Basename(folderPath))
+}
+
+func (c CGenCtrl) getApiItemsInSrc(apiModuleFolderPath, reqSuffix string) (items []apiItem, err error) {
 	var importPath string
 	// The second level folders: versions.
 	apiVersionFolderPaths, err := gfile.ScanDir(apiModuleFolderPath, "*", false)
@@ -27,7 +65,8 @@ func (c CGenCtrl) getApiItemsInSrc(apiModuleFolderPath string) (items []apiItem,
 		return nil, err
 	}
 	for _, apiVersionFolderPath := range apiVersionFolderPaths {
-		if !gfile.IsDir(apiVersionFolderPath) {
+		// the sub folders that are not versions might be nested api modules.
+		if !gfile.IsDir(apiVersionFolderPath) || !c.isApiVersionFolder(apiVersionFolderPath) {
 			continue
 		}
 		// The second level folders: versions.
@@ -36,23 +75,38 @@ func (c CGenCtrl) getApiItemsInSrc(apiModuleFolderPath string) (items []apiItem,
 			return nil, err
 		}
 		importPath = utils.GetImportPath(apiVersionFolderPath)
+		// all type names declared in the api version package, for checking the response structs.
+		typeNameSet, err := c.getTypeNamesInSrc(apiFileFolderPaths)
+		if err != nil {
+			return nil, err
+		}
 		for _, apiFileFolderPath := range apiFileFolderPaths {
 			if gfile.IsDir(apiFileFolderPath) {
 				continue
 			}
-			structsInfo, err := c.getStructsNameInSrc(apiFileFolderPath)
+			structsInfo, err := c.getStructsInfoInSrc(apiFileFolderPath, reqSuffix)
 			if err != nil {
 				return nil, err
 			}
-			for _, methodName := range structsInfo {
-				// remove end "Req"
-				methodName = gstr.TrimRightStr(methodName, "Req", 1)
+			for _, structInfo := range structsInfo {
+				// remove end request suffix, eg: "Req".
+				methodName := gstr.TrimRightStr(structInfo.Name, reqSuffix, 1)
 				item := apiItem{
-					Import:     gstr.Trim(importPath, `"`),
-					FileName:   gfile.Name(apiFileFolderPath),
-					Module:     gfile.Basename(apiModuleFolderPath),
-					Version:    gfile.Basename(apiVersionFolderPath),
-					MethodName: methodName,
+					Import:        gstr.Trim(importPath, `"`),
+					FileName:      gfile.Name(apiFileFolderPath),
+					FilePath:      apiFileFolderPath,
+					Module:        gfile.Basename(apiModuleFolderPath),
+					Version:       gfile.Basename(apiVersionFolderPath),
+					MethodName:    methodName,
+					FuncName:      methodName,
+					ReqSuffix:     reqSuffix,
+					Path:          structInfo.Meta.Get("path"),
+					HTTPMethod:    structInfo.Meta.Get("method"),
+					HasValidation: structInfo.HasValidation,
+					Middleware:    structInfo.Meta.Get("middleware"),
+					Summary:       c.getMetaValue(structInfo.Meta, gtag.Summary, gtag.SummaryShort, gtag.SummaryShort2),
+					Tags:          structInfo.Meta.Get("tags"),
+					HasResponse:   typeNameSet.Contains(methodName + "Res"),
 				}
 				items = append(items, item)
 			}
@@ -61,50 +115,32 @@ func (c CGenCtrl) getApiItemsInSrc(apiModuleFolderPath string) (items []apiItem,
 	return
 }
 
-func (c CGenCtrl) getApiItemsInDst(dstFolder string) (items []apiItem, err error) {
+func (c CGenCtrl) getApiItemsInDst(dstFolder, reqSuffix string) (items []apiItem, err error) {
 	if !gfile.Exists(dstFolder) {
 		return nil, nil
 	}
-	type importItem struct {
-		Path  string
-		Alias string
-	}
-	filePaths, err := gfile.ScanDir(dstFolder, "*.go", true)
+	// the controller files of nested api modules in sub folders are not of current module.
+	filePaths, err := gfile.ScanDir(dstFolder, "*.go", false)
 	if err != nil {
 		return nil, err
 	}
 	for _, filePath := range filePaths {
 		var (
-			array       []string
 			importItems []importItem
-			importLines []string
 			module      = gfile.Basename(gfile.Dir(filePath))
 		)
-		importLines, err = c.getImportsInDst(filePath)
+		importItems, err = c.getImportsInDst(filePath)
 		if err != nil {
 			return nil, err
 		}
-
-		// retrieve all imports.
-		for _, importLine := range importLines {
-			array = gstr.SplitAndTrim(importLine, " ")
-			if len(array) == 2 {
-				importItems = append(importItems, importItem{
-					Path:  gstr.Trim(array[1], `"`),
-					Alias: array[0],
-				})
-			} else {
-				importItems = append(importItems, importItem{
-					Path: gstr.Trim(array[0], `"`),
-				})
-			}
-		}
 		// retrieve all api usages.
 		// retrieve it without using AST, but use regular expressions to retrieve.
 		// It's because the api definition is simple and regular.
 		// Use regular expressions to get better performance.
 		fileContent := gfile.GetContents(filePath)
-		matches, err := gregex.MatchAllString(PatternCtrlDefinition, fileContent)
+		matches, err := gregex.MatchAllString(
+			gstr.Replace(PatternCtrlDefinition, "{ReqSuffix}", gregex.Quote(reqSuffix)), fileContent,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -112,8 +148,9 @@ func (c CGenCtrl) getApiItemsInDst(dstFolder string) (items []apiItem, err error
 			// try to find the import path of the api.
 			var (
 				importPath string
-				version    = match[1]
-				methodName = match[2] // not the function name, but the method name in api definition.
+				funcName   = match[1]
+				version    = match[2]
+				methodName = match[3] // not the function name, but the method name in api definition.
 			)
 			for _, item := range importItems {
 				if item.Alias != "" {
@@ -129,10 +166,13 @@ func (c CGenCtrl) getApiItemsInDst(dstFolder string) (items []apiItem, err error
 				}
 			}
 			item := apiItem{
-				Import:     gstr.Trim(importPath, `"`),
+				Import:     importPath,
 				Module:     module,
 				Version:    gfile.Basename(importPath),
+				FilePath:   filePath,
 				MethodName: methodName,
+				FuncName:   funcName,
+				ReqSuffix:  reqSuffix,
 			}
 			items = append(items, item)
 		}
@@ -140,9 +180,27 @@ func (c CGenCtrl) getApiItemsInDst(dstFolder string) (items []apiItem, err error
 	return
 }
 
-// getStructsNameInSrc retrieves all struct names
-// that end in "Req" and have "g.Meta" in their body.
-func (c CGenCtrl) getStructsNameInSrc(filePath string) (structsName []string, err error) {
+// getMetaValue returns the value of the first non-empty tag of `names` in g.Meta tag `meta`,
+// which is used for the tags having short names, eg: summary and sm.
+func (c CGenCtrl) getMetaValue(meta reflect.StructTag, names ...string) string {
+	for _, name := range names {
+		if value := meta.Get(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// apiStructInfo is the request struct information parsed from api definition source file.
+type apiStructInfo struct {
+	Name          string            // Name of the request struct, eg: GetListReq.
+	Meta          reflect.StructTag // Tag of the g.Meta field, eg: path:"/user/list" method:"get".
+	HasValidation bool              // Whether any field of the request struct has "v" validation tag.
+}
+
+// getStructsInfoInSrc retrieves all structs information
+// whose name end in request suffix `reqSuffix` and have "g.Meta" in their body.
+func (c CGenCtrl) getStructsInfoInSrc(filePath, reqSuffix string) (structsInfo []apiStructInfo, err error) {
 	var (
 		fileContent = gfile.GetContents(filePath)
 		fileSet     = token.NewFileSet()
@@ -155,9 +213,9 @@ func (c CGenCtrl) getStructsNameInSrc(filePath string) (structsName []string, er
 
 	ast.Inspect(node, func(n ast.Node) bool {
 		if typeSpec, ok := n.(*ast.TypeSpec); ok {
-			methodName := typeSpec.Name.Name
-			if !gstr.HasSuffix(methodName, "Req") {
-				// ignore struct name that do not end in "Req"
+			structName := typeSpec.Name.Name
+			if !gstr.HasSuffix(structName, reqSuffix) || structName == reqSuffix {
+				// ignore struct name that do not end in request suffix
 				return true
 			}
 			if structType, ok := typeSpec.Type.(*ast.StructType); ok {
@@ -169,7 +227,11 @@ func (c CGenCtrl) getStructsNameInSrc(filePath string) (structsName []string, er
 				if !gstr.Contains(buf.String(), `g.Meta`) {
 					return true
 				}
-				structsName = append(structsName, methodName)
+				structsInfo = append(structsInfo, apiStructInfo{
+					Name:          structName,
+					Meta:          c.getMetaTagInStruct(structType),
+					HasValidation: c.hasValidationTagInStruct(structType),
+				})
 			}
 		}
 		return true
@@ -178,24 +240,97 @@ func (c CGenCtrl) getStructsNameInSrc(filePath string) (structsName []string, er
 	return
 }
 
-// getImportsInDst retrieves all import paths in the file.
-func (c CGenCtrl) getImportsInDst(filePath string) (imports []string, err error) {
+// getTypeNamesInSrc retrieves and returns all type names declared in given go files.
+func (c CGenCtrl) getTypeNamesInSrc(filePaths []string) (typeNameSet *gset.StrSet, err error) {
+	typeNameSet = gset.NewStrSet()
+	for _, filePath := range filePaths {
+		if gfile.IsDir(filePath) {
+			continue
+		}
+		node, err := parser.ParseFile(token.NewFileSet(), "", gfile.GetContents(filePath), 0)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(node, func(n ast.Node) bool {
+			if typeSpec, ok := n.(*ast.TypeSpec); ok {
+				typeNameSet.Add(typeSpec.Name.Name)
+			}
+			return true
+		})
+	}
+	return
+}
+
+// getMetaTagInStruct retrieves and returns the tag of the g.Meta field in given struct.
+func (c CGenCtrl) getMetaTagInStruct(structType *ast.StructType) reflect.StructTag {
+	for _, field := range structType.Fields.List {
+		if len(field.Names) > 0 || field.Tag == nil {
+			continue
+		}
+		selector, ok := field.Type.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != "Meta" {
+			continue
+		}
+		if ident, ok := selector.X.(*ast.Ident); !ok || ident.Name != "g" {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return ""
+		}
+		return reflect.StructTag(tag)
+	}
+	return ""
+}
+
+// hasValidationTagInStruct checks and returns whether any field in given struct has "v" validation tag.
+func (c CGenCtrl) hasValidationTagInStruct(structType *ast.StructType) bool {
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		if _, ok := reflect.StructTag(tag).Lookup("v"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// importItem is the import declaration parsed from controller source file.
+type importItem struct {
+	Path  string // Unquoted import path.
+	Alias string // Alias name of the import, which is empty if not aliased.
+}
+
+// getImportsInDst retrieves all imports in the file, in which the blank and dot imports are ignored
+// as they cannot be used as qualifiers of api definitions.
+func (c CGenCtrl) getImportsInDst(filePath string) (imports []importItem, err error) {
 	var (
 		fileContent = gfile.GetContents(filePath)
 		fileSet     = token.NewFileSet()
 	)
 
-	node, err := parser.ParseFile(fileSet, "", fileContent, parser.ParseComments)
+	node, err := parser.ParseFile(fileSet, "", fileContent, parser.ImportsOnly)
 	if err != nil {
 		return
 	}
 
-	ast.Inspect(node, func(n ast.Node) bool {
-		if imp, ok := n.(*ast.ImportSpec); ok {
-			imports = append(imports, imp.Path.Value)
+	for _, imp := range node.Imports {
+		var item importItem
+		if item.Path, err = strconv.Unquote(imp.Path.Value); err != nil {
+			return nil, err
 		}
-		return true
-	})
-
+		if imp.Name != nil {
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				continue
+			}
+			item.Alias = imp.Name.Name
+		}
+		imports = append(imports, item)
+	}
 	return
 }
@@ -0,0 +1,63 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package genctrl
+
+import (
+	"fmt"
+
+	"github.com/gogf/gf/cmd/gf/v2/internal/utility/mlog"
+	"github.com/gogf/gf/v2/container/gset"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+type driftChecker struct{}
+
+func newDriftChecker() *driftChecker {
+	return &driftChecker{}
+}
+
+// Check compares the api definitions of a module with its controller methods by module, version and
+// method name, and returns the descriptions of the controller methods missing for the api definitions,
+// which are not generated yet. It also warns for the orphaned controller methods whose api definitions
+// are missing.
+func (c *driftChecker) Check(apiItemsInSrc, apiItemsInDst []apiItem) (missing []string) {
+	var (
+		srcSet = gset.NewStrSet()
+		dstSet = gset.NewStrSet()
+	)
+	for _, item := range apiItemsInDst {
+		dstSet.Add(c.getKey(item))
+	}
+	for _, item := range apiItemsInSrc {
+		srcSet.Add(c.getKey(item))
+		if dstSet.Contains(c.getKey(item)) {
+			continue
+		}
+		missing = append(missing, fmt.Sprintf(
+			`missing controller method "%s" of %s for api definition in: %s`,
+			item.MethodName, c.getCtrlName(item), item.FilePath,
+		))
+	}
+	for _, item := range apiItemsInDst {
+		if srcSet.Contains(c.getKey(item)) {
+			continue
+		}
+		mlog.Printf(
+			`orphaned controller method "%s" found in: %s, whose api definition is missing`,
+			item.MethodName, item.FilePath,
+		)
+	}
+	return
+}
+
+func (c *driftChecker) getKey(item apiItem) string {
+	return gstr.Join([]string{item.Module, item.Version, item.MethodName}, ",")
+}
+
+func (c *driftChecker) getCtrlName(item apiItem) string {
+	return fmt.Sprintf(`%s.Controller%s`, item.Module, gstr.UcFirst(item.Version))
+}
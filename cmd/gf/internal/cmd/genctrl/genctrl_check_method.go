@@ -0,0 +1,76 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package genctrl
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gogf/gf/v2/container/gmap"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+type methodConflictChecker struct{}
+
+func newMethodConflictChecker() *methodConflictChecker {
+	return &methodConflictChecker{}
+}
+
+// Check groups the api definitions of all modules by module and method name,
+// and returns error naming the versions and source files of the ones that resolve to
+// the same controller method, which would collide in the generated controller files.
+//
+// The same method name in different versions is not a conflict, as they are generated
+// into different controllers, eg: ControllerV1 and ControllerV2. The versions are compared
+// case-insensitively, as their controller file names collide in case-insensitive file systems.
+func (c *methodConflictChecker) Check(apiItems []apiItem) (err error) {
+	var (
+		conflicts   = make([]string, 0)
+		methodItems = gmap.NewListMap()
+	)
+	for _, item := range apiItems {
+		var (
+			// the nested api modules of the same name are of different module folders.
+			methodKey = fmt.Sprintf(`%s,%s`, filepath.Dir(filepath.Dir(item.FilePath)), item.MethodName)
+			targetKey = gstr.ToLower(item.Version)
+			targets   *gmap.ListMap
+		)
+		if v := methodItems.Get(methodKey); v != nil {
+			targets = v.(*gmap.ListMap)
+		} else {
+			targets = gmap.NewListMap()
+			methodItems.Set(methodKey, targets)
+		}
+		var items []apiItem
+		if v := targets.Get(targetKey); v != nil {
+			items = v.([]apiItem)
+		}
+		targets.Set(targetKey, append(items, item))
+	}
+	methodItems.Iterator(func(_, value interface{}) bool {
+		value.(*gmap.ListMap).Iterator(func(_, value interface{}) bool {
+			var items = value.([]apiItem)
+			if len(items) < 2 {
+				return true
+			}
+			var sources = make([]string, 0, len(items))
+			for _, item := range items {
+				sources = append(sources, fmt.Sprintf(`%s(%s)`, item.Version, item.FilePath))
+			}
+			conflicts = append(conflicts, fmt.Sprintf(
+				`method conflict "%s.%s" found in: %s`, items[0].Module, items[0].MethodName, gstr.Join(sources, ", "),
+			))
+			return true
+		})
+		return true
+	})
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return gerror.New(gstr.Join(conflicts, "\n"))
+}
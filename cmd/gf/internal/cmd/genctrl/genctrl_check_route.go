@@ -0,0 +1,99 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package genctrl
+
+import (
+	"fmt"
+
+	"github.com/gogf/gf/cmd/gf/v2/internal/utility/mlog"
+	"github.com/gogf/gf/v2/container/gmap"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+const (
+	// routeMethodAll is the route method for api definition that has no method specified,
+	// which matches any HTTP method.
+	routeMethodAll = "ALL"
+)
+
+type routeConflictChecker struct{}
+
+func newRouteConflictChecker() *routeConflictChecker {
+	return &routeConflictChecker{}
+}
+
+// Check collects all (method, path) pairs from the api definitions of all modules,
+// and reports the ones that resolve to the same route with their source files.
+// The conflicts are returned as error if `strict` is true, or else they are printed as warnings.
+func (c *routeConflictChecker) Check(apiItems []apiItem, strict bool) (err error) {
+	var (
+		routeKey   string
+		conflicts  = make([]string, 0)
+		routeItems = gmap.NewListMap()
+	)
+	for _, item := range apiItems {
+		if item.Path == "" {
+			continue
+		}
+		for _, method := range c.getRouteMethods(item.HTTPMethod) {
+			routeKey = fmt.Sprintf(`%s %s`, method, item.Path)
+			var items []apiItem
+			if v := routeItems.Get(routeKey); v != nil {
+				items = v.([]apiItem)
+			}
+			routeItems.Set(routeKey, append(items, item))
+		}
+	}
+	routeItems.Iterator(func(key, value interface{}) bool {
+		var (
+			items  = value.([]apiItem)
+			method = gstr.Split(key.(string), " ")[0]
+			path   = gstr.Split(key.(string), " ")[1]
+		)
+		// The route with method ALL conflicts with any other method of the same path.
+		if method != routeMethodAll {
+			if v := routeItems.Get(fmt.Sprintf(`%s %s`, routeMethodAll, path)); v != nil {
+				items = append(items, v.([]apiItem)...)
+			}
+		}
+		if len(items) < 2 {
+			return true
+		}
+		var sources = make([]string, 0, len(items))
+		for _, item := range items {
+			sources = append(sources, fmt.Sprintf(`%s(%s%s)`, item.FilePath, item.MethodName, item.ReqSuffix))
+		}
+		conflicts = append(conflicts, fmt.Sprintf(
+			`route conflict "%s %s" found in: %s`, method, path, gstr.Join(sources, ", "),
+		))
+		return true
+	})
+	if len(conflicts) == 0 {
+		return nil
+	}
+	if strict {
+		return gerror.New(gstr.Join(conflicts, "\n"))
+	}
+	for _, conflict := range conflicts {
+		mlog.Print(conflict)
+	}
+	return nil
+}
+
+// getRouteMethods parses and returns the upper case route methods from g.Meta method tag,
+// which might be multiple methods joined with char ','.
+func (c *routeConflictChecker) getRouteMethods(method string) []string {
+	if method == "" {
+		return []string{routeMethodAll}
+	}
+	var methods = make([]string, 0)
+	for _, v := range gstr.SplitAndTrim(method, ",") {
+		methods = append(methods, gstr.ToUpper(v))
+	}
+	return methods
+}
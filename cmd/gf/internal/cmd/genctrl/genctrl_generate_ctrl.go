@@ -7,25 +7,40 @@
 package genctrl
 
 import (
+	"bytes"
 	"fmt"
 	"path/filepath"
 	"strings"
+	"text/template"
 
 	"github.com/gogf/gf/cmd/gf/v2/internal/consts"
 	"github.com/gogf/gf/cmd/gf/v2/internal/utility/mlog"
+	"github.com/gogf/gf/cmd/gf/v2/internal/utility/utils"
 	"github.com/gogf/gf/v2/container/gset"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/gogf/gf/v2/text/gstr"
 )
 
-type controllerGenerator struct{}
+type controllerGenerator struct {
+	// methodTemplate is the custom template for the body of controller methods.
+	// The built-in method body is used if it is nil.
+	methodTemplate *template.Template
+}
 
-func newControllerGenerator() *controllerGenerator {
-	return &controllerGenerator{}
+func newControllerGenerator(methodTemplate *template.Template) *controllerGenerator {
+	return &controllerGenerator{
+		methodTemplate: methodTemplate,
+	}
 }
 
-func (c *controllerGenerator) Generate(dstModuleFolderPath string, apiModuleApiItems []apiItem, merge bool) (err error) {
+// Generate generates the controller go files for given api items, which appends the controller methods
+// to the existing controller files without rewriting the existing methods.
+// If `force` is true, it overwrites the controller files of given api items instead.
+func (c *controllerGenerator) Generate(
+	dstModuleFolderPath string, apiModuleApiItems []apiItem, merge, genValidation, force bool,
+) (err error) {
 	var (
 		doneApiItemSet = gset.NewStrSet()
 	)
@@ -46,12 +61,12 @@ func (c *controllerGenerator) Generate(dstModuleFolderPath string, apiModuleApiI
 
 		// use -merge
 		if merge {
-			err = c.doGenerateCtrlMergeItem(dstModuleFolderPath, subItems, doneApiItemSet)
+			err = c.doGenerateCtrlMergeItem(dstModuleFolderPath, subItems, doneApiItemSet, genValidation, force)
 			continue
 		}
 
 		for _, subItem := range subItems {
-			err = c.doGenerateCtrlItem(dstModuleFolderPath, subItem)
+			err = c.doGenerateCtrlItem(dstModuleFolderPath, subItem, genValidation, force)
 			if err != nil {
 				return
 			}
@@ -125,8 +140,15 @@ func (c *controllerGenerator) doGenerateCtrlNewByModuleAndVersion(
 	return
 }
 
-func (c *controllerGenerator) doGenerateCtrlItem(dstModuleFolderPath string, item apiItem) (err error) {
+func (c *controllerGenerator) doGenerateCtrlItem(
+	dstModuleFolderPath string, item apiItem, genValidation, force bool,
+) (err error) {
+	methodBody, err := c.getMethodBodyContent(item)
+	if err != nil {
+		return err
+	}
 	var (
+		validation      = c.getValidationContent(item, genValidation)
 		methodNameSnake = gstr.CaseSnake(item.MethodName)
 		ctrlName        = fmt.Sprintf(`Controller%s`, gstr.UcFirst(item.Version))
 		methodFilePath  = filepath.FromSlash(gfile.Join(dstModuleFolderPath, fmt.Sprintf(
@@ -135,38 +157,57 @@ func (c *controllerGenerator) doGenerateCtrlItem(dstModuleFolderPath string, ite
 	)
 	var content string
 
-	if gfile.Exists(methodFilePath) {
+	if gfile.Exists(methodFilePath) && !force {
 		content = gstr.ReplaceByMap(consts.TemplateGenCtrlControllerMethodFuncMerge, g.MapStrStr{
-			"{Module}":     item.Module,
-			"{CtrlName}":   ctrlName,
-			"{Version}":    item.Version,
-			"{MethodName}": item.MethodName,
+			"{Module}":        item.Module,
+			"{CtrlName}":      ctrlName,
+			"{Version}":       item.Version,
+			"{MethodName}":    item.MethodName,
+			"{FuncName}":      item.FuncName,
+			"{MethodComment}": c.getMethodComment(item),
+			"{ReqSuffix}":     item.ReqSuffix,
+			"{Validation}":    validation,
+			"{MethodBody}":    methodBody,
 		})
 
-		if gstr.Contains(gfile.GetContents(methodFilePath), fmt.Sprintf(`func (c *%v) %v(`, ctrlName, item.MethodName)) {
+		if gstr.Contains(gfile.GetContents(methodFilePath), fmt.Sprintf(`func (c *%v) %v(`, ctrlName, item.FuncName)) {
 			return
 		}
+		if validation != "" {
+			if err = c.addValidationImport(methodFilePath); err != nil {
+				return err
+			}
+		}
 		if err = gfile.PutContentsAppend(methodFilePath, gstr.TrimLeft(content)); err != nil {
 			return err
 		}
 	} else {
 		content = gstr.ReplaceByMap(consts.TemplateGenCtrlControllerMethodFunc, g.MapStrStr{
-			"{Module}":     item.Module,
-			"{ImportPath}": item.Import,
-			"{CtrlName}":   ctrlName,
-			"{Version}":    item.Version,
-			"{MethodName}": item.MethodName,
+			"{Module}":           item.Module,
+			"{ImportPath}":       item.Import,
+			"{ImportValidation}": c.getValidationImportContent(validation != ""),
+			"{CtrlName}":         ctrlName,
+			"{Version}":          item.Version,
+			"{MethodName}":       item.MethodName,
+			"{FuncName}":         item.FuncName,
+			"{MethodComment}":    c.getMethodComment(item),
+			"{ReqSuffix}":        item.ReqSuffix,
+			"{Validation}":       validation,
+			"{MethodBody}":       methodBody,
 		})
 		if err = gfile.PutContents(methodFilePath, gstr.TrimLeft(content)); err != nil {
 			return err
 		}
 	}
+	c.formatCustomized(methodFilePath)
 	mlog.Printf(`generated: %s`, methodFilePath)
 	return
 }
 
 // use -merge
-func (c *controllerGenerator) doGenerateCtrlMergeItem(dstModuleFolderPath string, apiItems []apiItem, doneApiSet *gset.StrSet) (err error) {
+func (c *controllerGenerator) doGenerateCtrlMergeItem(
+	dstModuleFolderPath string, apiItems []apiItem, doneApiSet *gset.StrSet, genValidation, force bool,
+) (err error) {
 
 	type controllerFileItem struct {
 		module     string
@@ -174,6 +215,8 @@ func (c *controllerGenerator) doGenerateCtrlMergeItem(dstModuleFolderPath string
 		importPath string
 		// Each ctrlFileItem has multiple CTRLs
 		controllers strings.Builder
+		// Whether any CTRL has request validation.
+		hasValidation bool
 	}
 	// It is possible that there are multiple files under one module
 	ctrlFileItemMap := make(map[string]*controllerFileItem)
@@ -190,13 +233,30 @@ func (c *controllerGenerator) doGenerateCtrlMergeItem(dstModuleFolderPath string
 			ctrlFileItemMap[api.FileName] = ctrlFileItem
 		}
 
+		methodBody, err := c.getMethodBodyContent(api)
+		if err != nil {
+			return err
+		}
+		validation := c.getValidationContent(api, genValidation)
 		ctrl := gstr.TrimLeft(gstr.ReplaceByMap(consts.TemplateGenCtrlControllerMethodFuncMerge, g.MapStrStr{
-			"{Module}":     api.Module,
-			"{CtrlName}":   fmt.Sprintf(`Controller%s`, gstr.UcFirst(api.Version)),
-			"{Version}":    api.Version,
-			"{MethodName}": api.MethodName,
+			"{Module}":        api.Module,
+			"{CtrlName}":      fmt.Sprintf(`Controller%s`, gstr.UcFirst(api.Version)),
+			"{Version}":       api.Version,
+			"{MethodName}":    api.MethodName,
+			"{FuncName}":      api.FuncName,
+			"{MethodComment}": c.getMethodComment(api),
+			"{ReqSuffix}":     api.ReqSuffix,
+			"{Validation}":    validation,
+			"{MethodBody}":    methodBody,
 		}))
+		// the controller methods are separated by blank lines, as required by gofmt for methods with comments.
+		if ctrlFileItem.controllers.Len() > 0 {
+			ctrlFileItem.controllers.WriteString("\n")
+		}
 		ctrlFileItem.controllers.WriteString(ctrl)
+		if validation != "" {
+			ctrlFileItem.hasValidation = true
+		}
 		doneApiSet.Add(api.String())
 	}
 
@@ -207,21 +267,97 @@ func (c *controllerGenerator) doGenerateCtrlMergeItem(dstModuleFolderPath string
 
 		// This logic is only followed when a new ctrlFileItem is generated
 		// Most of the rest of the time, the following logic is followed
-		if !gfile.Exists(ctrlFilePath) {
+		if !gfile.Exists(ctrlFilePath) || force {
 			ctrlFileHeader := gstr.TrimLeft(gstr.ReplaceByMap(consts.TemplateGenCtrlControllerHeader, g.MapStrStr{
-				"{Module}":     ctrlFileItem.module,
-				"{ImportPath}": ctrlFileItem.importPath,
+				"{Module}":           ctrlFileItem.module,
+				"{ImportPath}":       ctrlFileItem.importPath,
+				"{ImportValidation}": c.getValidationImportContent(ctrlFileItem.hasValidation),
 			}))
 			err = gfile.PutContents(ctrlFilePath, ctrlFileHeader)
 			if err != nil {
 				return err
 			}
+		} else if ctrlFileItem.hasValidation {
+			if err = c.addValidationImport(ctrlFilePath); err != nil {
+				return err
+			}
 		}
 
 		if err = gfile.PutContentsAppend(ctrlFilePath, ctrlFileItem.controllers.String()); err != nil {
 			return err
 		}
+		c.formatCustomized(ctrlFilePath)
 		mlog.Printf(`generated: %s`, ctrlFilePath)
 	}
 	return
 }
+
+// getMethodBodyContent returns the body content for the controller method of `item`,
+// which is rendered using the custom method template if given, or else the built-in one.
+// The placeholder is prepended to the method body if the response struct of `item` is missing.
+func (c *controllerGenerator) getMethodBodyContent(item apiItem) (string, error) {
+	var placeholder string
+	if !item.HasResponse {
+		placeholder = gstr.Replace(consts.TemplateGenCtrlControllerResMissing, "{MethodName}", item.MethodName)
+	}
+	if c.methodTemplate == nil {
+		return placeholder + consts.TemplateGenCtrlControllerMethodBody, nil
+	}
+	var buffer = bytes.NewBuffer(nil)
+	if err := c.methodTemplate.Execute(buffer, item); err != nil {
+		return "", gerror.Wrapf(err, `execute controller template failed for method "%s"`, item.MethodName)
+	}
+	return placeholder + gstr.TrimRight(buffer.String(), "\r\n"), nil
+}
+
+// formatCustomized formats the controller file generated using the custom method template,
+// as the rendered method body might not use the imports of the built-in template or need others.
+func (c *controllerGenerator) formatCustomized(filePath string) {
+	if c.methodTemplate != nil {
+		utils.GoFmt(filePath)
+	}
+}
+
+// getMethodComment returns the comment of the controller method of `item`, which contains
+// the OpenAPI summary and tags of g.Meta, or an empty string if neither of them is given.
+func (c *controllerGenerator) getMethodComment(item apiItem) string {
+	var comment string
+	if summary := gstr.Join(gstr.SplitAndTrim(item.Summary, "\n"), " "); summary != "" {
+		comment += fmt.Sprintf("// %s %s\n", item.FuncName, summary)
+	}
+	if item.Tags != "" {
+		comment += fmt.Sprintf("// Tags: %s\n", item.Tags)
+	}
+	return comment
+}
+
+// getValidationContent returns the request validation content for the controller method of `item`,
+// or an empty string if validation generating is disabled or the request has no validation tags.
+func (c *controllerGenerator) getValidationContent(item apiItem, genValidation bool) string {
+	if genValidation && item.HasValidation {
+		return consts.TemplateGenCtrlControllerValidation
+	}
+	return ""
+}
+
+// getValidationImportContent returns the import content for request validation if `hasValidation`.
+func (c *controllerGenerator) getValidationImportContent(hasValidation bool) string {
+	if hasValidation {
+		return consts.TemplateGenCtrlControllerImportValidation
+	}
+	return ""
+}
+
+// addValidationImport adds the import for request validation to an existing controller file
+// if it is not imported yet.
+func (c *controllerGenerator) addValidationImport(filePath string) error {
+	var (
+		content      = gfile.GetContents(filePath)
+		gerrorImport = "\t\"github.com/gogf/gf/v2/errors/gerror\"\n"
+	)
+	if gstr.Contains(content, consts.TemplateGenCtrlControllerImportValidation) {
+		return nil
+	}
+	content = gstr.Replace(content, gerrorImport, gerrorImport+consts.TemplateGenCtrlControllerImportValidation, 1)
+	return gfile.PutContents(filePath, content)
+}
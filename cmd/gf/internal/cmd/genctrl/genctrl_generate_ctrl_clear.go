@@ -39,7 +39,9 @@ func (c *controllerClearer) doClear(dstModuleFolderPath string, item apiItem) (e
 		fileContent = gstr.Trim(gfile.GetContents(methodFilePath))
 	)
 	// retrieve it without using AST, because it's simple.
-	match, err := gregex.MatchString(`.+?Req.+?Res.+?{([\s\S]+?)}`, fileContent)
+	match, err := gregex.MatchString(
+		fmt.Sprintf(`.+?%s.+?Res.+?{([\s\S]+?)}`, gregex.Quote(item.ReqSuffix)), fileContent,
+	)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,99 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package genctrl
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogf/gf/cmd/gf/v2/internal/consts"
+	"github.com/gogf/gf/cmd/gf/v2/internal/utility/mlog"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+// Plan prints the controller files that Generate would create or modify for given api items,
+// along with the content of new controller methods, without writing any files.
+// The controller methods of `implementedApiItems` are printed as skipped.
+func (c *controllerGenerator) Plan(
+	dstModuleFolderPath string, apiModuleApiItems, implementedApiItems []apiItem, merge, genValidation, force bool,
+) (err error) {
+	for _, item := range implementedApiItems {
+		mlog.Printf(
+			`skip: controller method "%s" already implemented in: %s`,
+			item.MethodName, item.FilePath,
+		)
+	}
+	var (
+		ctrlFilePaths    []string
+		ctrlFileContents = make(map[string]*strings.Builder)
+	)
+	for _, item := range apiModuleApiItems {
+		for _, filePath := range []string{
+			filepath.FromSlash(gfile.Join(dstModuleFolderPath, item.Module+".go")),
+			filepath.FromSlash(gfile.Join(dstModuleFolderPath, item.Module+"_new.go")),
+		} {
+			if _, ok := ctrlFileContents[filePath]; !ok && !gfile.Exists(filePath) {
+				ctrlFilePaths = append(ctrlFilePaths, filePath)
+				ctrlFileContents[filePath] = &strings.Builder{}
+			}
+		}
+		methodBody, err := c.getMethodBodyContent(item)
+		if err != nil {
+			return err
+		}
+		var (
+			ctrlFileName = gstr.CaseSnake(item.MethodName)
+			method       = gstr.ReplaceByMap(consts.TemplateGenCtrlControllerMethodFuncMerge, g.MapStrStr{
+				"{Module}":        item.Module,
+				"{CtrlName}":      fmt.Sprintf(`Controller%s`, gstr.UcFirst(item.Version)),
+				"{Version}":       item.Version,
+				"{MethodName}":    item.MethodName,
+				"{FuncName}":      item.FuncName,
+				"{MethodComment}": c.getMethodComment(item),
+				"{ReqSuffix}":     item.ReqSuffix,
+				"{Validation}":    c.getValidationContent(item, genValidation),
+				"{MethodBody}":    methodBody,
+			})
+		)
+		if merge {
+			ctrlFileName = item.FileName
+		}
+		var ctrlFilePath = filepath.FromSlash(gfile.Join(dstModuleFolderPath, fmt.Sprintf(
+			`%s_%s_%s.go`, item.Module, item.Version, ctrlFileName,
+		)))
+		if _, ok := ctrlFileContents[ctrlFilePath]; !ok {
+			ctrlFilePaths = append(ctrlFilePaths, ctrlFilePath)
+			ctrlFileContents[ctrlFilePath] = &strings.Builder{}
+		}
+		if merge && ctrlFileContents[ctrlFilePath].Len() > 0 {
+			ctrlFileContents[ctrlFilePath].WriteString("\n")
+		}
+		ctrlFileContents[ctrlFilePath].WriteString(gstr.TrimLeft(method))
+	}
+	for _, ctrlFilePath := range ctrlFilePaths {
+		var operation = "create"
+		if gfile.Exists(ctrlFilePath) {
+			operation = "modify"
+			if force {
+				operation = "overwrite"
+			}
+		}
+		mlog.Printf(`%s: %s`, operation, ctrlFilePath)
+		// the content of new controller methods is printed as added lines of diff.
+		var content = gstr.TrimRight(ctrlFileContents[ctrlFilePath].String())
+		if content == "" {
+			continue
+		}
+		for _, line := range gstr.Split(content, "\n") {
+			mlog.Printf(`+ %s`, gstr.TrimRight(line))
+		}
+	}
+	return
+}
@@ -0,0 +1,139 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package genctrl
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/gogf/gf/cmd/gf/v2/internal/consts"
+	"github.com/gogf/gf/cmd/gf/v2/internal/utility/mlog"
+	"github.com/gogf/gf/cmd/gf/v2/internal/utility/utils"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+type controllerPruner struct{}
+
+func newControllerPruner() *controllerPruner {
+	return &controllerPruner{}
+}
+
+// Prune reports the orphaned controller methods whose api definitions are missing.
+// If `remove` is true, it removes the orphaned methods that are still generated stubs,
+// and warns for the ones whose bodies are edited by hand instead of removing them.
+func (c *controllerPruner) Prune(orphanedApiItemsInCtrl []apiItem, remove bool) (err error) {
+	for _, item := range orphanedApiItemsInCtrl {
+		if !gfile.Exists(item.FilePath) {
+			continue
+		}
+		if !remove {
+			mlog.Printf(
+				`orphaned controller method "%s" found in: %s, whose api definition is missing`,
+				item.MethodName, item.FilePath,
+			)
+			continue
+		}
+		if err = c.doPrune(item); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+func (c *controllerPruner) doPrune(item apiItem) (err error) {
+	var (
+		fileSet     = token.NewFileSet()
+		fileContent = gfile.GetContents(item.FilePath)
+		ctrlName    = fmt.Sprintf(`Controller%s`, gstr.UcFirst(item.Version))
+	)
+	node, err := parser.ParseFile(fileSet, "", fileContent, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	var (
+		funcDecl   *ast.FuncDecl
+		otherDecls int
+	)
+	for _, decl := range node.Decls {
+		if v, ok := decl.(*ast.FuncDecl); ok && funcDecl == nil && c.isCtrlMethod(v, ctrlName, item.FuncName) {
+			funcDecl = v
+			continue
+		}
+		if v, ok := decl.(*ast.GenDecl); ok && v.Tok == token.IMPORT {
+			continue
+		}
+		otherDecls++
+	}
+	if funcDecl == nil {
+		mlog.Printf(
+			`orphaned controller method "%s" found in: %s, whose api definition is missing`,
+			item.MethodName, item.FilePath,
+		)
+		return nil
+	}
+	var (
+		bodyStart = fileSet.Position(funcDecl.Body.Lbrace).Offset + 1
+		bodyEnd   = fileSet.Position(funcDecl.Body.Rbrace).Offset
+	)
+	if !c.isStubBody(fileContent[bodyStart:bodyEnd]) {
+		mlog.Printf(
+			`orphaned controller method "%s" found in: %s, which is not removed as it is implemented`,
+			item.MethodName, item.FilePath,
+		)
+		return nil
+	}
+	// remove the file if the orphaned controller method is the only declaration in it.
+	if otherDecls == 0 {
+		mlog.Printf(`remove unimplemented and of no api definitions controller file: %s`, item.FilePath)
+		return gfile.Remove(item.FilePath)
+	}
+	var declStart = funcDecl.Pos()
+	if funcDecl.Doc != nil {
+		declStart = funcDecl.Doc.Pos()
+	}
+	fileContent = fileContent[:fileSet.Position(declStart).Offset] + fileContent[fileSet.Position(funcDecl.End()).Offset:]
+	if err = gfile.PutContents(item.FilePath, fileContent); err != nil {
+		return err
+	}
+	// format the file to remove the redundant blank lines and unused imports.
+	utils.GoFmt(item.FilePath)
+	mlog.Printf(
+		`remove unimplemented and of no api definitions controller method "%s" in: %s`,
+		item.MethodName, item.FilePath,
+	)
+	return nil
+}
+
+// isCtrlMethod checks and returns whether `funcDecl` is the method `methodName` of controller `ctrlName`.
+func (c *controllerPruner) isCtrlMethod(funcDecl *ast.FuncDecl, ctrlName, methodName string) bool {
+	if funcDecl.Name.Name != methodName || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return false
+	}
+	var recvType = funcDecl.Recv.List[0].Type
+	if starExpr, ok := recvType.(*ast.StarExpr); ok {
+		recvType = starExpr.X
+	}
+	ident, ok := recvType.(*ast.Ident)
+	return ok && ident.Name == ctrlName
+}
+
+// isStubBody checks and returns whether given method body is the one generated by built-in template,
+// which might have request validation, ignoring the differences of white spaces.
+func (c *controllerPruner) isStubBody(body string) bool {
+	var (
+		normalize = func(s string) string {
+			return strings.Join(strings.Fields(s), " ")
+		}
+		normalizedBody = normalize(body)
+	)
+	return normalizedBody == normalize(consts.TemplateGenCtrlControllerMethodBody) ||
+		normalizedBody == normalize(consts.TemplateGenCtrlControllerValidation+consts.TemplateGenCtrlControllerMethodBody)
+}
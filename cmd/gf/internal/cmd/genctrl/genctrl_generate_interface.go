@@ -77,8 +77,9 @@ func (c *apiInterfaceGenerator) doGenerate(apiModuleFolderPath string, module st
 		)
 		for _, subItem := range subItems {
 			method = fmt.Sprintf(
-				"\t%s(ctx context.Context, req *%s.%sReq) (res *%s.%sRes, err error)",
-				subItem.MethodName, subItem.Version, subItem.MethodName, subItem.Version, subItem.MethodName,
+				"\t%s(ctx context.Context, req *%s.%s%s) (res *%s.%sRes, err error)",
+				subItem.FuncName, subItem.Version, subItem.MethodName, subItem.ReqSuffix,
+				subItem.Version, subItem.MethodName,
 			)
 			methods = append(methods, method)
 			doneApiItemSet.Add(subItem.String())
@@ -0,0 +1,120 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package genctrl
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gogf/gf/cmd/gf/v2/internal/consts"
+	"github.com/gogf/gf/cmd/gf/v2/internal/utility/mlog"
+	"github.com/gogf/gf/v2/container/gmap"
+	"github.com/gogf/gf/v2/container/gset"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+type routerGenerator struct{}
+
+func newRouterGenerator() *routerGenerator {
+	return &routerGenerator{}
+}
+
+// Generate generates the router registering go file for certain module,
+// in which the routes sharing the same middleware annotation of g.Meta are registered
+// under one router group that applies the middlewares only once.
+// If `groupByPrefix` is true, the routes are also grouped by their shared route prefix, eg: /user.
+// It does nothing if none of the api definitions has middleware annotation and `groupByPrefix` is false.
+func (c *routerGenerator) Generate(dstModuleFolderPath string, apiModuleApiItems []apiItem, groupByPrefix bool) (err error) {
+	if len(apiModuleApiItems) == 0 || (!groupByPrefix && !c.hasMiddleware(apiModuleApiItems)) {
+		return nil
+	}
+	var (
+		module         = apiModuleApiItems[0].Module
+		routerFilePath = filepath.FromSlash(gfile.Join(dstModuleFolderPath, module+"_router.go"))
+		versionSet     = gset.NewStrSet()
+		controllers    = make([]string, 0)
+		groups         = make([]string, 0)
+		// middleware annotation and route prefix => handlers, which keeps the order of api definitions.
+		groupHandlers = gmap.NewListMap()
+	)
+	for _, item := range apiModuleApiItems {
+		var ctrlVarName = c.getCtrlVarName(item.Version)
+		if versionSet.AddIfNotExist(item.Version) {
+			controllers = append(controllers, fmt.Sprintf(
+				"\t%s := New%s()", ctrlVarName, gstr.UcFirst(item.Version),
+			))
+		}
+		var (
+			groupKey = gstr.Join(gstr.SplitAndTrim(item.Middleware, ","), ",")
+			handlers []string
+		)
+		if groupByPrefix {
+			groupKey += " " + item.RoutePrefix()
+		}
+		if v := groupHandlers.Get(groupKey); v != nil {
+			handlers = v.([]string)
+		}
+		groupHandlers.Set(groupKey, append(
+			handlers, fmt.Sprintf("\t\t\t%s.%s,", ctrlVarName, item.FuncName),
+		))
+	}
+	groupHandlers.Iterator(func(key, value interface{}) bool {
+		var (
+			middlewareKey, routePrefix = gstr.List2(key.(string), " ")
+			handlers                   = value.([]string)
+			comment                    string
+		)
+		if routePrefix != "" {
+			comment = fmt.Sprintf("\t// routes with prefix \"%s\".\n", routePrefix)
+		}
+		if middlewareKey == "" {
+			for i, handler := range handlers {
+				handlers[i] = gstr.TrimLeftStr(handler, "\t", 1)
+			}
+			groups = append(groups, comment+gstr.ReplaceByMap(consts.TemplateGenCtrlRouterGroup, g.MapStrStr{
+				"{Handlers}": gstr.Join(handlers, "\n"),
+			}))
+			return true
+		}
+		var middlewares = make([]string, 0)
+		for _, name := range gstr.Split(middlewareKey, ",") {
+			middlewares = append(middlewares, fmt.Sprintf(`middlewares["%s"]`, name))
+		}
+		groups = append(groups, comment+gstr.ReplaceByMap(consts.TemplateGenCtrlRouterMiddlewareGroup, g.MapStrStr{
+			"{Middlewares}": gstr.Join(middlewares, ", "),
+			"{Handlers}":    gstr.Join(handlers, "\n"),
+		}))
+		return true
+	})
+	content := gstr.ReplaceByMap(consts.TemplateGenCtrlRouter, g.MapStrStr{
+		"{Module}":      module,
+		"{Controllers}": gstr.Join(controllers, "\n"),
+		"{Groups}":      gstr.TrimRight(gstr.Join(groups, ""), "\n"),
+	})
+	if err = gfile.PutContents(routerFilePath, gstr.TrimLeft(content)); err != nil {
+		return err
+	}
+	mlog.Printf(`generated: %s`, routerFilePath)
+	return
+}
+
+// hasMiddleware checks and returns whether any of the api definitions has middleware annotation.
+func (c *routerGenerator) hasMiddleware(items []apiItem) bool {
+	for _, item := range items {
+		if item.Middleware != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// getCtrlVarName returns the variable name of the controller for certain version, eg: ctrlV1.
+func (c *routerGenerator) getCtrlVarName(version string) string {
+	return fmt.Sprintf(`ctrl%s`, gstr.UcFirst(version))
+}
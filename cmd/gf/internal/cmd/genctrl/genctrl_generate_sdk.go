@@ -178,6 +178,8 @@ func (c *apiSdkGenerator) doGenerateSdkImplementer(
 		implementerFileContent += gstr.TrimLeft(gstr.ReplaceByMap(consts.TemplateGenCtrlSdkImplementerFunc, g.MapStrStr{
 			"{Version}":         item.Version,
 			"{MethodName}":      item.MethodName,
+			"{FuncName}":        item.FuncName,
+			"{ReqSuffix}":       item.ReqSuffix,
 			"{ImplementerName}": implementerName,
 		}))
 		implementerFileContent += "\n"
@@ -0,0 +1,83 @@
+// Copyright GoFrame gf Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package genctrl
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"text/template"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+// methodNamingFuncMap is the functions available in method naming template.
+var methodNamingFuncMap = template.FuncMap{
+	"UcFirst":   gstr.UcFirst,
+	"CaseCamel": gstr.CaseCamel,
+}
+
+// parseMethodNaming parses the method naming template for the names of controller methods.
+// It returns nil template if `methodNaming` is empty, which names controller methods by
+// the method names of api definitions.
+func (c CGenCtrl) parseMethodNaming(methodNaming string) (*template.Template, error) {
+	if methodNaming == "" {
+		return nil, nil
+	}
+	namingTemplate, err := template.New("methodNaming").Funcs(methodNamingFuncMap).Parse(methodNaming)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `parse method naming template "%s" failed`, methodNaming)
+	}
+	return namingTemplate, nil
+}
+
+// applyMethodNaming renders the names of controller methods of `items` using `namingTemplate`.
+// It returns error if any rendered name is not an exported Go identifier, or if the names of
+// different api definitions collide in the same controller.
+func (c CGenCtrl) applyMethodNaming(items []apiItem, namingTemplate *template.Template) ([]apiItem, error) {
+	if namingTemplate == nil {
+		return items, nil
+	}
+	var (
+		buffer   = bytes.NewBuffer(nil)
+		renamed  = make([]apiItem, len(items))
+		funcKeys = make(map[string]apiItem)
+	)
+	for i, item := range items {
+		buffer.Reset()
+		if err := namingTemplate.Execute(buffer, item); err != nil {
+			return nil, gerror.Wrapf(
+				err, `execute method naming template for api "%s%s" in "%s" failed`,
+				item.MethodName, item.ReqSuffix, item.FilePath,
+			)
+		}
+		var funcName = gstr.Trim(buffer.String())
+		if !token.IsIdentifier(funcName) || !token.IsExported(funcName) {
+			return nil, gerror.Newf(
+				`invalid controller method name "%s" of api "%s%s" in "%s", it should be an exported Go identifier`,
+				funcName, item.MethodName, item.ReqSuffix, item.FilePath,
+			)
+		}
+		// the controller methods of the same module folder and version are of the same controller.
+		var funcKey = fmt.Sprintf(
+			`%s,%s,%s`, filepath.Dir(filepath.Dir(item.FilePath)), gstr.ToLower(item.Version), funcName,
+		)
+		if existing, ok := funcKeys[funcKey]; ok {
+			return nil, gerror.Newf(
+				`controller method name "%s" of api "%s%s" in "%s" collides with api "%s%s" in "%s"`,
+				funcName, item.MethodName, item.ReqSuffix, item.FilePath,
+				existing.MethodName, existing.ReqSuffix, existing.FilePath,
+			)
+		}
+		funcKeys[funcKey] = item
+		item.FuncName = funcName
+		renamed[i] = item
+	}
+	return renamed, nil
+}
@@ -0,0 +1,14 @@
+package article
+
+import (
+	// standard library.
+	"context"
+	_ "embed" // blank import.
+
+	// api definitions.
+	apiv1 "github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-import-alias/api/article/v1" // aliased import.
+)
+
+func (c *ControllerV1) Create(ctx context.Context, req *apiv1.CreateReq) (res *apiv1.CreateRes, err error) {
+	return &apiv1.CreateRes{}, nil
+}
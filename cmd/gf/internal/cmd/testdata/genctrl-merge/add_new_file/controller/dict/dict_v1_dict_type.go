@@ -9,6 +9,8 @@ import (
 	"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-merge/add_new_file/api/dict/v1"
 )
 
+// DictTypeAddPage 字典类型添加页面
+// Tags: 字典管理
 func (c *ControllerV1) DictTypeAddPage(ctx context.Context, req *v1.DictTypeAddPageReq) (res *v1.DictTypeAddPageRes, err error) {
 	return nil, gerror.NewCode(gcode.CodeNotImplemented)
 }
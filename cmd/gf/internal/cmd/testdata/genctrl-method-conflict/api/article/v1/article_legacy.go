@@ -0,0 +1,14 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package v1
+
+import "github.com/gogf/gf/v2/frame/g"
+
+// GetListReq is defined again in the same version, which resolves to the same controller method.
+type GetListReq struct {
+	g.Meta `path:"/article/legacy/list" method:"get" tags:"ArticleService"`
+}
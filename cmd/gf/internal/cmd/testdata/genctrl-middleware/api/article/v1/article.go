@@ -0,0 +1,46 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package v1
+
+import "github.com/gogf/gf/v2/frame/g"
+
+type (
+	CreateReq struct {
+		g.Meta `path:"/article/create" method:"post" middleware:"auth" tags:"ArticleService"`
+		Title  string
+	}
+
+	CreateRes struct{}
+)
+
+type (
+	UpdateReq struct {
+		g.Meta `path:"/article/update" method:"post" middleware:"auth" tags:"ArticleService"`
+		Id     uint64
+		Title  string
+	}
+
+	UpdateRes struct{}
+)
+
+type (
+	DeleteReq struct {
+		g.Meta `path:"/article/delete" method:"post" middleware:"auth" tags:"ArticleService"`
+		Id     uint64
+	}
+
+	DeleteRes struct{}
+)
+
+type (
+	GetListReq struct {
+		g.Meta `path:"/article/list" method:"get" tags:"ArticleService"`
+		Page   int
+	}
+
+	GetListRes struct{}
+)
@@ -0,0 +1,24 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-nested/api/admin/user/v1/model"
+)
+
+type (
+	GetListReq struct {
+		g.Meta `path:"/admin/user/list" method:"get" tags:"AdminUserService"`
+		Page   int
+	}
+
+	GetListRes struct {
+		List []model.UserItem
+	}
+)
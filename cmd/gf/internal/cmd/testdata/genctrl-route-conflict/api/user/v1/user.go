@@ -0,0 +1,21 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package v1
+
+import "github.com/gogf/gf/v2/frame/g"
+
+type GetInfoReq struct {
+	g.Meta `path:"/user/info" method:"get" tags:"UserService"`
+}
+
+type GetInfoRes struct{}
+
+type UpdateInfoReq struct {
+	g.Meta `path:"/user/info" method:"put" tags:"UserService"`
+}
+
+type UpdateInfoRes struct{}
@@ -0,0 +1,37 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package v1
+
+import "github.com/gogf/gf/v2/frame/g"
+
+type (
+	GetListReq struct {
+		g.Meta `path:"/article/list" method:"get" tags:"ArticleService"`
+	}
+	GetListRes struct{}
+)
+
+type (
+	CreateReq struct {
+		g.Meta `path:"/article/create" method:"post" middleware:"auth" tags:"ArticleService"`
+	}
+	CreateRes struct{}
+)
+
+type (
+	GetCategoryListReq struct {
+		g.Meta `path:"/category/list" method:"get" tags:"ArticleService"`
+	}
+	GetCategoryListRes struct{}
+)
+
+type (
+	CreateCategoryReq struct {
+		g.Meta `path:"/category/create" method:"post" tags:"ArticleService"`
+	}
+	CreateCategoryRes struct{}
+)
@@ -0,0 +1,3 @@
+	// {{.Module}}/{{.Version}}.{{.MethodName}} is implemented by custom template.
+	res = &{{.Version}}.{{.MethodName}}Res{}
+	return
@@ -0,0 +1,19 @@
+package article
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-validation/api/article/v1"
+)
+
+// Tags: ArticleService
+func (c *ControllerV1) Create(ctx context.Context, req *v1.CreateReq) (res *v1.CreateRes, err error) {
+	if err = g.Validator().Data(req).Run(ctx); err != nil {
+		return nil, err
+	}
+	return nil, gerror.NewCode(gcode.CodeNotImplemented)
+}
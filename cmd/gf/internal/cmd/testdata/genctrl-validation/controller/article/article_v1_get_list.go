@@ -0,0 +1,15 @@
+package article
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+
+	"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl-validation/api/article/v1"
+)
+
+// Tags: ArticleService
+func (c *ControllerV1) GetList(ctx context.Context, req *v1.GetListReq) (res *v1.GetListRes, err error) {
+	return nil, gerror.NewCode(gcode.CodeNotImplemented)
+}
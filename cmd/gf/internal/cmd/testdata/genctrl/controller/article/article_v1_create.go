@@ -9,6 +9,7 @@ import (
 	"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl/api/article/v1"
 )
 
+// Tags: ArticleService
 func (c *ControllerV1) Create(ctx context.Context, req *v1.CreateReq) (res *v1.CreateRes, err error) {
 	return nil, gerror.NewCode(gcode.CodeNotImplemented)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl/api/article/v1"
 )
 
+// Tags: ArticleService
 func (c *ControllerV1) GetList(ctx context.Context, req *v1.GetListReq) (res *v1.GetListRes, err error) {
 	return nil, gerror.NewCode(gcode.CodeNotImplemented)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/genctrl/api/article/v1"
 )
 
+// Tags: ArticleService
 func (c *ControllerV1) Update(ctx context.Context, req *v1.UpdateReq) (res *v1.UpdateRes, err error) {
 	return nil, gerror.NewCode(gcode.CodeNotImplemented)
 }
@@ -9,15 +9,26 @@ import (
 	"github.com/gogf/gf/cmd/gf/v2/internal/cmd/testdata/issue/3460/api/hello/v2"
 )
 
+// DictTypeAddPage 字典类型添加页面
+// Tags: 字典管理
 func (c *ControllerV2) DictTypeAddPage(ctx context.Context, req *v2.DictTypeAddPageReq) (res *v2.DictTypeAddPageRes, err error) {
 	return nil, gerror.NewCode(gcode.CodeNotImplemented)
 }
+
+// DictTypeAdd 添加字典类型
+// Tags: 字典管理
 func (c *ControllerV2) DictTypeAdd(ctx context.Context, req *v2.DictTypeAddReq) (res *v2.DictTypeAddRes, err error) {
 	return nil, gerror.NewCode(gcode.CodeNotImplemented)
 }
+
+// DictTypeEditPage 字典类型添加页面
+// Tags: 字典管理
 func (c *ControllerV2) DictTypeEditPage(ctx context.Context, req *v2.DictTypeEditPageReq) (res *v2.DictTypeEditPageRes, err error) {
 	return nil, gerror.NewCode(gcode.CodeNotImplemented)
 }
+
+// DictTypeEdit 修改字典类型
+// Tags: 字典管理
 func (c *ControllerV2) DictTypeEdit(ctx context.Context, req *v2.DictTypeEditReq) (res *v2.DictTypeEditRes, err error) {
 	return nil, gerror.NewCode(gcode.CodeNotImplemented)
 }
@@ -42,12 +42,12 @@ import (
 
 	"github.com/gogf/gf/v2/errors/gcode"
 	"github.com/gogf/gf/v2/errors/gerror"
-
+{ImportValidation}
 	"{ImportPath}"
 )
 
-func (c *{CtrlName}) {MethodName}(ctx context.Context, req *{Version}.{MethodName}Req) (res *{Version}.{MethodName}Res, err error) {
-	return nil, gerror.NewCode(gcode.CodeNotImplemented)
+{MethodComment}func (c *{CtrlName}) {FuncName}(ctx context.Context, req *{Version}.{MethodName}{ReqSuffix}) (res *{Version}.{MethodName}Res, err error) {
+{Validation}{MethodBody}
 }
 `
 
@@ -59,7 +59,7 @@ import (
 
 	"github.com/gogf/gf/v2/errors/gcode"
 	"github.com/gogf/gf/v2/errors/gerror"
-
+{ImportValidation}
 	"{ImportPath}"
 )
 
@@ -67,11 +67,24 @@ import (
 
 const TemplateGenCtrlControllerMethodFuncMerge = `
 
-func (c *{CtrlName}) {MethodName}(ctx context.Context, req *{Version}.{MethodName}Req) (res *{Version}.{MethodName}Res, err error) {
-	return nil, gerror.NewCode(gcode.CodeNotImplemented)
+{MethodComment}func (c *{CtrlName}) {FuncName}(ctx context.Context, req *{Version}.{MethodName}{ReqSuffix}) (res *{Version}.{MethodName}Res, err error) {
+{Validation}{MethodBody}
 }
 `
 
+const TemplateGenCtrlControllerMethodBody = `	return nil, gerror.NewCode(gcode.CodeNotImplemented)`
+
+const TemplateGenCtrlControllerResMissing = `	// TODO: define the response struct "{MethodName}Res" in api definition, which is missing.
+`
+
+const TemplateGenCtrlControllerImportValidation = `	"github.com/gogf/gf/v2/frame/g"
+`
+
+const TemplateGenCtrlControllerValidation = `	if err = g.Validator().Data(req).Run(ctx); err != nil {
+		return nil, err
+	}
+`
+
 const TemplateGenCtrlApiInterface = `
 // =================================================================================
 // Code generated and maintained by GoFrame CLI tool. DO NOT EDIT.
@@ -85,3 +98,37 @@ import (
 
 {Interfaces}
 `
+
+const TemplateGenCtrlRouter = `
+// =================================================================================
+// Code generated and maintained by GoFrame CLI tool. DO NOT EDIT.
+// =================================================================================
+
+package {Module}
+
+import (
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// Register registers all routes of module "{Module}" to given router group.
+// The routes sharing the same "middleware" annotation in g.Meta are registered under one router group,
+// which applies the middlewares retrieved from "middlewares" by their annotation names only once.
+func Register(group *ghttp.RouterGroup, middlewares map[string]ghttp.HandlerFunc) {
+{Controllers}
+
+{Groups}
+}
+`
+
+const TemplateGenCtrlRouterMiddlewareGroup = `	group.Group("/", func(group *ghttp.RouterGroup) {
+		group.Middleware({Middlewares})
+		group.Bind(
+{Handlers}
+		)
+	})
+`
+
+const TemplateGenCtrlRouterGroup = `	group.Bind(
+{Handlers}
+	)
+`
@@ -82,7 +82,7 @@ func (i *implementer) {ImplementerName}() {Module}.I{ImplementerName} {
 `
 
 const TemplateGenCtrlSdkImplementerFunc = `
-func (i *implementer{ImplementerName}) {MethodName}(ctx context.Context, req *{Version}.{MethodName}Req) (res *{Version}.{MethodName}Res, err error) {
+func (i *implementer{ImplementerName}) {FuncName}(ctx context.Context, req *{Version}.{MethodName}{ReqSuffix}) (res *{Version}.{MethodName}Res, err error) {
 	err = i.Request(ctx, req, &res)
 	return
 }
@@ -8,6 +8,7 @@ package clickhouse
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/gogf/gf/v2/database/gdb"
 )
@@ -17,7 +18,36 @@ func (d *Driver) Begin(ctx context.Context) (tx gdb.TX, err error) {
 	return nil, errUnsupportedBegin
 }
 
+// BeginTx starts and returns the transaction object with given transaction options.
+func (d *Driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (tx gdb.TX, err error) {
+	return nil, errUnsupportedBegin
+}
+
 // Transaction wraps the transaction logic using function `f`.
 func (d *Driver) Transaction(ctx context.Context, f func(ctx context.Context, tx gdb.TX) error) error {
 	return errUnsupportedTransaction
 }
+
+// BeginRead starts and returns the read-only transaction object on slave node.
+func (d *Driver) BeginRead(ctx context.Context) (tx gdb.TX, err error) {
+	return nil, errUnsupportedBegin
+}
+
+// TransactionOpts wraps the transaction logic using function `f` with given transaction options.
+func (d *Driver) TransactionOpts(
+	ctx context.Context, opts gdb.TxOptions, f func(ctx context.Context, tx gdb.TX) error,
+) error {
+	return errUnsupportedTransaction
+}
+
+// TransactionWithRetry wraps the transaction logic using function `f`, which retries on retryable errors.
+func (d *Driver) TransactionWithRetry(
+	ctx context.Context, maxRetries int, f func(ctx context.Context, tx gdb.TX) error,
+) error {
+	return errUnsupportedTransaction
+}
+
+// BeginXA starts and returns the XA transaction object of global transaction id `xid`.
+func (d *Driver) BeginXA(ctx context.Context, xid string) (tx gdb.TX, err error) {
+	return nil, errUnsupportedBegin
+}
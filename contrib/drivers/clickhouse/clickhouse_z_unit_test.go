@@ -239,6 +239,29 @@ func TestDriverClickhouse_Transaction(t *testing.T) {
 	}), nil)
 }
 
+func TestDriverClickhouse_TransactionOpts(t *testing.T) {
+	connect := clickhouseConfigDB()
+	err := connect.TransactionOpts(context.Background(), gdb.TxOptions{}, func(ctx context.Context, tx gdb.TX) error {
+		return nil
+	})
+	gtest.AssertEQ(err, errUnsupportedTransaction)
+}
+
+func TestDriverClickhouse_TransactionWithRetry(t *testing.T) {
+	connect := clickhouseConfigDB()
+	err := connect.TransactionWithRetry(context.Background(), 3, func(ctx context.Context, tx gdb.TX) error {
+		return nil
+	})
+	gtest.AssertEQ(err, errUnsupportedTransaction)
+}
+
+func TestDriverClickhouse_BeginXA(t *testing.T) {
+	connect := clickhouseConfigDB()
+	tx, err := connect.BeginXA(context.Background(), "xid")
+	gtest.AssertEQ(err, errUnsupportedBegin)
+	gtest.AssertNil(tx)
+}
+
 func TestDriverClickhouse_InsertIgnore(t *testing.T) {
 	connect := clickhouseConfigDB()
 	_, err := connect.InsertIgnore(context.Background(), "", nil)
@@ -0,0 +1,38 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// IsDeadlockError checks and returns whether the given error is the deadlock error of MySQL.
+func (d *Driver) IsDeadlockError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == errNumberDeadlock
+}
+
+// GetDeadlockDetail queries and returns the InnoDB status, which contains the latest detected deadlock,
+// using a separate connection from the master node.
+func (d *Driver) GetDeadlockDetail(ctx context.Context) (string, error) {
+	master, err := d.Master()
+	if err != nil {
+		return "", err
+	}
+	var typ, name, status string
+	err = master.QueryRowContext(ctx, `SHOW ENGINE INNODB STATUS`).Scan(&typ, &name, &status)
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
@@ -0,0 +1,32 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package mysql
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	errNumberLockWaitTimeout = 1205 // ER_LOCK_WAIT_TIMEOUT
+	errNumberDeadlock        = 1213 // ER_LOCK_DEADLOCK
+)
+
+// IsRetryableError checks and returns whether the given error of transaction is retryable,
+// which are the deadlock and lock wait timeout errors of MySQL.
+func (d *Driver) IsRetryableError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	switch mysqlErr.Number {
+	case errNumberDeadlock, errNumberLockWaitTimeout:
+		return true
+	}
+	return false
+}
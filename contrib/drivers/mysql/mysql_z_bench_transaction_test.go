@@ -0,0 +1,54 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package mysql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gogf/gf/v2/database/gdb"
+)
+
+func benchmarkTXPrepare(b *testing.B, cacheDisabled bool) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	db.GetConfig().TxStmtCacheDisabled = cacheDisabled
+	defer func() {
+		db.GetConfig().TxStmtCacheDisabled = false
+	}()
+	querySql := fmt.Sprintf("SELECT passport FROM %s WHERE id=?", table)
+	b.ResetTimer()
+	err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		for i := 0; i < b.N; i++ {
+			st, err := tx.Prepare(querySql)
+			if err != nil {
+				return err
+			}
+			var passport string
+			if err = st.QueryRow(i%TableSize + 1).Scan(&passport); err != nil {
+				return err
+			}
+			if err = st.Close(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkTX_Prepare_Cached(b *testing.B) {
+	benchmarkTXPrepare(b, false)
+}
+
+func BenchmarkTX_Prepare_NoCache(b *testing.B) {
+	benchmarkTXPrepare(b, true)
+}
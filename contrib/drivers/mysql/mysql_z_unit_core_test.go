@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/gogf/gf/v2/container/garray"
+	"github.com/gogf/gf/v2/container/gvar"
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/encoding/gxml"
@@ -1672,3 +1673,49 @@ func Test_Core_ClearCacheAll(t *testing.T) {
 		t.AssertNil(err)
 	})
 }
+
+func Test_Core_SetResultInterceptor(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	db.SetResultInterceptor(func(ctx context.Context, columns []string, result gdb.Result) gdb.Result {
+		for _, column := range columns {
+			if column != "password" {
+				continue
+			}
+			for _, record := range result {
+				record[column] = gvar.New("******")
+			}
+		}
+		return result
+	})
+	defer db.SetResultInterceptor(nil)
+
+	gtest.C(t, func(t *gtest.T) {
+		one, err := db.GetOne(ctx, fmt.Sprintf("SELECT * FROM %s WHERE id=?", table), 1)
+		t.AssertNil(err)
+		t.Assert(one["passport"], "user_1")
+		t.Assert(one["password"], "******")
+	})
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			type User struct {
+				Id       int
+				Passport string
+				Password string
+			}
+			var user *User
+			err := tx.GetStruct(&user, fmt.Sprintf("SELECT * FROM %s WHERE id=?", table), 2)
+			t.AssertNil(err)
+			t.Assert(user.Passport, "user_2")
+			t.Assert(user.Password, "******")
+			return nil
+		})
+		t.AssertNil(err)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		value, err := db.Model(table).Where("id", 3).Value("nickname")
+		t.AssertNil(err)
+		t.Assert(value, "name_3")
+	})
+}
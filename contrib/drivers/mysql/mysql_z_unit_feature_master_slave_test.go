@@ -7,6 +7,7 @@
 package mysql_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -97,4 +98,37 @@ func Test_Master_Slave(t *testing.T) {
 		t.AssertNil(err)
 		t.Assert(count, int64(TableSize))
 	})
+	// Read-only transaction on slave.
+	gtest.C(t, func(t *gtest.T) {
+		table := "table_" + guid.S()
+		createInitTableWithDb(masterSlaveDB.Schema("master"), table)
+		createTableWithDb(masterSlaveDB.Schema("slave"), table)
+		defer dropTableWithDb(masterSlaveDB.Schema("master"), table)
+		defer dropTableWithDb(masterSlaveDB.Schema("slave"), table)
+
+		tx, err := masterSlaveDB.BeginRead(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		t.Assert(tx.IsReadOnly(), true)
+
+		count, err := tx.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 0)
+
+		_, err = tx.Insert(table, g.Map{"id": 1, "passport": "t1"})
+		t.Assert(err.Error(), "transaction is read-only")
+		_, err = tx.Update(table, g.Map{"passport": "t1"}, "id", 1)
+		t.Assert(err.Error(), "transaction is read-only")
+		_, err = tx.Delete(table, "id", 1)
+		t.Assert(err.Error(), "transaction is read-only")
+		_, err = tx.Exec(fmt.Sprintf("DELETE FROM %s", table))
+		t.Assert(err.Error(), "transaction is read-only")
+
+		// Nested transaction is allowed.
+		err = tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Model(table).All()
+			return err
+		})
+		t.AssertNil(err)
+	})
 }
@@ -725,6 +725,87 @@ CREATE TABLE %s (
 	})
 }
 
+func Test_SoftDelete_TX(t *testing.T) {
+	table := "soft_time_test_table_" + gtime.TimestampNanoStr()
+	if _, err := db.Exec(ctx, fmt.Sprintf(`
+CREATE TABLE %s (
+  id        int(11) NOT NULL,
+  name      varchar(45) DEFAULT NULL,
+  create_at datetime(6) DEFAULT NULL,
+  update_at datetime(6) DEFAULT NULL,
+  delete_at datetime(6) DEFAULT NULL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+    `, table)); err != nil {
+		gtest.Error(err)
+	}
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		for i := 1; i <= 3; i++ {
+			_, err := db.Model(table).Data(g.Map{
+				"id":   i,
+				"name": fmt.Sprintf("name_%d", i),
+			}).Insert()
+			t.AssertNil(err)
+		}
+	})
+	// Soft deleting in rolled back transaction.
+	gtest.C(t, func(t *gtest.T) {
+		original, err := db.Model(table).WherePri(1).One()
+		t.AssertNil(err)
+
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		r, err := tx.Delete(table, "id", 1)
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 1)
+
+		count, err := tx.Model(table).WherePri(1).Count()
+		t.AssertNil(err)
+		t.Assert(count, 0)
+		one, err := tx.Model(table).Unscoped().WherePri(1).One()
+		t.AssertNil(err)
+		t.AssertNE(one["delete_at"].String(), "")
+		t.AssertNil(tx.Rollback())
+
+		one, err = db.Model(table).WherePri(1).One()
+		t.AssertNil(err)
+		t.Assert(one["update_at"].String(), original["update_at"].String())
+		t.Assert(one["delete_at"].String(), "")
+	})
+	// Soft deleting in committed transaction.
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		_, err = tx.Delete(table, "id", 2)
+		t.AssertNil(err)
+		t.AssertNil(tx.Commit())
+
+		count, err := db.Model(table).WherePri(2).Count()
+		t.AssertNil(err)
+		t.Assert(count, 0)
+		one, err := db.Model(table).Unscoped().WherePri(2).One()
+		t.AssertNil(err)
+		t.AssertNE(one["delete_at"].String(), "")
+	})
+	// Real deleting in transaction.
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		r, err := tx.DeleteForce(table, "id", g.SliceInt{2, 3})
+		t.AssertNil(err)
+		n, _ := r.RowsAffected()
+		t.Assert(n, 2)
+		t.AssertNil(tx.Commit())
+
+		count, err := db.Model(table).Unscoped().Count()
+		t.AssertNil(err)
+		t.Assert(count, 1)
+	})
+}
+
 func Test_SoftDelete_Join(t *testing.T) {
 	table1 := "time_test_table1"
 	if _, err := db.Exec(ctx, fmt.Sprintf(`
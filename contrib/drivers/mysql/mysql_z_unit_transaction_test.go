@@ -7,80 +7,1467 @@
 package mysql_test
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"os"
 	"testing"
+	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
+
+	"github.com/gogf/gf/v2/container/garray"
 	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/glog"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/text/gstr"
 )
 
-func Test_TX_Query(t *testing.T) {
-	tx, err := db.Begin(ctx)
-	if err != nil {
-		gtest.Error(err)
-	}
-	if _, err = tx.Query("SELECT ?", 1); err != nil {
-		gtest.Error(err)
-	}
-	if _, err = tx.Query("SELECT ?+?", 1, 2); err != nil {
-		gtest.Error(err)
-	}
-	if _, err = tx.Query("SELECT ?+?", g.Slice{1, 2}); err != nil {
-		gtest.Error(err)
-	}
-	if _, err = tx.Query("ERROR"); err == nil {
-		gtest.Error("FAIL")
-	}
-	if err = tx.Commit(); err != nil {
-		gtest.Error(err)
-	}
+func Test_TX_Query(t *testing.T) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		gtest.Error(err)
+	}
+	if _, err = tx.Query("SELECT ?", 1); err != nil {
+		gtest.Error(err)
+	}
+	if _, err = tx.Query("SELECT ?+?", 1, 2); err != nil {
+		gtest.Error(err)
+	}
+	if _, err = tx.Query("SELECT ?+?", g.Slice{1, 2}); err != nil {
+		gtest.Error(err)
+	}
+	if _, err = tx.Query("ERROR"); err == nil {
+		gtest.Error("FAIL")
+	}
+	if err = tx.Commit(); err != nil {
+		gtest.Error(err)
+	}
+}
+
+func Test_TX_Exec(t *testing.T) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		gtest.Error(err)
+	}
+	if _, err := tx.Exec("SELECT ?", 1); err != nil {
+		gtest.Error(err)
+	}
+	if _, err := tx.Exec("SELECT ?+?", 1, 2); err != nil {
+		gtest.Error(err)
+	}
+	if _, err := tx.Exec("SELECT ?+?", g.Slice{1, 2}); err != nil {
+		gtest.Error(err)
+	}
+	if _, err := tx.Exec("ERROR"); err == nil {
+		gtest.Error("FAIL")
+	}
+	if err := tx.Commit(); err != nil {
+		gtest.Error(err)
+	}
+}
+
+func Test_TX_Commit(t *testing.T) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		gtest.Error(err)
+	}
+	if err := tx.Commit(); err != nil {
+		gtest.Error(err)
+	}
+}
+
+func Test_TX_Rollback(t *testing.T) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		gtest.Error(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		gtest.Error(err)
+	}
+}
+
+func Test_TX_BeginTx(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{
+			Isolation: sql.LevelRepeatableRead,
+			ReadOnly:  true,
+		})
+		t.AssertNil(err)
+
+		count, err := tx.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize)
+
+		_, err = tx.Insert(table, g.Map{
+			"id":       TableSize + 1,
+			"passport": "t_read_only",
+		})
+		t.AssertNE(err, nil)
+		t.AssertNil(tx.Rollback())
+	})
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		t.AssertNil(err)
+		t.AssertNil(tx.GetOptions())
+		_, err = tx.Update(table, g.Map{"passport": "t_updated"}, "id", 1)
+		t.AssertNil(err)
+		t.AssertNil(tx.Commit())
+
+		value, err := db.Model(table).Where("id", 1).Value("passport")
+		t.AssertNil(err)
+		t.Assert(value, "t_updated")
+	})
+	// Context cancellation rolls back the transaction.
+	gtest.C(t, func(t *gtest.T) {
+		cancelCtx, cancelFunc := context.WithCancel(ctx)
+		tx, err := db.BeginTx(cancelCtx, &sql.TxOptions{})
+		t.AssertNil(err)
+		_, err = tx.Update(table, g.Map{"passport": "t_canceled"}, "id", 2)
+		t.AssertNil(err)
+		cancelFunc()
+		t.AssertNE(tx.Commit(), nil)
+
+		value, err := db.Model(table).Where("id", 2).Value("passport")
+		t.AssertNil(err)
+		t.Assert(value, "user_2")
+	})
+}
+
+func Test_TX_BeginTx_Options(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		opts := &sql.TxOptions{
+			Isolation: sql.LevelSerializable,
+			ReadOnly:  true,
+		}
+		tx, err := db.BeginTx(ctx, opts)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		t.Assert(tx.GetOptions() == opts, true)
+
+		count, err := tx.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize)
+
+		// Nested transaction uses save point, which ignores the options.
+		err = tx.Begin()
+		t.AssertNil(err)
+		count, err = tx.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize)
+		t.AssertNil(tx.Rollback())
+		t.Assert(tx.IsClosed(), false)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		t.AssertNil(err)
+		t.AssertNil(tx.GetOptions())
+		t.AssertNil(tx.Commit())
+
+		tx, err = db.Begin(ctx)
+		t.AssertNil(err)
+		t.AssertNil(tx.GetOptions())
+		t.AssertNil(tx.Commit())
+	})
+}
+
+type fakeTxMetricsSink struct {
+	metrics []gdb.TxMetrics
+}
+
+func (s *fakeTxMetricsSink) RecordTx(ctx context.Context, metrics gdb.TxMetrics) {
+	s.metrics = append(s.metrics, metrics)
+}
+
+func Test_TX_SetMetricsLabel(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		sink := &fakeTxMetricsSink{}
+		db.SetTxMetricsSink(sink)
+		defer db.SetTxMetricsSink(nil)
+
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		tx = tx.SetMetricsLabel("checkout")
+		t.Assert(tx.GetMetricsLabel(), "checkout")
+		_, err = tx.Update(table, g.Map{"passport": "t_checkout"}, "id", 1)
+		t.AssertNil(err)
+		// Nested transaction does not produce metrics.
+		t.AssertNil(tx.Begin())
+		t.AssertNil(tx.Commit())
+		t.Assert(len(sink.metrics), 0)
+		t.AssertNil(tx.Commit())
+
+		t.Assert(len(sink.metrics), 1)
+		t.Assert(sink.metrics[0].Label, "checkout")
+		t.Assert(sink.metrics[0].Group, db.GetGroup())
+		t.Assert(sink.metrics[0].Committed, true)
+		t.AssertNil(sink.metrics[0].Error)
+		t.AssertGT(sink.metrics[0].Duration, 0)
+
+		// Default empty label.
+		err = db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			return gerror.New("rollback")
+		})
+		t.AssertNE(err, nil)
+		t.Assert(len(sink.metrics), 2)
+		t.Assert(sink.metrics[1].Label, "")
+		t.Assert(sink.metrics[1].Committed, false)
+	})
+}
+
+func Test_TX_Cursor(t *testing.T) {
+	var (
+		table = createTable()
+		total = 1000
+	)
+	defer dropTable(table)
+
+	array := garray.New(true)
+	for i := 1; i <= total; i++ {
+		array.Append(g.Map{
+			"id":       i,
+			"passport": fmt.Sprintf(`user_%d`, i),
+			"nickname": fmt.Sprintf(`name_%d`, i%3),
+		})
+	}
+	_, err := db.Model(table).Data(array).Batch(100).Insert()
+	gtest.AssertNil(err)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+		t.AssertNil(err)
+		defer tx.Rollback()
+
+		cursor, err := tx.Model(table).Where("nickname", "name_1").Cursor(100)
+		t.AssertNil(err)
+
+		var (
+			batches int
+			count   int
+			lastId  int
+		)
+		for {
+			result, err := cursor.Next()
+			t.AssertNil(err)
+			if result.IsEmpty() {
+				break
+			}
+			if batches == 0 {
+				// Records inserted by other connection are invisible in the snapshot.
+				_, err = db.Model(table).Data(g.Map{"id": total + 1, "nickname": "name_1"}).Insert()
+				t.AssertNil(err)
+			}
+			t.AssertLE(len(result), 100)
+			for _, record := range result {
+				t.AssertGT(record["id"].Int(), lastId)
+				t.Assert(record["nickname"].String(), "name_1")
+				lastId = record["id"].Int()
+			}
+			count += len(result)
+			batches++
+		}
+		t.Assert(count, 334)
+		t.Assert(batches, 4)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Cursor(0)
+		t.AssertNE(err, nil)
+		_, err = db.Model(table).OrderDesc("id").Cursor(10)
+		t.AssertNE(err, nil)
+		cursor, err := db.Model(table).Fields("passport").Cursor(10)
+		t.AssertNil(err)
+		_, err = cursor.Next()
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_TX_Increment(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			value, err := tx.Increment(table, "id", 100, "id", 1)
+			t.AssertNil(err)
+			t.Assert(value, 101)
+
+			value, err = tx.Increment(table, "id", -1, "passport", "user_2")
+			t.AssertNil(err)
+			t.Assert(value, 1)
+
+			// Zero increment changes no row, but the record matches.
+			value, err = tx.Increment(table, "id", 0, "passport", "user_3")
+			t.AssertNil(err)
+			t.Assert(value, 3)
+
+			_, err = tx.Increment(table, "id", 1, "id", 10000)
+			t.AssertNE(err, nil)
+			return nil
+		})
+		t.AssertNil(err)
+
+		count, err := db.Model(table).Where("id", 101).Count()
+		t.AssertNil(err)
+		t.Assert(count, 1)
+	})
+}
+
+func Test_TX_TransactionWithRetry(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	// Retries on deadlock.
+	gtest.C(t, func(t *gtest.T) {
+		var attempts int
+		err := db.TransactionWithRetry(ctx, 3, func(ctx context.Context, tx gdb.TX) error {
+			attempts++
+			_, err := tx.Update(table, g.Map{"passport": fmt.Sprintf(`t_retry_%d`, attempts)}, "id", 1)
+			t.AssertNil(err)
+			if attempts < 3 {
+				return &mysqldriver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+			}
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(attempts, 3)
+
+		value, err := db.Model(table).Where("id", 1).Value("passport")
+		t.AssertNil(err)
+		t.Assert(value, "t_retry_3")
+	})
+	// Returns the last error after retries exhausted.
+	gtest.C(t, func(t *gtest.T) {
+		var attempts int
+		err := db.TransactionWithRetry(ctx, 2, func(ctx context.Context, tx gdb.TX) error {
+			attempts++
+			return &mysqldriver.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}
+		})
+		t.AssertNE(err, nil)
+		t.Assert(attempts, 3)
+	})
+	// No retry on non-retryable error.
+	gtest.C(t, func(t *gtest.T) {
+		var attempts int
+		err := db.TransactionWithRetry(ctx, 3, func(ctx context.Context, tx gdb.TX) error {
+			attempts++
+			_, err := tx.Insert(table, g.Map{"id": 1, "passport": "t_duplicated"})
+			return err
+		})
+		t.AssertNE(err, nil)
+		t.Assert(attempts, 1)
+	})
+}
+
+func Test_TX_TransactionWithRetry_Custom(t *testing.T) {
+	var errConflict = gerror.New("version conflict")
+
+	// Custom retryable error function.
+	gtest.C(t, func(t *gtest.T) {
+		db.SetRetryableErrorFunc(func(err error) bool {
+			return gerror.Is(err, errConflict)
+		})
+		defer db.SetRetryableErrorFunc(nil)
+
+		var attempts int
+		err := db.TransactionWithRetry(ctx, 3, func(ctx context.Context, tx gdb.TX) error {
+			attempts++
+			if attempts < 2 {
+				return errConflict
+			}
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(attempts, 2)
+	})
+	// Stops retrying if context is done.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			attempts            int
+			cancelCtx, cancelFn = context.WithCancel(ctx)
+		)
+		err := db.TransactionWithRetry(cancelCtx, 10, func(ctx context.Context, tx gdb.TX) error {
+			attempts++
+			cancelFn()
+			return &mysqldriver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+		})
+		t.AssertNE(err, nil)
+		t.Assert(attempts, 1)
+	})
+}
+
+func Test_TX_TransactionOpts(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	// Zero value options are the same as Transaction.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.TransactionOpts(ctx, gdb.TxOptions{}, func(ctx context.Context, tx gdb.TX) error {
+			t.AssertNil(tx.GetOptions())
+			_, err := tx.Update(table, g.Map{"passport": "t_opts"}, "id", 1)
+			return err
+		})
+		t.AssertNil(err)
+		value, err := db.Model(table).Where("id", 1).Value("passport")
+		t.AssertNil(err)
+		t.Assert(value, "t_opts")
+	})
+	// Isolation and read-only.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.TransactionOpts(ctx, gdb.TxOptions{
+			Isolation: sql.LevelSerializable,
+			ReadOnly:  true,
+		}, func(ctx context.Context, tx gdb.TX) error {
+			t.Assert(tx.GetOptions().Isolation, sql.LevelSerializable)
+			t.Assert(tx.IsReadOnly(), true)
+			_, err := tx.Update(table, g.Map{"passport": "t_read_only"}, "id", 1)
+			return err
+		})
+		t.AssertNE(err, nil)
+	})
+	// Timeout.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.TransactionOpts(ctx, gdb.TxOptions{
+			Timeout: 100 * time.Millisecond,
+		}, func(ctx context.Context, tx gdb.TX) error {
+			deadline, ok := ctx.Deadline()
+			t.Assert(ok, true)
+			t.Assert(time.Until(deadline) <= 100*time.Millisecond, true)
+			_, err := tx.Exec("SELECT SLEEP(1)")
+			return err
+		})
+		t.AssertNE(err, nil)
+	})
+	// The earlier deadline of parent context takes effect.
+	gtest.C(t, func(t *gtest.T) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		err := db.TransactionOpts(timeoutCtx, gdb.TxOptions{
+			Timeout: time.Minute,
+		}, func(ctx context.Context, tx gdb.TX) error {
+			deadline, ok := ctx.Deadline()
+			t.Assert(ok, true)
+			t.Assert(time.Until(deadline) <= 100*time.Millisecond, true)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+	// Retries.
+	gtest.C(t, func(t *gtest.T) {
+		var attempts int
+		err := db.TransactionOpts(ctx, gdb.TxOptions{
+			Isolation:  sql.LevelReadCommitted,
+			MaxRetries: 2,
+		}, func(ctx context.Context, tx gdb.TX) error {
+			attempts++
+			t.Assert(tx.GetOptions().Isolation, sql.LevelReadCommitted)
+			if attempts < 2 {
+				return &mysqldriver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+			}
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(attempts, 2)
+	})
+}
+
+func Test_TX_InsertIfNotExists(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			// Already exists.
+			inserted, err := tx.InsertIfNotExists(table, g.Map{
+				"id":       TableSize + 1,
+				"passport": "user_1",
+			}, "passport", "user_1")
+			t.AssertNil(err)
+			t.Assert(inserted, false)
+
+			// Inserted.
+			inserted, err = tx.InsertIfNotExists(table, g.Map{
+				"id":       TableSize + 1,
+				"passport": "user_new",
+				"nickname": "name_new",
+			}, "passport=?", "user_new")
+			t.AssertNil(err)
+			t.Assert(inserted, true)
+
+			// Exists after inserted.
+			inserted, err = tx.InsertIfNotExists(table, g.Map{
+				"id":       TableSize + 2,
+				"passport": "user_new",
+			}, g.Map{"passport": "user_new"})
+			t.AssertNil(err)
+			t.Assert(inserted, false)
+			return nil
+		})
+		t.AssertNil(err)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize+1)
+
+		value, err := db.Model(table).Where("id", TableSize+1).Value("nickname")
+		t.AssertNil(err)
+		t.Assert(value, "name_new")
+	})
+}
+
+func Test_TX_Assert(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	var assertCount = func(count int) func(tx gdb.TX) error {
+		return func(tx gdb.TX) error {
+			n, err := tx.Model(table).Count()
+			if err != nil {
+				return err
+			}
+			if n != count {
+				return gerror.Newf(`expected count %d, but got %d`, count, n)
+			}
+			return nil
+		}
+	}
+	// Debug off, assertion skipped.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Model(table).Where("id", 1).Delete()
+			t.AssertNil(err)
+			return tx.Assert(assertCount(TableSize))
+		})
+		t.AssertNil(err)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize-1)
+	})
+	// Debug on.
+	gtest.C(t, func(t *gtest.T) {
+		db.SetDebug(true)
+		defer db.SetDebug(false)
+
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Model(table).Where("id", 2).Delete()
+			t.AssertNil(err)
+			return tx.Assert(assertCount(TableSize - 2))
+		})
+		t.AssertNil(err)
+
+		err = db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Model(table).Where("id", 3).Delete()
+			t.AssertNil(err)
+			return tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				return tx.Assert(assertCount(TableSize))
+			})
+		})
+		t.AssertNE(err, nil)
+		t.Assert(gerror.Cause(err).Error(), fmt.Sprintf(`expected count %d, but got %d`, TableSize, TableSize-3))
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, TableSize-2)
+	})
+}
+
+func Test_TX_OnCommit_OnRollback(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		var events = garray.NewStrArray()
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			tx.OnCommit(func() { events.Append("commit1") })
+			tx.OnCommit(func() { events.Append("commit2") })
+			tx.OnRollback(func() { events.Append("rollback") })
+			err := tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				_, err := tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+				return err
+			})
+			t.AssertNil(err)
+			// Releasing save point does not trigger callbacks.
+			t.Assert(events.Len(), 0)
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(events.Slice(), []string{"commit1", "commit2"})
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		var events = garray.NewStrArray()
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			tx.OnCommit(func() { events.Append("commit") })
+			tx.OnRollback(func() { events.Append("rollback1") })
+			tx.OnRollback(func() { events.Append("rollback2") })
+			err := tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				return gerror.New("nested rollback")
+			})
+			t.AssertNE(err, nil)
+			// Rolling back to save point does not trigger callbacks.
+			t.Assert(events.Len(), 0)
+			return gerror.New("rollback")
+		})
+		t.AssertNE(err, nil)
+		t.Assert(events.Slice(), []string{"rollback1", "rollback2"})
+	})
+}
+
+func Test_TX_StatementCount(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		t.Assert(tx.StatementCount(), 0)
+
+		_, err = tx.Query("SELECT 1")
+		t.AssertNil(err)
+		_, err = tx.Exec(fmt.Sprintf("UPDATE %s SET nickname='name' WHERE id=1", table))
+		t.AssertNil(err)
+		t.Assert(tx.StatementCount(), 2)
+
+		_, err = tx.Model(table).Where("id", 1).One()
+		t.AssertNil(err)
+		_, err = db.Model(table).Ctx(tx.GetCtx()).TX(tx).Count()
+		t.AssertNil(err)
+		t.Assert(tx.StatementCount(), 4)
+	})
+}
+
+func Test_TX_StatementCount_Commit(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	var buffer = bytes.NewBuffer(nil)
+	db.GetLogger().(*glog.Logger).SetWriter(buffer)
+	defer db.GetLogger().(*glog.Logger).SetWriter(os.Stdout)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			tx.Debug(true)
+			_, err := tx.Exec(fmt.Sprintf("UPDATE %s SET nickname='name' WHERE id=1", table))
+			t.AssertNil(err)
+			// The save point statements of nested transaction are also counted.
+			err = tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				_, err := tx.Query(fmt.Sprintf("SELECT * FROM %s WHERE id=1", table))
+				return err
+			})
+			t.AssertNil(err)
+			t.Assert(tx.StatementCount(), 4)
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(gstr.Contains(buffer.String(), "COMMIT [statements:4]"), true)
+	})
+}
+
+func Test_TX_StartTime_Elapsed(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var begin = time.Now()
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			t.Assert(tx.StartTime().Before(begin), false)
+			t.Assert(tx.StartTime().After(time.Now()), false)
+			time.Sleep(10 * time.Millisecond)
+			t.AssertGE(tx.Elapsed(), 10*time.Millisecond)
+			t.AssertLE(tx.Elapsed(), time.Since(begin))
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_Transaction_Context_Cancel(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		var cancelCtx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		err := db.Transaction(cancelCtx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+			t.AssertNil(err)
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				cancel()
+			}()
+			_, err = tx.Query("SELECT SLEEP(5)")
+			return err
+		})
+		t.AssertNE(err, nil)
+		t.Assert(errors.Is(err, context.Canceled), true)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 0)
+	})
+
+	// The context is cancelled before the transaction begins.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			called            bool
+			cancelCtx, cancel = context.WithCancel(ctx)
+		)
+		cancel()
+		err := db.Transaction(cancelCtx, func(ctx context.Context, tx gdb.TX) error {
+			called = true
+			return nil
+		})
+		t.Assert(errors.Is(err, context.Canceled), true)
+		t.Assert(called, false)
+	})
+}
+
+func Test_TX_SetStatementTimeout(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			tx.SetStatementTimeout(100 * time.Millisecond)
+			_, err := tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+			t.AssertNil(err)
+			_, err = tx.Query("SELECT SLEEP(5)")
+			t.AssertNE(err, nil)
+			t.Assert(errors.Is(err, context.DeadlineExceeded), true)
+			// The context of transaction is not affected by the statement timeout.
+			t.AssertNil(tx.GetCtx().Err())
+			return err
+		})
+		t.AssertNE(err, nil)
+		t.Assert(errors.Is(err, context.DeadlineExceeded), true)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 0)
+	})
+}
+
+func Test_TX_Update_ZeroValue_Fields(t *testing.T) {
+	table := fmt.Sprintf(`%s_%d`, TableName, gtime.TimestampNano())
+	if _, err := db.Exec(ctx, fmt.Sprintf(`
+	    CREATE TABLE %s (
+	        id     int(10) unsigned NOT NULL AUTO_INCREMENT,
+	        name   varchar(45) NULL,
+	        active tinyint(1) NOT NULL DEFAULT 0,
+	        count  int(10) NOT NULL DEFAULT 0,
+	        PRIMARY KEY (id)
+	    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+	    `, table,
+	)); err != nil {
+		gtest.Fatal(err)
+	}
+	defer dropTable(table)
+
+	type User struct {
+		Id     int    `json:"id,omitempty"`
+		Name   string `json:"name,omitempty"`
+		Active bool   `json:"active,omitempty"`
+		Count  int    `json:"count,omitempty"`
+	}
+	_, err := db.Insert(ctx, table, g.Map{"id": 1, "name": "john", "active": 1, "count": 10})
+	gtest.AssertNil(err)
+
+	// Zero values are omitted without Fields.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Model(table).Where("id", 1).Update(User{Name: "smith"})
+			return err
+		})
+		t.AssertNil(err)
+
+		one, err := db.Model(table).WherePri(1).One()
+		t.AssertNil(err)
+		t.Assert(one["name"], "smith")
+		t.Assert(one["active"], 1)
+		t.Assert(one["count"], 10)
+	})
+	// Zero values of specified fields are written.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Model(table).Fields("active", "count").Where("id", 1).Update(User{Name: "mike"})
+			return err
+		})
+		t.AssertNil(err)
+
+		one, err := db.Model(table).WherePri(1).One()
+		t.AssertNil(err)
+		t.Assert(one["name"], "smith")
+		t.Assert(one["active"], 0)
+		t.Assert(one["count"], 0)
+	})
+	// Specified fields are kept with OmitEmpty.
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Data(g.Map{"active": 1, "count": 10}).Where("id", 1).Update()
+		t.AssertNil(err)
+
+		err = db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Model(table).OmitEmpty().Fields("name", "count").Where("id", 1).Update(g.Map{
+				"name":  "tom",
+				"count": 0,
+			})
+			return err
+		})
+		t.AssertNil(err)
+
+		one, err := db.Model(table).WherePri(1).One()
+		t.AssertNil(err)
+		t.Assert(one["name"], "tom")
+		t.Assert(one["active"], 1)
+		t.Assert(one["count"], 0)
+	})
+}
+
+func Test_TX_Model_WithIdentityMap(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	type User struct {
+		Id       int
+		Passport string
+		Nickname string
+	}
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			var user1, user2, user3 *User
+			t.AssertNil(tx.Model(table).WithIdentityMap().Find(1, &user1))
+			t.Assert(user1.Passport, "user_1")
+			count := tx.StatementCount()
+
+			// Loaded from identity map.
+			t.AssertNil(tx.Model(table).WithIdentityMap().Find(1, &user2))
+			t.Assert(user2.Passport, "user_1")
+			t.Assert(tx.StatementCount(), count)
+
+			// Identity map disabled.
+			t.AssertNil(tx.Model(table).Find(1, &user3))
+			t.Assert(user3.Passport, "user_1")
+			t.Assert(tx.StatementCount(), count+1)
+
+			// Not found.
+			var user4 *User
+			t.AssertNil(tx.Model(table).WithIdentityMap().Find(TableSize+1, &user4))
+			t.Assert(user4, nil)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+	// Identity map is cleared after transaction.
+	gtest.C(t, func(t *gtest.T) {
+		var user *User
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			return tx.Model(table).WithIdentityMap().Find(2, &user)
+		})
+		t.AssertNil(err)
+		t.Assert(user.Passport, "user_2")
+
+		_, err = db.Model(table).Data("passport", "updated").WherePri(2).Update()
+		t.AssertNil(err)
+
+		err = db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			return tx.Model(table).WithIdentityMap().Find(2, &user)
+		})
+		t.AssertNil(err)
+		t.Assert(user.Passport, "updated")
+	})
+}
+
+func Test_TX_OnCommitted_OnRolledBack(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		var events = garray.NewStrArray()
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		tx.OnCommitted(func(err error) {
+			events.Append(fmt.Sprintf("commit:%v:%v", tx.IsClosed(), err == nil))
+		})
+		tx.OnRolledBack(func(err error) {
+			events.Append(fmt.Sprintf("rollback:%v:%v", tx.IsClosed(), err == nil))
+		})
+		err = tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			return nil
+		})
+		t.AssertNil(err)
+		err = tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			return gerror.New("rollback")
+		})
+		t.AssertNE(err, nil)
+		t.AssertNil(tx.Commit())
+		// Commit on closed transaction fails.
+		t.AssertNE(tx.Commit(), nil)
+		t.Assert(events.Slice(), []string{
+			"commit:false:true",
+			"rollback:false:true",
+			"commit:true:true",
+			"commit:true:false",
+		})
+	})
+}
+
+func Test_TX_Checkpoint_AffectedSince(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.AffectedSince("not_exist")
+			t.AssertNE(err, nil)
+
+			// Phase 1: inserting.
+			tx.Checkpoint("phase1")
+			_, err = tx.Model(table).Data(g.List{
+				{"id": 1, "passport": "user_1"},
+				{"id": 2, "passport": "user_2"},
+				{"id": 3, "passport": "user_3"},
+			}).Insert()
+			t.AssertNil(err)
+
+			// Phase 2: updating and deleting.
+			tx.Checkpoint("phase2")
+			_, err = tx.Model(table).Data("nickname", "updated").Where("id<?", 3).Update()
+			t.AssertNil(err)
+			_, err = tx.Model(table).Where("id", 3).Delete()
+			t.AssertNil(err)
+			// Query statements affect nothing.
+			_, err = tx.Model(table).All()
+			t.AssertNil(err)
+
+			affected, err := tx.AffectedSince("phase1")
+			t.AssertNil(err)
+			t.Assert(affected, 6)
+			affected, err = tx.AffectedSince("phase2")
+			t.AssertNil(err)
+			t.Assert(affected, 3)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_TX_GetCtx_Id(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var ids = garray.NewStrArray()
+		for i := 0; i < 2; i++ {
+			err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				t.Assert(tx.GetCtx().Value("TransactionId"), tx.Id())
+				t.AssertNE(tx.Id(), "")
+				ids.Append(tx.Id())
+				return nil
+			})
+			t.AssertNil(err)
+		}
+		t.AssertNE(ids.At(0), ids.At(1))
+	})
+}
+
+func Test_TX_Batch_DefaultSize(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	// It is not batched if the batch size is neither specified nor configured.
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(db.GetConfig().BatchSize, 0)
+
+		var list = make(g.List, 0)
+		for i := 1; i <= 1200; i++ {
+			list = append(list, g.Map{
+				"id":       i,
+				"passport": fmt.Sprintf(`user_%d`, i),
+			})
+		}
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			// Warm up the table fields cache.
+			_, err := tx.Model(table).Fields("id").All()
+			t.AssertNil(err)
+			count := tx.StatementCount()
+			result, err := tx.Insert(table, list)
+			t.AssertNil(err)
+			n, _ := result.RowsAffected()
+			t.Assert(n, 1200)
+			t.Assert(tx.StatementCount()-count, 1)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		var list = make(g.List, 0)
+		for i := 3001; i <= 3501; i++ {
+			list = append(list, g.Map{
+				"id":       i,
+				"passport": fmt.Sprintf(`user_%d`, i),
+			})
+		}
+		// Warm up the table fields cache.
+		_, err := db.Model(table).Fields("id").All()
+		t.AssertNil(err)
+		sqlArray, err := gdb.CatchSQL(ctx, func(ctx context.Context) error {
+			_, err := db.Model(table).Ctx(ctx).Data(list).Insert()
+			return err
+		})
+		t.AssertNil(err)
+		var insertCount int
+		for _, s := range sqlArray {
+			if gstr.HasPrefix(s, "INSERT") {
+				insertCount++
+			}
+		}
+		t.Assert(insertCount, 1)
+		count, err := db.Model(table).Where("id>=?", 3001).Where("id<=?", 3501).Count()
+		t.AssertNil(err)
+		t.Assert(count, 501)
+	})
+
+	// The configured batch size applies to the batch operations of transaction.
+	gtest.C(t, func(t *gtest.T) {
+		var batchSize = db.GetConfig().BatchSize
+		db.GetConfig().BatchSize = 100
+		defer func() {
+			db.GetConfig().BatchSize = batchSize
+		}()
+
+		var list = make(g.List, 0)
+		for i := 2001; i <= 2250; i++ {
+			list = append(list, g.Map{
+				"id":       i,
+				"passport": fmt.Sprintf(`user_%d`, i),
+			})
+		}
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Model(table).Fields("id").All()
+			t.AssertNil(err)
+			count := tx.StatementCount()
+			_, err = tx.Insert(table, list)
+			t.AssertNil(err)
+			t.Assert(tx.StatementCount()-count, 3)
+
+			// Specified batch takes precedence over the configured one.
+			count = tx.StatementCount()
+			_, err = tx.Replace(table, list, 50)
+			t.AssertNil(err)
+			t.Assert(tx.StatementCount()-count, 5)
+
+			// Model is batched only if Model.Batch is specified.
+			count = tx.StatementCount()
+			_, err = tx.Model(table).Data(list).Save()
+			t.AssertNil(err)
+			t.Assert(tx.StatementCount()-count, 1)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_TX_Batch_Failure(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		var list = make(g.List, 0)
+		for i := 1; i <= 10; i++ {
+			list = append(list, g.Map{
+				"id":       i,
+				"passport": fmt.Sprintf(`user_%d`, i),
+			})
+		}
+		// Duplicated primary key in the third batch.
+		list[7]["id"] = 1
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Model(table).Data(list).Batch(3).Insert()
+			return err
+		})
+		t.AssertNE(err, nil)
+		t.Assert(gstr.Contains(err.Error(), "batch 2 failed at offset 6"), true)
+
+		var mysqlErr *mysqldriver.MySQLError
+		t.Assert(errors.As(err, &mysqlErr), true)
+
+		failure := gdb.GetBatchFailure(err)
+		t.AssertNE(failure, nil)
+		t.Assert(failure.BatchIndex, 2)
+		t.Assert(failure.Offset, 6)
+		t.Assert(failure.Affected, 6)
+
+		// The succeeded batches are rolled back with the transaction.
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 0)
+	})
+
+	// No batch failure detail if the records are not split into batches.
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Model(table).Data(g.List{
+			{"id": 1, "passport": "user_1"},
+			{"id": 1, "passport": "user_1"},
+		}).Batch(3).Insert()
+		t.AssertNE(err, nil)
+		t.AssertNil(gdb.GetBatchFailure(err))
+	})
+}
+
+func Test_Transaction_Managed_Commit_Rollback(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	// Commit/Rollback the managed transaction in closure.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			if _, err := tx.Insert(table, g.Map{"id": 1, "passport": "user_1"}); err != nil {
+				return err
+			}
+			err := tx.Commit()
+			t.AssertNE(err, nil)
+			t.Assert(gerror.Code(err), gcode.CodeInvalidOperation)
+			t.Assert(err.Error(), "cannot commit a managed transaction")
+			err = tx.Rollback()
+			t.AssertNE(err, nil)
+			t.Assert(err.Error(), "cannot rollback a managed transaction")
+			t.Assert(tx.IsClosed(), false)
+
+			return tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				t.Assert(tx.Commit().Error(), "cannot commit a managed transaction")
+				t.Assert(tx.Rollback().Error(), "cannot rollback a managed transaction")
+				_, err := tx.Insert(table, g.Map{"id": 2, "passport": "user_2"})
+				return err
+			})
+		})
+		t.AssertNil(err)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 2)
+	})
+
+	// Nested transactions begun by user in closure.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			t.AssertNil(tx.Begin())
+			if _, err := tx.Insert(table, g.Map{"id": 3, "passport": "user_3"}); err != nil {
+				return err
+			}
+			t.AssertNil(tx.Rollback())
+
+			t.AssertNil(tx.Begin())
+			if _, err := tx.Insert(table, g.Map{"id": 4, "passport": "user_4"}); err != nil {
+				return err
+			}
+			t.AssertNil(tx.Commit())
+
+			// Back to the managed level.
+			t.AssertNE(tx.Commit(), nil)
+			return nil
+		})
+		t.AssertNil(err)
+
+		ids, err := db.Model(table).OrderAsc("id").Array("id")
+		t.AssertNil(err)
+		t.Assert(ids, g.Slice{1, 2, 4})
+	})
+}
+
+func Test_TX_QueryWithContext_ExecWithContext(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			cancelledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+			_, err := tx.QueryWithContext(cancelledCtx, fmt.Sprintf("SELECT * FROM %s WHERE id=1", table))
+			t.Assert(errors.Is(err, context.Canceled), true)
+			_, err = tx.ExecWithContext(cancelledCtx, fmt.Sprintf("UPDATE %s SET nickname='name' WHERE id=1", table))
+			t.Assert(errors.Is(err, context.Canceled), true)
+
+			// The context of transaction is untouched.
+			t.AssertNil(tx.GetCtx().Err())
+
+			timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			result, err := tx.ExecWithContext(timeoutCtx, fmt.Sprintf("UPDATE %s SET nickname='name' WHERE id=1", table))
+			t.AssertNil(err)
+			n, _ := result.RowsAffected()
+			t.Assert(n, 1)
+			all, err := tx.QueryWithContext(timeoutCtx, fmt.Sprintf("SELECT * FROM %s WHERE id=1", table))
+			t.AssertNil(err)
+			t.Assert(all[0]["nickname"], "name")
+			return nil
+		})
+		t.AssertNil(err)
+
+		value, err := db.Model(table).Where("id", 1).Value("nickname")
+		t.AssertNil(err)
+		t.Assert(value, "name")
+	})
+
+	// The explicit context does not leak into subsequent statements.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			var probeCtx = context.WithValue(context.Background(), "Probe", "probe")
+			_, err := tx.ExecWithContext(probeCtx, fmt.Sprintf("UPDATE %s SET nickname='probe' WHERE id=2", table))
+			t.AssertNil(err)
+			t.AssertNil(tx.GetCtx().Value("Probe"))
+
+			// The statement with explicit context is still in the transaction.
+			value, err := tx.Model(table).Where("id", 2).Value("nickname")
+			t.AssertNil(err)
+			t.Assert(value, "probe")
+			return gerror.New("rollback")
+		})
+		t.AssertNE(err, nil)
+
+		value, err := db.Model(table).Where("id", 2).Value("nickname")
+		t.AssertNil(err)
+		t.AssertNE(value, "probe")
+	})
+}
+
+func Test_TX_Debug(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	var buffer = bytes.NewBuffer(nil)
+	db.GetLogger().(*glog.Logger).SetWriter(buffer)
+	defer db.GetLogger().(*glog.Logger).SetWriter(os.Stdout)
+
+	// Enabled for transaction when db debug is disabled.
+	gtest.C(t, func(t *gtest.T) {
+		buffer.Reset()
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			tx.Debug(true)
+			_, err := tx.Query(fmt.Sprintf("SELECT * FROM %s WHERE id=1", table))
+			return err
+		})
+		t.AssertNil(err)
+		_, err = db.Query(ctx, fmt.Sprintf("SELECT * FROM %s WHERE id=2", table))
+		t.AssertNil(err)
+		t.Assert(gstr.Contains(buffer.String(), "WHERE id=1"), true)
+		t.Assert(gstr.Contains(buffer.String(), "COMMIT"), true)
+		t.Assert(gstr.Contains(buffer.String(), "WHERE id=2"), false)
+	})
+
+	// Following db debug setting in default.
+	gtest.C(t, func(t *gtest.T) {
+		buffer.Reset()
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Query(fmt.Sprintf("SELECT * FROM %s WHERE id=3", table))
+			return err
+		})
+		t.AssertNil(err)
+		t.Assert(gstr.Contains(buffer.String(), "WHERE id=3"), false)
+	})
+
+	// Disabled for transaction when db debug is enabled.
+	gtest.C(t, func(t *gtest.T) {
+		db.SetDebug(true)
+		defer db.SetDebug(false)
+		buffer.Reset()
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			tx.Debug(false)
+			_, err := tx.Query(fmt.Sprintf("SELECT * FROM %s WHERE id=4", table))
+			return err
+		})
+		t.AssertNil(err)
+		t.Assert(gstr.Contains(buffer.String(), "WHERE id=4"), false)
+	})
+}
+
+func Test_TX_Logger_TransactionId(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	var buffer = bytes.NewBuffer(nil)
+	db.GetLogger().(*glog.Logger).SetWriter(buffer)
+	defer db.GetLogger().(*glog.Logger).SetWriter(os.Stdout)
+	db.SetDebug(true)
+	defer db.SetDebug(false)
+
+	gtest.C(t, func(t *gtest.T) {
+		var txId string
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			txId = tx.Id()
+			if _, err := tx.Query(fmt.Sprintf("SELECT * FROM %s WHERE id=1", table)); err != nil {
+				return err
+			}
+			return tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				_, err := tx.Query(fmt.Sprintf("SELECT * FROM %s WHERE id=2", table))
+				return err
+			})
+		})
+		t.AssertNil(err)
+
+		var content = buffer.String()
+		t.Assert(gstr.Contains(content, fmt.Sprintf("[txid:%s:0] BEGIN", txId)), true)
+		t.Assert(gstr.Contains(content, fmt.Sprintf("[txid:%s:0] SELECT * FROM %s WHERE id=1", txId, table)), true)
+		t.Assert(gstr.Contains(content, fmt.Sprintf("[txid:%s:1] SELECT * FROM %s WHERE id=2", txId, table)), true)
+		t.Assert(gstr.Contains(content, fmt.Sprintf("[txid:%s:0] COMMIT", txId)), true)
+	})
 }
 
-func Test_TX_Exec(t *testing.T) {
-	tx, err := db.Begin(ctx)
-	if err != nil {
-		gtest.Error(err)
-	}
-	if _, err := tx.Exec("SELECT ?", 1); err != nil {
-		gtest.Error(err)
-	}
-	if _, err := tx.Exec("SELECT ?+?", 1, 2); err != nil {
-		gtest.Error(err)
-	}
-	if _, err := tx.Exec("SELECT ?+?", g.Slice{1, 2}); err != nil {
-		gtest.Error(err)
-	}
-	if _, err := tx.Exec("ERROR"); err == nil {
-		gtest.Error("FAIL")
-	}
-	if err := tx.Commit(); err != nil {
-		gtest.Error(err)
-	}
+func Test_TX_XA(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	// Two-phase commit.
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.BeginXA(ctx, fmt.Sprintf(`gf_xa_%d`, gtime.TimestampNano()))
+		t.AssertNil(err)
+		t.Assert(tx.IsXA(), true)
+		_, err = tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+		t.AssertNil(err)
+		// Commit is not available for XA transaction.
+		t.AssertNE(tx.Commit(), nil)
+		t.AssertNil(tx.EndXA())
+		t.AssertNil(tx.PrepareXA())
+		t.AssertNil(tx.CommitXA(false))
+		t.Assert(tx.IsClosed(), true)
+		t.AssertNE(tx.CommitXA(false), nil)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 1)
+	})
+
+	// One-phase commit.
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.BeginXA(ctx, fmt.Sprintf(`gf_xa_%d`, gtime.TimestampNano()))
+		t.AssertNil(err)
+		_, err = tx.Insert(table, g.Map{"id": 2, "passport": "user_2"})
+		t.AssertNil(err)
+		t.AssertNil(tx.EndXA())
+		t.AssertNil(tx.CommitXA(true))
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 2)
+	})
+
+	// Rollback without ending.
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.BeginXA(ctx, fmt.Sprintf(`gf_xa'%d`, gtime.TimestampNano()))
+		t.AssertNil(err)
+		_, err = tx.Insert(table, g.Map{"id": 3, "passport": "user_3"})
+		t.AssertNil(err)
+		t.AssertNil(tx.Rollback())
+		t.Assert(tx.IsClosed(), true)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 2)
+	})
+
+	// Local transaction.
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		t.Assert(tx.IsXA(), false)
+		t.AssertNE(tx.EndXA(), nil)
+	})
 }
 
-func Test_TX_Commit(t *testing.T) {
-	tx, err := db.Begin(ctx)
-	if err != nil {
-		gtest.Error(err)
-	}
-	if err := tx.Commit(); err != nil {
-		gtest.Error(err)
-	}
+func Test_TX_Model_Ctx(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		var txCtx = context.WithValue(ctx, "TraceId", "trace_tx")
+		err := db.Transaction(txCtx, func(ctx context.Context, tx gdb.TX) error {
+			model := tx.Model(table)
+			t.Assert(model.GetCtx().Value("TraceId"), "trace_tx")
+			t.Assert(model.GetCtx().Value("TransactionId"), tx.Id())
+			one, err := model.Where("id", 1).One()
+			t.AssertNil(err)
+			t.Assert(one["id"], 1)
+
+			// Explicit context overrides the context of transaction.
+			var modelCtx = context.WithValue(ctx, "TraceId", "trace_model")
+			model = tx.Model(table).Ctx(modelCtx)
+			t.Assert(model.GetCtx().Value("TraceId"), "trace_model")
+			return nil
+		})
+		t.AssertNil(err)
+	})
 }
 
-func Test_TX_Rollback(t *testing.T) {
-	tx, err := db.Begin(ctx)
-	if err != nil {
-		gtest.Error(err)
-	}
-	if err := tx.Rollback(); err != nil {
-		gtest.Error(err)
-	}
+func Test_TX_ExecResult(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			result, err := tx.ExecResult(
+				fmt.Sprintf("INSERT INTO %s(passport, nickname) VALUES(?, ?)", table),
+				"user_100", "name_100",
+			)
+			t.AssertNil(err)
+			t.Assert(result.RowsAffected, 1)
+			t.Assert(result.LastInsertId, TableSize+1)
+			t.AssertNil(result.LastInsertIdErr)
+
+			result, err = tx.ExecResult(fmt.Sprintf("UPDATE %s SET nickname='name' WHERE id<=?", table), 3)
+			t.AssertNil(err)
+			t.Assert(result.RowsAffected, 3)
+			t.Assert(result.LastInsertId, 0)
+			t.AssertNil(result.LastInsertIdErr)
+
+			result, err = tx.ExecResult(fmt.Sprintf("UPDATE %s_not_exist SET nickname='name'", table))
+			t.AssertNE(err, nil)
+			t.AssertNil(result)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_TX_UnderlyingTx(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+
+		sqlTx, err := tx.UnderlyingTx()
+		t.AssertNil(err)
+		_, err = sqlTx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET passport='raw_tx' WHERE id=1", table))
+		t.AssertNil(err)
+
+		// The statement executed on the raw transaction belongs to current transaction.
+		value, err := tx.GetValue(fmt.Sprintf("SELECT passport FROM %s WHERE id=1", table))
+		t.AssertNil(err)
+		t.Assert(value.String(), "raw_tx")
+		t.AssertNil(tx.Rollback())
+
+		value, err = db.GetValue(ctx, fmt.Sprintf("SELECT passport FROM %s WHERE id=1", table))
+		t.AssertNil(err)
+		t.Assert(value.String(), "user_1")
+
+		// Closed transaction.
+		sqlTx, err = tx.UnderlyingTx()
+		t.AssertNE(err, nil)
+		t.Assert(sqlTx, nil)
+		t.Assert(gerror.Code(err), gcode.CodeInvalidOperation)
+	})
 }
 
 func Test_TX_Prepare(t *testing.T) {
@@ -106,6 +1493,55 @@ func Test_TX_Prepare(t *testing.T) {
 	})
 }
 
+func Test_TX_Prepare_Cache(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			querySql := fmt.Sprintf("SELECT passport FROM %s WHERE id=?", table)
+			st1, err := tx.Prepare(querySql)
+			t.AssertNil(err)
+			// The cached statement is not closed by Close.
+			t.AssertNil(st1.Close())
+
+			st2, err := tx.Prepare(querySql)
+			t.AssertNil(err)
+			t.Assert(st1 == st2, true)
+
+			var passport string
+			t.AssertNil(st2.QueryRow(1).Scan(&passport))
+			t.Assert(passport, "user_1")
+
+			st3, err := tx.Prepare(fmt.Sprintf("SELECT nickname FROM %s WHERE id=?", table))
+			t.AssertNil(err)
+			t.Assert(st3 == st1, false)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+	// Cache disabled.
+	gtest.C(t, func(t *gtest.T) {
+		db.GetConfig().TxStmtCacheDisabled = true
+		defer func() {
+			db.GetConfig().TxStmtCacheDisabled = false
+		}()
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			querySql := fmt.Sprintf("SELECT passport FROM %s WHERE id=?", table)
+			st1, err := tx.Prepare(querySql)
+			t.AssertNil(err)
+			defer st1.Close()
+
+			st2, err := tx.Prepare(querySql)
+			t.AssertNil(err)
+			defer st2.Close()
+			t.Assert(st1 == st2, false)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
 func Test_TX_Insert(t *testing.T) {
 	table := createTable()
 	defer dropTable(table)
@@ -147,6 +1583,106 @@ func Test_TX_Insert(t *testing.T) {
 	})
 }
 
+func Test_TX_InsertAndReturning(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			// MySQL falls back to querying the inserted record by the last insert id.
+			one, err := tx.InsertAndReturning(table, g.Map{
+				"passport": "t1",
+				"nickname": "T1",
+			})
+			t.AssertNil(err)
+			t.Assert(one["id"].Int(), 1)
+			t.Assert(one["passport"].String(), "t1")
+			t.Assert(one["nickname"].String(), "T1")
+
+			// Specified columns.
+			one, err = tx.InsertAndReturning(table, g.Map{
+				"passport": "t2",
+				"nickname": "T2",
+			}, "id", "passport")
+			t.AssertNil(err)
+			t.Assert(len(one), 2)
+			t.Assert(one["id"].Int(), 2)
+			t.Assert(one["passport"].String(), "t2")
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_TX_InsertAndReturn(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	type User struct {
+		Id       int
+		Passport string
+	}
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			var user *User
+			// MySQL does not support RETURNING clause, and nothing is inserted.
+			err := tx.InsertAndReturn(table, g.Map{
+				"passport": "t1",
+				"nickname": "T1",
+			}, []string{"id", "passport"}, &user)
+			t.AssertNE(err, nil)
+			t.Assert(gerror.Code(err), gcode.CodeNotSupported)
+			t.Assert(user, nil)
+
+			count, err := tx.Model(table).Count()
+			t.AssertNil(err)
+			t.Assert(count, 0)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_TX_InsertIgnoreResult(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+
+		inserted, err := tx.InsertIgnoreResult(table, g.Map{
+			"id":       TableSize + 1,
+			"passport": "t_inserted",
+			"nickname": "T_INSERTED",
+		})
+		t.AssertNil(err)
+		t.Assert(inserted, true)
+
+		n, err := tx.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(n, TableSize+1)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+
+		inserted, err := tx.InsertIgnoreResult(table, g.Map{
+			"id":       1,
+			"passport": "t_duplicated",
+			"nickname": "T_DUPLICATED",
+		})
+		t.AssertNil(err)
+		t.Assert(inserted, false)
+
+		value, err := tx.Model(table).Where("id", 1).Value("passport")
+		t.AssertNil(err)
+		t.Assert(value, "user_1")
+	})
+}
+
 func Test_TX_BatchInsert(t *testing.T) {
 	table := createTable()
 	defer dropTable(table)
@@ -436,6 +1972,63 @@ func Test_TX_GetCount(t *testing.T) {
 	})
 }
 
+func Test_TX_GetCount_Complex(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+
+		// Count function.
+		count, err := tx.GetCount(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id>?", table), 5)
+		t.AssertNil(err)
+		t.Assert(count, TableSize-5)
+
+		// Sub query in fields.
+		count, err = tx.GetCount(fmt.Sprintf(
+			"SELECT id, (SELECT passport FROM %s WHERE id=1) AS first FROM %s WHERE id<=?", table, table,
+		), 3)
+		t.AssertNil(err)
+		t.Assert(count, 3)
+
+		// Sub query in condition.
+		count, err = tx.GetCount(fmt.Sprintf(
+			"SELECT * FROM %s WHERE id IN (SELECT id FROM %s WHERE id>?)", table, table,
+		), 8)
+		t.AssertNil(err)
+		t.Assert(count, TableSize-8)
+
+		// DISTINCT.
+		_, err = tx.Update(table, g.Map{"nickname": "same"}, "id<=?", 4)
+		t.AssertNil(err)
+		count, err = tx.GetCount(fmt.Sprintf("SELECT DISTINCT nickname FROM %s", table))
+		t.AssertNil(err)
+		t.Assert(count, TableSize-3)
+
+		// GROUP BY.
+		count, err = tx.GetCount(fmt.Sprintf("SELECT nickname, COUNT(1) FROM %s GROUP BY nickname", table))
+		t.AssertNil(err)
+		t.Assert(count, TableSize-3)
+
+		// Grouped aggregate starting with COUNT, which counts the groups rather than the first group.
+		count, err = tx.GetCount(fmt.Sprintf("SELECT COUNT(1) AS total FROM %s GROUP BY nickname", table))
+		t.AssertNil(err)
+		t.Assert(count, TableSize-3)
+
+		// LIMIT.
+		count, err = tx.GetCount(fmt.Sprintf("SELECT * FROM %s ORDER BY id LIMIT 3", table))
+		t.AssertNil(err)
+		t.Assert(count, 3)
+
+		// ORDER BY.
+		count, err = tx.GetCount(fmt.Sprintf("SELECT id, passport FROM %s WHERE id>? ORDER BY id DESC", table), 2)
+		t.AssertNil(err)
+		t.Assert(count, TableSize-2)
+	})
+}
+
 func Test_TX_GetStruct(t *testing.T) {
 	table := createInitTable()
 	defer dropTable(table)
@@ -826,6 +2419,37 @@ func Test_Transaction_Nested_Begin_Rollback_Commit(t *testing.T) {
 	})
 }
 
+func Test_Transaction_Nested_Level(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		t.Assert(tx.NestedLevel(), 0)
+		t.Assert(tx.IsNested(), false)
+
+		t.AssertNil(tx.Begin())
+		t.Assert(tx.NestedLevel(), 1)
+		t.Assert(tx.IsNested(), true)
+
+		t.AssertNil(tx.Begin())
+		t.Assert(tx.NestedLevel(), 2)
+
+		t.AssertNil(tx.Rollback())
+		t.Assert(tx.NestedLevel(), 1)
+
+		err = tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			t.Assert(tx.NestedLevel(), 2)
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(tx.NestedLevel(), 1)
+
+		t.AssertNil(tx.Commit())
+		t.Assert(tx.NestedLevel(), 0)
+		t.Assert(tx.IsNested(), false)
+		t.AssertNil(tx.Commit())
+	})
+}
+
 func Test_Transaction_Nested_TX_Transaction_UseTX(t *testing.T) {
 	table := createTable()
 	defer dropTable(table)
@@ -1109,6 +2733,385 @@ func Test_Transaction_Nested_SavePoint_RollbackTo(t *testing.T) {
 	})
 }
 
+func Test_Transaction_Nested_SavePointFunc(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+			t.AssertNil(err)
+			// released.
+			err = tx.SavePointFunc("point1", func() error {
+				_, err = tx.Insert(table, g.Map{"id": 2, "passport": "user_2"})
+				t.AssertNil(err)
+				// nested and rolled back by error.
+				err = tx.SavePointFunc("point2", func() error {
+					_, err = tx.Insert(table, g.Map{"id": 3, "passport": "user_3"})
+					t.AssertNil(err)
+					return gerror.New("rollback")
+				})
+				t.AssertNE(err, nil)
+				return nil
+			})
+			t.AssertNil(err)
+			// rolled back by panic.
+			err = tx.SavePointFunc("point3", func() error {
+				_, err = tx.Insert(table, g.Map{"id": 4, "passport": "user_4"})
+				t.AssertNil(err)
+				panic("rollback")
+			})
+			t.AssertNE(err, nil)
+			return nil
+		})
+		t.AssertNil(err)
+
+		all, err := db.Model(table).OrderAsc("id").All()
+		t.AssertNil(err)
+		t.Assert(len(all), 2)
+		t.Assert(all[0]["id"], 1)
+		t.Assert(all[1]["id"], 2)
+	})
+}
+
+func Test_Transaction_Nested_TransactionNamed(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+			t.AssertNil(err)
+			// committed.
+			err = tx.TransactionNamed(ctx, "create_user", func(ctx context.Context, tx gdb.TX) error {
+				t.Assert(tx.SavePoints(), g.SliceStr{"create_user"})
+				t.Assert(tx.NestedLevel(), 1)
+				_, err = tx.Insert(table, g.Map{"id": 2, "passport": "user_2"})
+				t.AssertNil(err)
+				// The active save point of the same name cannot be reused.
+				err = tx.TransactionNamed(ctx, "create_user", func(ctx context.Context, tx gdb.TX) error {
+					return nil
+				})
+				t.AssertNE(err, nil)
+				// The save point is named by nesting level for anonymous nested transaction.
+				return tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+					t.Assert(tx.SavePoints(), g.SliceStr{"create_user", "transaction_" + tx.Id() + "_1"})
+					return nil
+				})
+			})
+			t.AssertNil(err)
+			t.Assert(len(tx.SavePoints()), 0)
+			// rolled back, and the name can be reused after the named transaction is finished.
+			err = tx.TransactionNamed(ctx, "create_user", func(ctx context.Context, tx gdb.TX) error {
+				_, err = tx.Insert(table, g.Map{"id": 3, "passport": "user_3"})
+				t.AssertNil(err)
+				return gerror.New("rollback")
+			})
+			t.AssertNE(err, nil)
+			t.Assert(len(tx.SavePoints()), 0)
+			// invalid name.
+			err = tx.TransactionNamed(ctx, "create_user`; DROP TABLE user", func(ctx context.Context, tx gdb.TX) error {
+				return nil
+			})
+			t.AssertNE(err, nil)
+			return nil
+		})
+		t.AssertNil(err)
+
+		all, err := db.Model(table).OrderAsc("id").All()
+		t.AssertNil(err)
+		t.Assert(len(all), 2)
+		t.Assert(all[0]["id"], 1)
+		t.Assert(all[1]["id"], 2)
+	})
+}
+
+func Test_Transaction_Nested_SavePointPrefix(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		t.Assert(tx.GetSavePointPrefix(), "transaction")
+
+		t.AssertNE(tx.SetSavePointPrefix(""), nil)
+		t.AssertNE(tx.SetSavePointPrefix("1sp"), nil)
+		t.AssertNE(tx.SetSavePointPrefix("sp`; DROP TABLE user"), nil)
+		t.AssertNil(tx.SetSavePointPrefix("app_sp"))
+		t.Assert(tx.GetSavePointPrefix(), "app_sp")
+
+		t.AssertNil(tx.Begin())
+		_, err = tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+		t.AssertNil(err)
+		// The save point of nested transaction is named with the custom prefix.
+		t.AssertNil(tx.RollbackTo("app_sp0"))
+		t.AssertNE(tx.SetSavePointPrefix("other_sp"), nil)
+		t.AssertNil(tx.Commit())
+
+		count, err := tx.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, 0)
+	})
+}
+
+func Test_Transaction_Nested_SavePoint_Interleaved(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+
+		_, err = tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+		t.AssertNil(err)
+		// Nested transaction at level 1.
+		t.AssertNil(tx.Begin())
+		_, err = tx.Insert(table, g.Map{"id": 2, "passport": "user_2"})
+		t.AssertNil(err)
+		// Manual save points using the names that collide with the legacy auto-generated ones.
+		t.AssertNil(tx.SavePoint("transaction0"))
+		t.AssertNil(tx.SavePoint("transaction1"))
+		_, err = tx.Insert(table, g.Map{"id": 3, "passport": "user_3"})
+		t.AssertNil(err)
+		// Nested transaction at level 2.
+		t.AssertNil(tx.Begin())
+		_, err = tx.Insert(table, g.Map{"id": 4, "passport": "user_4"})
+		t.AssertNil(err)
+		t.AssertNil(tx.Rollback())
+		t.AssertNil(tx.RollbackTo("transaction1"))
+		t.AssertNil(tx.Commit())
+
+		ids, err := tx.Model(table).OrderAsc("id").Array("id")
+		t.AssertNil(err)
+		t.Assert(ids, g.Slice{1, 2})
+
+		// Rollback to the auto-generated save point of level 1 is not affected by manual save points.
+		t.AssertNil(tx.Begin())
+		t.AssertNil(tx.SavePoint("transaction0"))
+		_, err = tx.Insert(table, g.Map{"id": 5, "passport": "user_5"})
+		t.AssertNil(err)
+		t.AssertNil(tx.Rollback())
+
+		ids, err = tx.Model(table).OrderAsc("id").Array("id")
+		t.AssertNil(err)
+		t.Assert(ids, g.Slice{1, 2})
+	})
+}
+
+func Test_Transaction_Nested_MaxDepth(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+
+		// Unlimited in default.
+		for i := 0; i < 5; i++ {
+			t.AssertNil(tx.Begin())
+		}
+		t.Assert(tx.NestedLevel(), 5)
+		for i := 0; i < 5; i++ {
+			t.AssertNil(tx.Commit())
+		}
+		t.Assert(tx.NestedLevel(), 0)
+
+		tx.SetMaxDepth(2)
+		t.AssertNil(tx.Begin())
+		t.AssertNil(tx.Begin())
+		t.Assert(tx.NestedLevel(), 2)
+		err = tx.Begin()
+		t.AssertNE(err, nil)
+		t.Assert(gerror.Code(err), gcode.CodeInvalidOperation)
+		t.Assert(gstr.Contains(err.Error(), "current depth is 2"), true)
+		t.Assert(tx.NestedLevel(), 2)
+
+		// Nested Transaction function is limited as well.
+		err = tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			return nil
+		})
+		t.AssertNE(err, nil)
+		t.Assert(tx.NestedLevel(), 2)
+	})
+}
+
+func Test_TX_SavePoints(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		t.AssertNil(tx.SetSavePointPrefix("nested"))
+		t.Assert(len(tx.SavePoints()), 0)
+
+		t.AssertNil(tx.SavePoint("p1"))
+		t.AssertNil(tx.Begin())
+		t.AssertNil(tx.SavePoint("p2"))
+		t.AssertNil(tx.SavePoint("p3"))
+		t.Assert(tx.SavePoints(), g.SliceStr{"p1", "nested0", "p2", "p3"})
+
+		// The save points created after the rolled back one are dropped.
+		t.AssertNil(tx.RollbackTo("p2"))
+		t.Assert(tx.SavePoints(), g.SliceStr{"p1", "nested0", "p2"})
+
+		// The save point of nested transaction is released with the ones created after it.
+		t.AssertNil(tx.Commit())
+		t.Assert(tx.SavePoints(), g.SliceStr{"p1"})
+
+		// The save point of the same name is replaced.
+		t.AssertNil(tx.Begin())
+		t.AssertNil(tx.SavePoint("p1"))
+		t.Assert(tx.SavePoints(), g.SliceStr{"nested0", "p1"})
+		t.AssertNil(tx.Rollback())
+		t.Assert(len(tx.SavePoints()), 0)
+
+		// The returned names are a copy.
+		t.AssertNil(tx.SavePoint("p4"))
+		points := tx.SavePoints()
+		points[0] = "changed"
+		t.Assert(tx.SavePoints(), g.SliceStr{"p4"})
+
+		// Invalid save point name is not tracked.
+		t.AssertNE(tx.SavePoint("p5;"), nil)
+		t.Assert(tx.SavePoints(), g.SliceStr{"p4"})
+	})
+
+	// SavePointFunc and closed transaction.
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		err = tx.SavePointFunc("p1", func() error {
+			t.Assert(tx.SavePoints(), g.SliceStr{"p1"})
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(len(tx.SavePoints()), 0)
+
+		t.AssertNil(tx.SavePoint("p2"))
+		t.AssertNil(tx.Commit())
+		t.Assert(len(tx.SavePoints()), 0)
+	})
+}
+
+func Test_TX_RollbackTo_Nested(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	// The nested transactions begun after the save point are finished.
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		t.AssertNil(tx.SetSavePointPrefix("nested"))
+
+		_, err = tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+		t.AssertNil(err)
+		t.AssertNil(tx.SavePoint("p1"))
+		t.AssertNil(tx.Begin())
+		_, err = tx.Insert(table, g.Map{"id": 2, "passport": "user_2"})
+		t.AssertNil(err)
+		t.AssertNil(tx.Begin())
+		t.AssertNil(tx.SavePoint("p2"))
+		_, err = tx.Insert(table, g.Map{"id": 3, "passport": "user_3"})
+		t.AssertNil(err)
+		t.Assert(tx.NestedLevel(), 2)
+
+		// The save point created in current nested transaction does not change the depth.
+		t.AssertNil(tx.RollbackTo("p2"))
+		t.Assert(tx.NestedLevel(), 2)
+		t.Assert(tx.SavePoints(), g.SliceStr{"p1", "nested0", "nested1", "p2"})
+
+		t.AssertNil(tx.RollbackTo("p1"))
+		t.Assert(tx.NestedLevel(), 0)
+		t.Assert(tx.SavePoints(), g.SliceStr{"p1"})
+
+		// The nested transactions work as usual after that.
+		t.AssertNil(tx.Begin())
+		_, err = tx.Insert(table, g.Map{"id": 4, "passport": "user_4"})
+		t.AssertNil(err)
+		t.AssertNil(tx.Commit())
+		t.Assert(tx.NestedLevel(), 0)
+
+		ids, err := tx.Model(table).OrderAsc("id").Array("id")
+		t.AssertNil(err)
+		t.Assert(ids, g.Slice{1, 4})
+	})
+	// The nesting depth is kept.
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		t.AssertNil(tx.SetSavePointPrefix("nested"))
+
+		_, err = tx.Insert(table, g.Map{"id": 1, "passport": "user_1"})
+		t.AssertNil(err)
+		t.AssertNil(tx.SavePoint("p1"))
+		t.AssertNil(tx.Begin())
+		_, err = tx.Insert(table, g.Map{"id": 2, "passport": "user_2"})
+		t.AssertNil(err)
+		t.AssertNil(tx.Begin())
+		_, err = tx.Insert(table, g.Map{"id": 3, "passport": "user_3"})
+		t.AssertNil(err)
+
+		t.AssertNil(tx.RollbackToSavePointKeeping("p1"))
+		t.Assert(tx.NestedLevel(), 2)
+		t.Assert(tx.SavePoints(), g.SliceStr{"p1", "nested0", "nested1"})
+
+		_, err = tx.Insert(table, g.Map{"id": 4, "passport": "user_4"})
+		t.AssertNil(err)
+		t.AssertNil(tx.Rollback())
+		t.Assert(tx.NestedLevel(), 1)
+		_, err = tx.Insert(table, g.Map{"id": 5, "passport": "user_5"})
+		t.AssertNil(err)
+		t.AssertNil(tx.Commit())
+		t.Assert(tx.NestedLevel(), 0)
+		t.Assert(tx.SavePoints(), g.SliceStr{"p1"})
+
+		ids, err := tx.Model(table).OrderAsc("id").Array("id")
+		t.AssertNil(err)
+		t.Assert(ids, g.Slice{1, 5})
+	})
+	// The managed nested transaction cannot be finished by RollbackTo.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			t.AssertNil(tx.SavePoint("p1"))
+			return tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+				err := tx.RollbackTo("p1")
+				t.AssertNE(err, nil)
+				t.Assert(gerror.Code(err), gcode.CodeInvalidOperation)
+				t.Assert(tx.NestedLevel(), 1)
+				return nil
+			})
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_Transaction_SavePoint_InvalidName(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		tx, err := db.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+
+		for _, point := range []string{"", "1point", "my point", "point`; DROP TABLE user; --", "point-1"} {
+			err = tx.SavePoint(point)
+			t.AssertNE(err, nil)
+			t.Assert(gerror.Code(err), gcode.CodeInvalidParameter)
+			err = tx.RollbackTo(point)
+			t.AssertNE(err, nil)
+			t.Assert(gerror.Code(err), gcode.CodeInvalidParameter)
+		}
+		t.AssertNil(tx.SavePoint("_my_point_1"))
+		t.AssertNil(tx.RollbackTo("_my_point_1"))
+	})
+}
+
 func Test_Transaction_Method(t *testing.T) {
 	table := createTable()
 	defer dropTable(table)
@@ -1139,3 +3142,143 @@ func Test_Transaction_Method(t *testing.T) {
 		t.Assert(count, int64(0))
 	})
 }
+
+func Test_Model_TransactionWithModel(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Model(table).TransactionWithModel(ctx, func(ctx context.Context, tx gdb.TX, model *gdb.Model) error {
+			_, err := model.Data(g.Map{
+				"id":       1,
+				"passport": "t1",
+			}).Insert()
+			t.AssertNil(err)
+			_, err = model.Data(g.Map{
+				"id":       2,
+				"passport": "t2",
+			}).Insert()
+			t.AssertNil(err)
+			count, err := model.Count()
+			t.AssertNil(err)
+			t.Assert(count, int64(2))
+			return gerror.New("rollback")
+		})
+		t.AssertNE(err, nil)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, int64(0))
+	})
+	// Reuse the transaction in context.
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			return db.Model(table).TransactionWithModel(ctx, func(ctx context.Context, tx2 gdb.TX, model *gdb.Model) error {
+				t.Assert(tx2.Id(), tx.Id())
+				_, err := model.Data(g.Map{
+					"id":       1,
+					"passport": "t1",
+				}).Insert()
+				return err
+			})
+		})
+		t.AssertNil(err)
+
+		count, err := db.Model(table).Count()
+		t.AssertNil(err)
+		t.Assert(count, int64(1))
+	})
+}
+
+func Test_TXGroup(t *testing.T) {
+	var (
+		table1 = createTable()
+		table2 = fmt.Sprintf(`%s_%d`, TableName, gtime.TimestampNano())
+	)
+	createTableWithDb(dbPrefix, TableNamePrefix1+table2)
+	defer dropTable(table1)
+	defer dropTableWithDb(dbPrefix, TableNamePrefix1+table2)
+
+	// Commit all.
+	gtest.C(t, func(t *gtest.T) {
+		err := gdb.NewTXGroup(db, dbPrefix).Transaction(ctx, func(ctx context.Context, txs map[string]gdb.TX) error {
+			t.Assert(len(txs), 2)
+			t.Assert(txs[db.GetGroup()].IsXA(), true)
+			t.Assert(txs[dbPrefix.GetGroup()].IsXA(), true)
+			_, err := db.Model(table1).Ctx(ctx).Data(g.Map{"id": 1, "passport": "user_1"}).Insert()
+			t.AssertNil(err)
+			_, err = dbPrefix.Model(table2).Ctx(ctx).Data(g.Map{"id": 1, "passport": "user_1"}).Insert()
+			t.AssertNil(err)
+			return nil
+		})
+		t.AssertNil(err)
+
+		count, err := db.Model(table1).Count()
+		t.AssertNil(err)
+		t.Assert(count, 1)
+		count, err = dbPrefix.Model(table2).Count()
+		t.AssertNil(err)
+		t.Assert(count, 1)
+	})
+	// Rollback all.
+	gtest.C(t, func(t *gtest.T) {
+		err := gdb.NewTXGroup(db, dbPrefix).Transaction(ctx, func(ctx context.Context, txs map[string]gdb.TX) error {
+			_, err := db.Model(table1).Ctx(ctx).Data(g.Map{"id": 2, "passport": "user_2"}).Insert()
+			t.AssertNil(err)
+			_, err = dbPrefix.Model(table2).Ctx(ctx).Data(g.Map{"id": 2, "passport": "user_2"}).Insert()
+			t.AssertNil(err)
+			return gerror.New("rollback")
+		})
+		t.AssertNE(err, nil)
+
+		count, err := db.Model(table1).Count()
+		t.AssertNil(err)
+		t.Assert(count, 1)
+		count, err = dbPrefix.Model(table2).Count()
+		t.AssertNil(err)
+		t.Assert(count, 1)
+	})
+}
+
+func Test_SlowLogThreshold(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	var buffer = bytes.NewBuffer(nil)
+	db.GetLogger().(*glog.Logger).SetWriter(buffer)
+	defer db.GetLogger().(*glog.Logger).SetWriter(os.Stdout)
+
+	db.GetConfig().SlowLogThreshold = 50 * time.Millisecond
+	defer func() {
+		db.GetConfig().SlowLogThreshold = 0
+	}()
+
+	gtest.C(t, func(t *gtest.T) {
+		_, err := db.Query(ctx, "SELECT SLEEP(0.1)")
+		t.AssertNil(err)
+		_, err = db.Query(ctx, fmt.Sprintf("SELECT * FROM %s WHERE id=1", table))
+		t.AssertNil(err)
+		t.Assert(gstr.Count(buffer.String(), "slow=true"), 1)
+		t.Assert(gstr.Contains(buffer.String(), "[WARN]"), true)
+		t.Assert(gstr.Contains(buffer.String(), "SELECT SLEEP(0.1)"), true)
+	})
+	// Transaction statements.
+	gtest.C(t, func(t *gtest.T) {
+		buffer.Reset()
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Exec(fmt.Sprintf("UPDATE %s SET nickname=SLEEP(0.1) WHERE id=1", table))
+			return err
+		})
+		t.AssertNil(err)
+		t.Assert(gstr.Count(buffer.String(), "slow=true"), 1)
+		t.Assert(gstr.Contains(buffer.String(), "[txid:"), true)
+	})
+	// Disabled.
+	gtest.C(t, func(t *gtest.T) {
+		buffer.Reset()
+		db.GetConfig().SlowLogThreshold = 0
+		_, err := db.Query(ctx, "SELECT SLEEP(0.1)")
+		t.AssertNil(err)
+		t.Assert(gstr.Contains(buffer.String(), "slow=true"), false)
+	})
+}
@@ -8,7 +8,7 @@
 //
 // Note:
 // 1. It does not support Replace features.
-// 2. It does not support Insert Ignore features.
+// 2. It implements Insert Ignore features using "ON CONFLICT DO NOTHING" statement.
 package pgsql
 
 import (
@@ -24,9 +24,10 @@ type Driver struct {
 }
 
 const (
-	internalPrimaryKeyInCtx gctx.StrKey = "primary_key"
-	defaultSchema           string      = "public"
-	quoteChar               string      = `"`
+	internalPrimaryKeyInCtx   gctx.StrKey = "primary_key"
+	internalInsertIgnoreInCtx gctx.StrKey = "insert_ignore"
+	defaultSchema             string      = "public"
+	quoteChar                 string      = `"`
 )
 
 func init() {
@@ -0,0 +1,61 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+const (
+	deadlockDetailSql = `
+SELECT pid, COALESCE(state, ''), COALESCE(wait_event_type, ''), COALESCE(wait_event, ''), COALESCE(query, '')
+FROM pg_stat_activity
+WHERE datname = current_database() AND pid <> pg_backend_pid()`
+)
+
+// IsDeadlockError checks and returns whether the given error is the deadlock error of PostgreSQL.
+func (d *Driver) IsDeadlockError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == errCodeDeadlockDetected
+}
+
+// GetDeadlockDetail queries and returns the activities of current database from pg_stat_activity,
+// using a separate connection from the master node.
+func (d *Driver) GetDeadlockDetail(ctx context.Context) (string, error) {
+	master, err := d.Master()
+	if err != nil {
+		return "", err
+	}
+	rows, err := master.QueryContext(ctx, deadlockDetailSql)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var (
+		pid                                    int64
+		state, waitEventType, waitEvent, query string
+		builder                                strings.Builder
+	)
+	for rows.Next() {
+		if err = rows.Scan(&pid, &state, &waitEventType, &waitEvent, &query); err != nil {
+			return "", err
+		}
+		builder.WriteString(fmt.Sprintf(
+			"pid=%d state=%s wait_event_type=%s wait_event=%s query=%s\n",
+			pid, state, waitEventType, waitEvent, query,
+		))
+	}
+	return builder.String(), rows.Err()
+}
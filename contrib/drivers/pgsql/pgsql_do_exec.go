@@ -41,6 +41,11 @@ func (d *Driver) DoExec(ctx context.Context, link gdb.Link, sql string, args ...
 		}
 	}
 
+	// Check if it is an insert ignore operation.
+	if ctx.Value(internalInsertIgnoreInCtx) != nil && strings.Contains(sql, "INSERT INTO") {
+		sql += " ON CONFLICT DO NOTHING"
+	}
+
 	// Check if it is an insert operation with primary key.
 	if value := ctx.Value(internalPrimaryKeyInCtx); value != nil {
 		var ok bool
@@ -52,8 +57,17 @@ func (d *Driver) DoExec(ctx context.Context, link gdb.Link, sql string, args ...
 		isUseCoreDoExec = true
 	}
 
-	// check if it is an insert operation.
-	if !isUseCoreDoExec && pkField.Name != "" && strings.Contains(sql, "INSERT INTO") {
+	// check if it is an insert or update operation.
+	var (
+		isInsert  = strings.Contains(sql, "INSERT INTO")
+		isUpdate  = strings.HasPrefix(sql, "UPDATE ")
+		returning = d.GetReturningFromCtx(ctx)
+	)
+	if (isInsert || isUpdate) && len(returning) > 0 {
+		// The returning columns specified by the caller, eg: TX.InsertAndReturning, TX.Increment.
+		primaryKey = pkField.Name
+		sql += " RETURNING " + d.formatReturning(returning)
+	} else if !isUseCoreDoExec && pkField.Name != "" && isInsert {
 		primaryKey = pkField.Name
 		sql += " RETURNING " + primaryKey
 	} else {
@@ -61,7 +75,8 @@ func (d *Driver) DoExec(ctx context.Context, link gdb.Link, sql string, args ...
 		return d.Core.DoExec(ctx, link, sql, args...)
 	}
 
-	// Only the insert operation with primary key can execute the following code
+	// Only the insert operation with primary key, or the operation with returning columns
+	// can execute the following code
 
 	if d.GetConfig().ExecTimeout > 0 {
 		var cancelFunc context.CancelFunc
@@ -92,6 +107,18 @@ func (d *Driver) DoExec(ctx context.Context, link gdb.Link, sql string, args ...
 	}
 	affected := len(out.Records)
 	if affected > 0 {
+		if len(returning) > 0 {
+			result := Result{
+				affected:  int64(affected),
+				returning: out.Records,
+			}
+			if primaryKey != "" && strings.Contains(pkField.Type, "int") &&
+				out.Records[affected-1][primaryKey] != nil {
+				result.lastInsertId = out.Records[affected-1][primaryKey].Int64()
+			}
+			return result, nil
+		}
+
 		if !strings.Contains(pkField.Type, "int") {
 			return Result{
 				affected:     int64(affected),
@@ -113,3 +140,15 @@ func (d *Driver) DoExec(ctx context.Context, link gdb.Link, sql string, args ...
 
 	return Result{}, nil
 }
+
+// formatReturning formats and returns the columns of `RETURNING` clause.
+func (d *Driver) formatReturning(columns []string) string {
+	var quoted = make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column == "*" {
+			return column
+		}
+		quoted = append(quoted, d.QuoteWord(column))
+	}
+	return strings.Join(quoted, ",")
+}
@@ -25,10 +25,11 @@ func (d *Driver) DoInsert(ctx context.Context, link gdb.Link, table string, list
 		)
 
 	case gdb.InsertOptionIgnore:
-		return nil, gerror.NewCode(
-			gcode.CodeNotSupported,
-			`Insert ignore operation is not supported by pgsql driver`,
-		)
+		// It uses "ON CONFLICT DO NOTHING" statement for insert ignore operation,
+		// which is appended to the inserting sql in DoExec.
+		ctx = context.WithValue(ctx, internalInsertIgnoreInCtx, true)
+		option.InsertOption = gdb.InsertOptionDefault
+		fallthrough
 
 	case gdb.InsertOptionDefault:
 		tableFields, err := d.GetCore().GetDB().TableFields(ctx, table)
@@ -0,0 +1,32 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package pgsql
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	errCodeSerializationFailure = "40001" // serialization_failure
+	errCodeDeadlockDetected     = "40P01" // deadlock_detected
+)
+
+// IsRetryableError checks and returns whether the given error of transaction is retryable,
+// which are the deadlock and serialization failure errors of PostgreSQL.
+func (d *Driver) IsRetryableError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code {
+	case errCodeDeadlockDetected, errCodeSerializationFailure:
+		return true
+	}
+	return false
+}
@@ -6,13 +6,18 @@
 
 package pgsql
 
-import "database/sql"
+import (
+	"database/sql"
+
+	"github.com/gogf/gf/v2/database/gdb"
+)
 
 type Result struct {
 	sql.Result
 	affected          int64
 	lastInsertId      int64
 	lastInsertIdError error
+	returning         gdb.Result // Records returned by the RETURNING clause specified by the caller.
 }
 
 func (pgr Result) RowsAffected() (int64, error) {
@@ -22,3 +27,8 @@ func (pgr Result) RowsAffected() (int64, error) {
 func (pgr Result) LastInsertId() (int64, error) {
 	return pgr.lastInsertId, pgr.lastInsertIdError
 }
+
+// Returning implements gdb.ReturningResult, which returns the records of the RETURNING clause.
+func (pgr Result) Returning() gdb.Result {
+	return pgr.returning
+}
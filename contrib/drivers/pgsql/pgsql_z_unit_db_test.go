@@ -7,10 +7,12 @@
 package pgsql_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/test/gtest"
@@ -76,6 +78,41 @@ func Test_DB_Insert(t *testing.T) {
 	})
 }
 
+func Test_DB_InsertIgnore(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			inserted, err := tx.InsertIgnoreResult(table, g.Map{
+				"id":          TableSize + 1,
+				"passport":    "t_inserted",
+				"password":    "25d55ad283aa400af464c76d713c07ad",
+				"nickname":    "T_INSERTED",
+				"create_time": gtime.Now().String(),
+			})
+			t.AssertNil(err)
+			t.Assert(inserted, true)
+
+			inserted, err = tx.InsertIgnoreResult(table, g.Map{
+				"id":          1,
+				"passport":    "t_duplicated",
+				"password":    "25d55ad283aa400af464c76d713c07ad",
+				"nickname":    "T_DUPLICATED",
+				"create_time": gtime.Now().String(),
+			})
+			t.AssertNil(err)
+			t.Assert(inserted, false)
+			return nil
+		})
+		t.AssertNil(err)
+
+		value, err := db.Model(table).Where("id", 1).Value("passport")
+		t.AssertNil(err)
+		t.Assert(value, "user_1")
+	})
+}
+
 func Test_DB_Save(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
 		createTable("t_user")
@@ -8,12 +8,16 @@ package pgsql_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/text/gstr"
 
 	"github.com/gogf/gf/contrib/drivers/pgsql/v2"
 )
@@ -84,6 +88,240 @@ func Test_TxLastInsertId(t *testing.T) {
 	})
 }
 
+func Test_Tx_ValidateConstraints(t *testing.T) {
+	var (
+		parentTable = createTable()
+		childTable  = fmt.Sprintf(`%s_child`, parentTable)
+	)
+	defer dropTable(parentTable)
+	defer dropTable(childTable)
+
+	_, err := db.Exec(ctx, fmt.Sprintf(`
+		CREATE TABLE %s (
+			id bigserial NOT NULL,
+			parent_id bigint NOT NULL,
+			PRIMARY KEY (id),
+			CONSTRAINT %s_fk FOREIGN KEY (parent_id) REFERENCES %s (id) DEFERRABLE INITIALLY DEFERRED
+		);`, childTable, childTable, parentTable,
+	))
+	gtest.AssertNil(err)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Insert(childTable, g.Map{"parent_id": 1})
+			t.AssertNil(err)
+			err = tx.ValidateConstraints()
+			t.AssertNE(err, nil)
+			t.Assert(gstr.Contains(err.Error(), childTable+"_fk"), true)
+			return err
+		})
+		t.AssertNE(err, nil)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			_, err := tx.Insert(childTable, g.Map{"parent_id": 1})
+			t.AssertNil(err)
+			_, err = tx.Insert(parentTable, g.Map{
+				"id":          1,
+				"passport":    "user1",
+				"password":    "pwd",
+				"nickname":    "nickname",
+				"create_time": CreateTime,
+			})
+			t.AssertNil(err)
+			return tx.ValidateConstraints()
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_Tx_ExecResult(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			// PostgreSQL does not support LastInsertId, which is returned as 0.
+			result, err := tx.ExecResult(fmt.Sprintf("UPDATE %s SET nickname='name' WHERE id<=3", table))
+			t.AssertNil(err)
+			t.Assert(result.RowsAffected, 3)
+			t.Assert(result.LastInsertId, 0)
+			t.Assert(errors.Is(result.LastInsertIdErr, gdb.ErrLastInsertIdUnsupported), true)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_Tx_InsertAndReturn(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	type User struct {
+		Id         int
+		Passport   string
+		CreateTime *gtime.Time
+	}
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			// Single record.
+			var user *User
+			err := tx.InsertAndReturn(table, g.Map{
+				"passport":    "user_1",
+				"password":    "pass_1",
+				"nickname":    "name_1",
+				"create_time": gtime.Now().String(),
+			}, []string{"id", "passport", "create_time"}, &user)
+			t.AssertNil(err)
+			t.Assert(user.Id, 1)
+			t.Assert(user.Passport, "user_1")
+			t.AssertNE(user.CreateTime, nil)
+
+			// Multiple records, which are more than the default batch count.
+			var (
+				users []User
+				data  = g.List{}
+			)
+			for i := 2; i <= 12; i++ {
+				data = append(data, g.Map{
+					"passport":    fmt.Sprintf(`user_%d`, i),
+					"password":    fmt.Sprintf(`pass_%d`, i),
+					"nickname":    fmt.Sprintf(`name_%d`, i),
+					"create_time": gtime.Now().String(),
+				})
+			}
+			err = tx.InsertAndReturn(table, data, []string{"id", "passport"}, &users)
+			t.AssertNil(err)
+			t.Assert(len(users), 11)
+			t.Assert(users[0].Id, 2)
+			t.Assert(users[0].Passport, "user_2")
+			t.Assert(users[10].Id, 12)
+			t.Assert(users[10].Passport, "user_12")
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
+func Test_Tx_Increment(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			// The condition uses the incremented column.
+			value, err := tx.Increment(table, "id", 100, "id", 1)
+			t.AssertNil(err)
+			t.Assert(value, 101)
+
+			value, err = tx.Increment(table, "id", 0, "passport", "user_2")
+			t.AssertNil(err)
+			t.Assert(value, 2)
+
+			_, err = tx.Increment(table, "id", 1, "id", 10000)
+			t.AssertNE(err, nil)
+
+			// The returning column does not affect the following statements.
+			result, err := tx.Update(table, g.Map{"nickname": "name_101"}, "id", 101)
+			t.AssertNil(err)
+			affected, err := result.RowsAffected()
+			t.AssertNil(err)
+			t.Assert(affected, 1)
+			return nil
+		})
+		t.AssertNil(err)
+
+		count, err := db.Model(table).Where("id", 101).Count()
+		t.AssertNil(err)
+		t.Assert(count, 1)
+	})
+}
+
+func Test_Tx_InsertIfNotExists(t *testing.T) {
+	table := createInitTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			// Already exists.
+			inserted, err := tx.InsertIfNotExists(table, g.Map{
+				"id":          TableSize + 1,
+				"passport":    "user_1",
+				"password":    "pass_1",
+				"nickname":    "name_1",
+				"create_time": gtime.Now().String(),
+			}, "passport", "user_1")
+			t.AssertNil(err)
+			t.Assert(inserted, false)
+
+			// The values of integer and timestamp columns are inserted.
+			inserted, err = tx.InsertIfNotExists(table, g.Map{
+				"id":          TableSize + 1,
+				"passport":    "user_new",
+				"password":    "pass_new",
+				"nickname":    "name_new",
+				"create_time": gtime.Now().String(),
+			}, "passport", "user_new")
+			t.AssertNil(err)
+			t.Assert(inserted, true)
+			return nil
+		})
+		t.AssertNil(err)
+
+		one, err := db.Model(table).Where("id", TableSize+1).One()
+		t.AssertNil(err)
+		t.Assert(one["passport"], "user_new")
+		t.AssertNE(one["create_time"], nil)
+	})
+}
+
+func Test_Tx_InsertAndReturning(t *testing.T) {
+	table := createTable()
+	defer dropTable(table)
+
+	gtest.C(t, func(t *gtest.T) {
+		err := db.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+			// All columns.
+			one, err := tx.InsertAndReturning(table, g.Map{
+				"passport":    "user_1",
+				"password":    "pass_1",
+				"nickname":    "name_1",
+				"create_time": gtime.Now().String(),
+			})
+			t.AssertNil(err)
+			t.Assert(one["id"].Int(), 1)
+			t.Assert(one["passport"].String(), "user_1")
+			t.AssertNE(one["create_time"], nil)
+
+			// Specified columns.
+			one, err = tx.InsertAndReturning(table, g.Map{
+				"passport":    "user_2",
+				"password":    "pass_2",
+				"nickname":    "name_2",
+				"create_time": gtime.Now().String(),
+			}, "id", "nickname")
+			t.AssertNil(err)
+			t.Assert(len(one), 2)
+			t.Assert(one["id"].Int(), 2)
+			t.Assert(one["nickname"].String(), "name_2")
+
+			// The returning columns do not affect the following statements.
+			result, err := tx.Insert(table, g.Map{
+				"passport":    "user_3",
+				"password":    "pass_3",
+				"nickname":    "name_3",
+				"create_time": gtime.Now().String(),
+			})
+			t.AssertNil(err)
+			lastId, err := result.LastInsertId()
+			t.AssertNil(err)
+			t.Assert(lastId, 3)
+			return nil
+		})
+		t.AssertNil(err)
+	})
+}
+
 func Test_Driver_DoFilter(t *testing.T) {
 	var (
 		ctx    = gctx.New()
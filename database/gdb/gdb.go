@@ -142,27 +142,39 @@ type DB interface {
 	// Transaction.
 	// ===========================================================================
 
-	Begin(ctx context.Context) (TX, error)                                           // See Core.Begin.
-	Transaction(ctx context.Context, f func(ctx context.Context, tx TX) error) error // See Core.Transaction.
+	Begin(ctx context.Context) (TX, error)                                                                    // See Core.Begin.
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (TX, error)                                             // See Core.BeginTx.
+	BeginRead(ctx context.Context) (TX, error)                                                                // See Core.BeginRead.
+	BeginXA(ctx context.Context, xid string) (TX, error)                                                      // See Core.BeginXA.
+	Transaction(ctx context.Context, f func(ctx context.Context, tx TX) error) error                          // See Core.Transaction.
+	TransactionWithRetry(ctx context.Context, maxRetries int, f func(ctx context.Context, tx TX) error) error // See Core.TransactionWithRetry.
+	TransactionOpts(ctx context.Context, opts TxOptions, f func(ctx context.Context, tx TX) error) error      // See Core.TransactionOpts.
 
 	// ===========================================================================
 	// Configuration methods.
 	// ===========================================================================
 
-	GetCache() *gcache.Cache            // See Core.GetCache.
-	SetDebug(debug bool)                // See Core.SetDebug.
-	GetDebug() bool                     // See Core.GetDebug.
-	GetSchema() string                  // See Core.GetSchema.
-	GetPrefix() string                  // See Core.GetPrefix.
-	GetGroup() string                   // See Core.GetGroup.
-	SetDryRun(enabled bool)             // See Core.SetDryRun.
-	GetDryRun() bool                    // See Core.GetDryRun.
-	SetLogger(logger glog.ILogger)      // See Core.SetLogger.
-	GetLogger() glog.ILogger            // See Core.GetLogger.
-	GetConfig() *ConfigNode             // See Core.GetConfig.
-	SetMaxIdleConnCount(n int)          // See Core.SetMaxIdleConnCount.
-	SetMaxOpenConnCount(n int)          // See Core.SetMaxOpenConnCount.
-	SetMaxConnLifeTime(d time.Duration) // See Core.SetMaxConnLifeTime.
+	GetCache() *gcache.Cache                                // See Core.GetCache.
+	SetDebug(debug bool)                                    // See Core.SetDebug.
+	GetDebug() bool                                         // See Core.GetDebug.
+	GetSchema() string                                      // See Core.GetSchema.
+	GetPrefix() string                                      // See Core.GetPrefix.
+	GetGroup() string                                       // See Core.GetGroup.
+	SetDryRun(enabled bool)                                 // See Core.SetDryRun.
+	GetDryRun() bool                                        // See Core.GetDryRun.
+	SetLogger(logger glog.ILogger)                          // See Core.SetLogger.
+	GetLogger() glog.ILogger                                // See Core.GetLogger.
+	SetResultInterceptor(interceptor ResultInterceptorFunc) // See Core.SetResultInterceptor.
+	GetResultInterceptor() ResultInterceptorFunc            // See Core.GetResultInterceptor.
+	RegisterHook(hook SqlHookFunc)                          // See Core.RegisterHook.
+	SetTxMetricsSink(sink TxMetricsSink)                    // See Core.SetTxMetricsSink.
+	GetTxMetricsSink() TxMetricsSink                        // See Core.GetTxMetricsSink.
+	SetRetryableErrorFunc(f RetryableErrorFunc)             // See Core.SetRetryableErrorFunc.
+	GetRetryableErrorFunc() RetryableErrorFunc              // See Core.GetRetryableErrorFunc.
+	GetConfig() *ConfigNode                                 // See Core.GetConfig.
+	SetMaxIdleConnCount(n int)                              // See Core.SetMaxIdleConnCount.
+	SetMaxOpenConnCount(n int)                              // See Core.SetMaxOpenConnCount.
+	SetMaxConnLifeTime(d time.Duration)                     // See Core.SetMaxConnLifeTime.
 
 	// ===========================================================================
 	// Utility methods.
@@ -178,6 +190,9 @@ type DB interface {
 	ConvertValueForLocal(ctx context.Context, fieldType string, fieldValue interface{}) (interface{}, error) // See Core.ConvertValueForLocal
 	CheckLocalTypeForField(ctx context.Context, fieldType string, fieldValue interface{}) (LocalType, error) // See Core.CheckLocalTypeForField
 	FormatUpsert(columns []string, list List, option DoInsertOption) (string, error)                         // See Core.DoFormatUpsert
+	IsRetryableError(err error) bool                                                                         // See Core.IsRetryableError.
+	IsDeadlockError(err error) bool                                                                          // See Core.IsDeadlockError.
+	GetDeadlockDetail(ctx context.Context) (string, error)                                                   // See Core.GetDeadlockDetail.
 }
 
 // TX defines the interfaces for ORM transaction operations.
@@ -196,14 +211,32 @@ type TX interface {
 	Begin() error
 	Commit() error
 	Rollback() error
+	OnCommit(f func())
+	OnRollback(f func())
+	OnCommitted(f func(err error))
+	OnRolledBack(f func(err error))
 	Transaction(ctx context.Context, f func(ctx context.Context, tx TX) error) (err error)
+	TransactionNamed(ctx context.Context, name string, f func(ctx context.Context, tx TX) error) (err error)
+	ValidateConstraints() error
+	Assert(f func(tx TX) error) error
+	IsXA() bool
+	EndXA() error
+	PrepareXA() error
+	CommitXA(onePhase bool) error
+	RollbackXA() error
+	NestedLevel() int
+	IsNested() bool
+	SetMaxDepth(n int)
 
 	// ===========================================================================
 	// Core method.
 	// ===========================================================================
 
 	Query(sql string, args ...interface{}) (result Result, err error)
+	QueryWithContext(ctx context.Context, sql string, args ...interface{}) (result Result, err error)
 	Exec(sql string, args ...interface{}) (sql.Result, error)
+	ExecWithContext(ctx context.Context, sql string, args ...interface{}) (sql.Result, error)
+	ExecResult(sql string, args ...interface{}) (*ExecResult, error)
 	Prepare(sql string) (*Stmt, error)
 
 	// ===========================================================================
@@ -224,19 +257,38 @@ type TX interface {
 
 	Insert(table string, data interface{}, batch ...int) (sql.Result, error)
 	InsertIgnore(table string, data interface{}, batch ...int) (sql.Result, error)
+	InsertIgnoreResult(table string, data interface{}) (inserted bool, err error)
+	InsertIfNotExists(table string, data interface{}, existsCondition interface{}, args ...interface{}) (inserted bool, err error)
 	InsertAndGetId(table string, data interface{}, batch ...int) (int64, error)
+	InsertAndReturning(table string, data interface{}, returning ...string) (Record, error)
+	InsertAndReturn(table string, data interface{}, returning []string, pointer interface{}) error
 	Replace(table string, data interface{}, batch ...int) (sql.Result, error)
 	Save(table string, data interface{}, batch ...int) (sql.Result, error)
 	Update(table string, data interface{}, condition interface{}, args ...interface{}) (sql.Result, error)
+	Increment(table, column string, by int64, condition interface{}, args ...interface{}) (int64, error)
 	Delete(table string, condition interface{}, args ...interface{}) (sql.Result, error)
+	DeleteForce(table string, condition interface{}, args ...interface{}) (sql.Result, error)
 
 	// ===========================================================================
 	// Utility methods.
 	// ===========================================================================
 
 	GetCtx() context.Context
+	Id() string
 	GetDB() DB
 	GetSqlTX() *sql.Tx
+	UnderlyingTx() (*sql.Tx, error)
+	GetOptions() *sql.TxOptions
+	IsReadOnly() bool
+	Debug(enabled bool) TX
+	SetStatementTimeout(d time.Duration)
+	SetMetricsLabel(label string) TX
+	GetMetricsLabel() string
+	StartTime() time.Time
+	Elapsed() time.Duration
+	StatementCount() int
+	Checkpoint(name string)
+	AffectedSince(checkpoint string) (int64, error)
 	IsClosed() bool
 
 	// ===========================================================================
@@ -245,6 +297,11 @@ type TX interface {
 
 	SavePoint(point string) error
 	RollbackTo(point string) error
+	RollbackToSavePointKeeping(point string) error
+	SavePoints() []string
+	SavePointFunc(point string, f func() error) (err error)
+	SetSavePointPrefix(prefix string) error
+	GetSavePointPrefix() string
 }
 
 // StatsItem defines the stats information for a configuration node.
@@ -258,17 +315,58 @@ type StatsItem interface {
 
 // Core is the base struct for database management.
 type Core struct {
-	db            DB              // DB interface object.
-	ctx           context.Context // Context for chaining operation only. Do not set a default value in Core initialization.
-	group         string          // Configuration group name.
-	schema        string          // Custom schema for this object.
-	debug         *gtype.Bool     // Enable debug mode for the database, which can be changed in runtime.
-	cache         *gcache.Cache   // Cache manager, SQL result cache only.
-	links         *gmap.Map       // links caches all created links by node.
-	logger        glog.ILogger    // Logger for logging functionality.
-	config        *ConfigNode     // Current config node.
-	dynamicConfig dynamicConfig   // Dynamic configurations, which can be changed in runtime.
-	innerMemCache *gcache.Cache
+	db                 DB              // DB interface object.
+	ctx                context.Context // Context for chaining operation only. Do not set a default value in Core initialization.
+	group              string          // Configuration group name.
+	schema             string          // Custom schema for this object.
+	debug              *gtype.Bool     // Enable debug mode for the database, which can be changed in runtime.
+	cache              *gcache.Cache   // Cache manager, SQL result cache only.
+	links              *gmap.Map       // links caches all created links by node.
+	logger             glog.ILogger    // Logger for logging functionality.
+	config             *ConfigNode     // Current config node.
+	dynamicConfig      dynamicConfig   // Dynamic configurations, which can be changed in runtime.
+	innerMemCache      *gcache.Cache
+	interceptor        ResultInterceptorFunc // Interceptor for query results before they're returned to caller.
+	sqlHooks           []SqlHookFunc         // Hooks for SQL statements before they're committed to underlying driver.
+	txMetricsSink      TxMetricsSink         // Sink receiving metrics of finished transactions.
+	retryableErrorFunc RetryableErrorFunc    // Custom function checking retryable errors for TransactionWithRetry.
+}
+
+// ResultInterceptorFunc is the function for intercepting and modifying the query result
+// before it's returned to the caller. The parameter `columns` is the column names
+// of the query result in sequence.
+type ResultInterceptorFunc func(ctx context.Context, columns []string, result Result) Result
+
+// SqlHookFunc is the function hooking the SQL statement right before it's committed to underlying driver,
+// which can modify the sql and its arguments of `in.Sql`, or short-circuit the execution by returning
+// non-nil error, which is returned to the caller.
+type SqlHookFunc func(ctx context.Context, in *SqlHookInput) error
+
+// SqlHookInput is the input parameters for SqlHookFunc.
+type SqlHookInput struct {
+	Sql           *Sql   // Sql is the statement to be committed, in which only Sql and Args take effect if modified.
+	Link          Link   // Link is the database connection that the statement is committed through, which is nil for BEGIN.
+	TransactionId string // TransactionId is the id of the transaction that the statement is committed in, which is empty if not in transaction.
+}
+
+// RetryableErrorFunc is the function checking whether the error of transaction is retryable,
+// which is used by TransactionWithRetry.
+type RetryableErrorFunc func(err error) bool
+
+// TxMetricsSink is the interface for receiving metrics of transactions,
+// which is called each time a transaction is committed or rolled back.
+type TxMetricsSink interface {
+	RecordTx(ctx context.Context, metrics TxMetrics)
+}
+
+// TxMetrics is the metrics of a finished transaction.
+type TxMetrics struct {
+	Group         string        // Group is the configuration group name of the database.
+	Label         string        // Label is the business operation label, see TX.SetMetricsLabel.
+	TransactionId string        // TransactionId is the unique id of the transaction.
+	Duration      time.Duration // Duration is the time cost from the transaction beginning to its end.
+	Committed     bool          // Committed marks the transaction is committed, or else it is rolled back or fails committing.
+	Error         error         // Error is the error of the commit or rollback, which is nil if succeeds.
 }
 
 type dynamicConfig struct {
@@ -281,6 +379,7 @@ type dynamicConfig struct {
 type DoCommitInput struct {
 	Db            *sql.DB
 	Tx            *sql.Tx
+	TxOptions     *sql.TxOptions // TxOptions is the transaction options for Begin, which is optional.
 	Stmt          *sql.Stmt
 	Link          Link
 	Sql           string
@@ -316,17 +415,18 @@ type Link interface {
 
 // Sql is the sql recording struct.
 type Sql struct {
-	Sql           string        // SQL string(may contain reserved char '?').
-	Type          SqlType       // SQL operation type.
-	Args          []interface{} // Arguments for this sql.
-	Format        string        // Formatted sql which contains arguments in the sql.
-	Error         error         // Execution result.
-	Start         int64         // Start execution timestamp in milliseconds.
-	End           int64         // End execution timestamp in milliseconds.
-	Group         string        // Group is the group name of the configuration that the sql is executed from.
-	Schema        string        // Schema is the schema name of the configuration that the sql is executed from.
-	IsTransaction bool          // IsTransaction marks whether this sql is executed in transaction.
-	RowsAffected  int64         // RowsAffected marks retrieved or affected number with current sql statement.
+	Sql            string        // SQL string(may contain reserved char '?').
+	Type           SqlType       // SQL operation type.
+	Args           []interface{} // Arguments for this sql.
+	Format         string        // Formatted sql which contains arguments in the sql.
+	Error          error         // Execution result.
+	Start          int64         // Start execution timestamp in milliseconds.
+	End            int64         // End execution timestamp in milliseconds.
+	Group          string        // Group is the group name of the configuration that the sql is executed from.
+	Schema         string        // Schema is the schema name of the configuration that the sql is executed from.
+	IsTransaction  bool          // IsTransaction marks whether this sql is executed in transaction.
+	RowsAffected   int64         // RowsAffected marks retrieved or affected number with current sql statement.
+	StatementCount int           // StatementCount is the count of statements executed in transaction, which is only set for COMMIT.
 }
 
 // DoInsertOption is the input struct for function DoInsert.
@@ -433,6 +533,11 @@ const (
 	SqlTypeBegin               SqlType = "DB.Begin"
 	SqlTypeTXCommit            SqlType = "TX.Commit"
 	SqlTypeTXRollback          SqlType = "TX.Rollback"
+	SqlTypeBeginXA             SqlType = "DB.BeginXA"
+	SqlTypeTXXAEnd             SqlType = "TX.XAEnd"
+	SqlTypeTXXAPrepare         SqlType = "TX.XAPrepare"
+	SqlTypeTXXACommit          SqlType = "TX.XACommit"
+	SqlTypeTXXARollback        SqlType = "TX.XARollback"
 	SqlTypeExecContext         SqlType = "DB.ExecContext"
 	SqlTypeQueryContext        SqlType = "DB.QueryContext"
 	SqlTypePrepareContext      SqlType = "DB.PrepareContext"
@@ -498,6 +498,7 @@ func (c *Core) DoInsert(ctx context.Context, link Link, table string, list List,
 	var (
 		listLength   = len(list)
 		valueHolders = make([]string, 0)
+		batchIndex   = 0
 	)
 	for i := 0; i < listLength; i++ {
 		values = values[:0]
@@ -525,6 +526,16 @@ func (c *Core) DoInsert(ctx context.Context, link Link, table string, list List,
 				onDuplicateStr,
 			), params...)
 			if err != nil {
+				if listLength > option.BatchCount && option.BatchCount > 0 {
+					err = &batchError{
+						err: err,
+						failure: BatchFailure{
+							BatchIndex: batchIndex,
+							Offset:     i + 1 - len(valueHolders),
+							Affected:   batchResult.Affected,
+						},
+					}
+				}
 				return stdSqlResult, err
 			}
 			if affectedRows, err = stdSqlResult.RowsAffected(); err != nil {
@@ -536,6 +547,7 @@ func (c *Core) DoInsert(ctx context.Context, link Link, table string, list List,
 			}
 			params = params[:0]
 			valueHolders = valueHolders[:0]
+			batchIndex++
 		}
 	}
 	return batchResult, nil
@@ -704,22 +716,37 @@ func (c Core) MarshalJSON() ([]byte, error) {
 // writeSqlToLogger outputs the Sql object to logger.
 // It is enabled only if configuration "debug" is true.
 func (c *Core) writeSqlToLogger(ctx context.Context, sql *Sql) {
+	s := c.formatSqlForLogger(ctx, sql)
+	if sql.Error != nil {
+		s += "\nError: " + sql.Error.Error()
+		c.logger.Error(ctx, s)
+	} else {
+		c.logger.Debug(ctx, s)
+	}
+}
+
+// writeSlowSqlToLogger outputs the Sql object whose duration exceeds the configured SlowLogThreshold
+// to logger at WARN level with marker "slow=true", regardless of configuration "debug".
+func (c *Core) writeSlowSqlToLogger(ctx context.Context, sql *Sql) {
+	c.logger.Warningf(ctx, `slow=true elapsed=%dms %s`, sql.End-sql.Start, c.formatSqlForLogger(ctx, sql))
+}
+
+// formatSqlForLogger formats and returns the logging content of the Sql object.
+func (c *Core) formatSqlForLogger(ctx context.Context, sql *Sql) string {
 	var transactionIdStr string
 	if sql.IsTransaction {
-		if v := ctx.Value(transactionIdForLoggerCtx); v != nil {
-			transactionIdStr = fmt.Sprintf(`[txid:%d] `, v.(uint64))
+		if v := ctx.Value(GetTransactionIdContextKey()); v != nil {
+			transactionIdStr = fmt.Sprintf(`[txid:%v] `, v)
 		}
 	}
 	s := fmt.Sprintf(
 		"[%3d ms] [%s] [%s] [rows:%-3d] %s%s",
 		sql.End-sql.Start, sql.Group, sql.Schema, sql.RowsAffected, transactionIdStr, sql.Format,
 	)
-	if sql.Error != nil {
-		s += "\nError: " + sql.Error.Error()
-		c.logger.Error(ctx, s)
-	} else {
-		c.logger.Debug(ctx, s)
+	if sql.Type == SqlTypeTXCommit || sql.Type == SqlTypeTXXACommit {
+		s += fmt.Sprintf(` [statements:%d]`, sql.StatementCount)
 	}
+	return s
 }
 
 // HasTable determine whether the table name exists in the database.
@@ -0,0 +1,53 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BatchFailure is the diagnostic detail of the failed batch when the inserting records
+// are split into multiple batches, eg: using Model.Batch.
+type BatchFailure struct {
+	BatchIndex int   // BatchIndex is the index of the failed batch, which starts from 0.
+	Offset     int   // Offset is the index of the first record of the failed batch in the inserting records.
+	Affected   int64 // Affected is the affected rows count of the succeeded batches before the failed batch.
+}
+
+// batchError is the error wrapping the error of the failed batch in batch inserting.
+type batchError struct {
+	err     error        // err is the original error of the failed batch.
+	failure BatchFailure // failure is the diagnostic detail of the failed batch.
+}
+
+// Error implements the interface of error.
+func (e *batchError) Error() string {
+	return fmt.Sprintf(
+		`batch %d failed at offset %d with %d rows affected before: %s`,
+		e.failure.BatchIndex, e.failure.Offset, e.failure.Affected, e.err.Error(),
+	)
+}
+
+// Unwrap implements the interface of errors.Unwrap.
+func (e *batchError) Unwrap() error {
+	return e.err
+}
+
+// GetBatchFailure retrieves and returns the diagnostic detail of the failed batch from `err`,
+// which tells which batch and offset failed and how many rows were affected before it.
+// Note that the succeeded batches are also rolled back if the inserting runs in transaction
+// that is rolled back.
+// It returns nil if `err` is not produced by a failed batch of batch inserting.
+func GetBatchFailure(err error) *BatchFailure {
+	var batchErr *batchError
+	if errors.As(err, &batchErr) {
+		failure := batchErr.failure
+		return &failure
+	}
+	return nil
+}
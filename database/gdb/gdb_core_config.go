@@ -49,10 +49,13 @@ type ConfigNode struct {
 	ExecTimeout          time.Duration `json:"execTimeout"`          // (Optional) Max exec time for dml.
 	TranTimeout          time.Duration `json:"tranTimeout"`          // (Optional) Max exec time for a transaction.
 	PrepareTimeout       time.Duration `json:"prepareTimeout"`       // (Optional) Max exec time for prepare operation.
+	TxStmtCacheDisabled  bool          `json:"txStmtCacheDisabled"`  // (Optional) Disable the prepared statement caching of transaction.
+	SlowLogThreshold     time.Duration `json:"slowLogThreshold"`     // (Optional) Statements taking longer than it are logged at WARN level, 0 means disabled.
 	CreatedAt            string        `json:"createdAt"`            // (Optional) The field name of table for automatic-filled created datetime.
 	UpdatedAt            string        `json:"updatedAt"`            // (Optional) The field name of table for automatic-filled updated datetime.
 	DeletedAt            string        `json:"deletedAt"`            // (Optional) The field name of table for automatic-filled updated datetime.
 	TimeMaintainDisabled bool          `json:"timeMaintainDisabled"` // (Optional) Disable the automatic time maintaining feature.
+	BatchSize            int           `json:"batchSize"`            // (Optional) Default batch size for batch Insert/Replace/Save operations of transaction if no batch specified, no batching if not configured.
 }
 
 const (
@@ -171,6 +174,60 @@ func (c *Core) GetLogger() glog.ILogger {
 	return c.logger
 }
 
+// SetResultInterceptor sets the interceptor function for query results of current database,
+// which can be used to redact or transform the values before the caller sees them,
+// eg: soft-masking sensitive columns.
+//
+// The interceptor is called for every query result, both in normal and transaction query procedures.
+// Note that it runs after the rows are scanned from the underlying driver but before any
+// struct conversion, so the modification applies consistently regardless of the scan target.
+func (c *Core) SetResultInterceptor(interceptor ResultInterceptorFunc) {
+	c.interceptor = interceptor
+}
+
+// GetResultInterceptor returns the interceptor function for query results.
+// It returns nil if no interceptor previously set.
+func (c *Core) GetResultInterceptor() ResultInterceptorFunc {
+	return c.interceptor
+}
+
+// RegisterHook registers the hook function for SQL statements of current database, which is called
+// in registration order right before each statement is committed to underlying driver, eg: injecting
+// tenant filters or recording metrics. The hooks are also called for the BEGIN/COMMIT/ROLLBACK of
+// transactions, so that the transaction boundaries can be audited along with the transaction id.
+//
+// Note that it is not concurrent safe, which should be called in the initialization of the database.
+func (c *Core) RegisterHook(hook SqlHookFunc) {
+	c.sqlHooks = append(c.sqlHooks, hook)
+}
+
+// SetTxMetricsSink sets the sink receiving the duration and outcome metrics of transactions,
+// which is called after each transaction of current database is committed or rolled back.
+// The metrics contain the label set by TX.SetMetricsLabel, so that the transaction performance
+// can be broken down by business operation.
+func (c *Core) SetTxMetricsSink(sink TxMetricsSink) {
+	c.txMetricsSink = sink
+}
+
+// GetTxMetricsSink returns the sink of transaction metrics.
+// It returns nil if no sink previously set.
+func (c *Core) GetTxMetricsSink() TxMetricsSink {
+	return c.txMetricsSink
+}
+
+// SetRetryableErrorFunc sets the custom function checking whether the error of transaction is
+// retryable for TransactionWithRetry, which overrides the DB.IsRetryableError of the driver.
+// It is useful for retrying on application specific errors or driver errors not covered by default.
+func (c *Core) SetRetryableErrorFunc(f RetryableErrorFunc) {
+	c.retryableErrorFunc = f
+}
+
+// GetRetryableErrorFunc returns the custom function checking retryable errors.
+// It returns nil if no function previously set.
+func (c *Core) GetRetryableErrorFunc() RetryableErrorFunc {
+	return c.retryableErrorFunc
+}
+
 // SetMaxIdleConnCount sets the maximum number of connections in the idle
 // connection pool.
 //
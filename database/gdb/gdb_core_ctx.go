@@ -40,6 +40,11 @@ const (
 	// but returns errors when execute `RowsAffected`. It here ignores the calling of `RowsAffected`
 	// to avoid triggering errors, rather than ignoring errors after they are triggered.
 	ignoreResultKeyInCtx gctx.StrKey = "IgnoreResult"
+
+	// `returningKeyInCtx` is a mark for some db drivers that support `RETURNING` clause,
+	// for example: `pgsql`. It carries the columns that should be returned by the inserting or updating statement,
+	// and the driver returns them using ReturningResult.
+	returningKeyInCtx gctx.StrKey = "Returning"
 )
 
 func (c *Core) injectInternalCtxData(ctx context.Context) context.Context {
@@ -96,3 +101,17 @@ func (c *Core) InjectIgnoreResult(ctx context.Context) context.Context {
 func (c *Core) GetIgnoreResultFromCtx(ctx context.Context) bool {
 	return ctx.Value(ignoreResultKeyInCtx) != nil
 }
+
+// InjectReturning injects the columns that should be returned by the inserting or updating statement into `ctx`.
+func (c *Core) InjectReturning(ctx context.Context, columns []string) context.Context {
+	return context.WithValue(ctx, returningKeyInCtx, columns)
+}
+
+// GetReturningFromCtx retrieves and returns the columns that should be returned by the inserting or updating statement.
+// It returns nil if no returning columns injected.
+func (c *Core) GetReturningFromCtx(ctx context.Context) []string {
+	if v := ctx.Value(returningKeyInCtx); v != nil {
+		return v.([]string)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gogf/gf/v2/internal/intlog"
+)
+
+// deadlockError is the error wrapping the deadlock error of transaction statement
+// with the diagnostic detail captured from the database server.
+type deadlockError struct {
+	err    error  // err is the original deadlock error from driver.
+	detail string // detail is the diagnostic detail of the deadlock, eg: output of `SHOW ENGINE INNODB STATUS`.
+}
+
+// Error implements the interface of error.
+func (e *deadlockError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap implements the interface of errors.Unwrap.
+func (e *deadlockError) Unwrap() error {
+	return e.err
+}
+
+// DeadlockDetail retrieves and returns the diagnostic detail captured when a deadlock error occurs
+// in transaction, which aids post-mortem debugging of contention.
+// It returns empty string if `err` is not a deadlock error or no detail was captured.
+func DeadlockDetail(err error) string {
+	var deadlockErr *deadlockError
+	if errors.As(err, &deadlockErr) {
+		return deadlockErr.detail
+	}
+	return ""
+}
+
+// IsDeadlockError checks and returns whether the given error is a deadlock error.
+// It returns false in default, and the driver should implement this function
+// to enable the deadlock detail capturing in transaction.
+func (c *Core) IsDeadlockError(err error) bool {
+	return false
+}
+
+// GetDeadlockDetail queries and returns the diagnostic detail of the latest deadlock from database server.
+// It returns empty string in default, and the driver should implement this function using the
+// dialect-appropriate diagnostic view on a separate connection rather than the deadlocked transaction.
+func (c *Core) GetDeadlockDetail(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+// wrapDeadlockError captures the deadlock detail using DB.GetDeadlockDetail and wraps it into `err`
+// if `err` is a deadlock error, or else it returns `err` directly.
+func (c *Core) wrapDeadlockError(ctx context.Context, err error) error {
+	if err == nil || !c.db.IsDeadlockError(err) {
+		return err
+	}
+	detail, detailErr := c.db.GetDeadlockDetail(ctx)
+	if detailErr != nil {
+		intlog.Errorf(ctx, `retrieve deadlock detail failed: %+v`, detailErr)
+	}
+	return &deadlockError{
+		err:    err,
+		detail: detail,
+	}
+}
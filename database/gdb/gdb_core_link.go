@@ -7,6 +7,7 @@
 package gdb
 
 import (
+	"context"
 	"database/sql"
 )
 
@@ -18,7 +19,24 @@ type dbLink struct {
 
 // txLink is used to implement interface Link for TX.
 type txLink struct {
-	*sql.Tx
+	rawTxLink
+	core *TXCore // core is the transaction object that creates this link, which can be nil.
+}
+
+// rawTxLink is the underlying link of transaction, which is *sql.Tx for local transaction,
+// or *sql.Conn for XA transaction.
+type rawTxLink interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// newTxLink creates and returns the Link object for given transaction.
+func newTxLink(tx TX) *txLink {
+	if core, ok := tx.(*TXCore); ok {
+		return &txLink{rawTxLink: core.rawLink(), core: core}
+	}
+	return &txLink{rawTxLink: tx.GetSqlTX()}
 }
 
 // IsTransaction returns if current Link is a transaction.
@@ -75,9 +75,9 @@ func (c *Core) traceSpanEnd(ctx context.Context, span trace.Span, sql *Sql) {
 		attribute.String(traceEventDbExecutionRows, fmt.Sprintf(`%d`, sql.RowsAffected)),
 	}
 	if sql.IsTransaction {
-		if v := ctx.Value(transactionIdForLoggerCtx); v != nil {
+		if v := ctx.Value(GetTransactionIdContextKey()); v != nil {
 			events = append(events, attribute.String(
-				traceEventDbExecutionTxID, fmt.Sprintf(`%d`, v.(uint64)),
+				traceEventDbExecutionTxID, fmt.Sprintf(`%v`, v),
 			))
 		}
 	}
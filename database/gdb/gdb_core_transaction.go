@@ -9,77 +9,302 @@ package gdb
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
 	"reflect"
+	"runtime"
+	"sort"
+	"time"
 
+	"github.com/gogf/gf/v2/container/gmap"
 	"github.com/gogf/gf/v2/container/gtype"
+	"github.com/gogf/gf/v2/debug/gdebug"
 	"github.com/gogf/gf/v2/errors/gcode"
 	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/internal/intlog"
 	"github.com/gogf/gf/v2/internal/reflection"
 	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
 	"github.com/gogf/gf/v2/util/gconv"
 )
 
 // TXCore is the struct for transaction management.
 type TXCore struct {
-	db               DB              // db is the current gdb database manager.
-	tx               *sql.Tx         // tx is the raw and underlying transaction manager.
-	ctx              context.Context // ctx is the context for this transaction only.
-	master           *sql.DB         // master is the raw and underlying database manager.
-	transactionId    string          // transactionId is a unique id generated by this object for this transaction.
-	transactionCount int             // transactionCount marks the times that Begins.
-	isClosed         bool            // isClosed marks this transaction has already been committed or rolled back.
+	db                DB                // db is the current gdb database manager.
+	tx                *sql.Tx           // tx is the raw and underlying transaction manager, which is nil for XA transaction.
+	conn              *sql.Conn         // conn is the dedicated connection for XA transaction, which is nil for local transaction.
+	xid               string            // xid is the id of XA transaction, which is empty for local transaction.
+	xaEnded           bool              // xaEnded marks the XA transaction has been ended by `XA END`.
+	ctx               context.Context   // ctx is the context for this transaction only.
+	ctxDb             DB                // ctxDb is the db bound with ctx, which is reused by TX.Model until ctx changes.
+	ctxDbCtx          context.Context   // ctxDbCtx is the context that ctxDb is bound with.
+	master            *sql.DB           // master is the raw and underlying database manager.
+	transactionId     string            // transactionId is a unique id generated by this object for this transaction.
+	transactionCount  int               // transactionCount marks the times that Begins.
+	isClosed          bool              // isClosed marks this transaction has already been committed or rolled back.
+	options           *sql.TxOptions    // options is the transaction options that this transaction begins with, which can be nil.
+	startTime         time.Time         // startTime is the time when this transaction begins.
+	metricsLabel      string            // metricsLabel is the business operation label for transaction metrics.
+	onCommitFuncs     []func()          // onCommitFuncs are the callbacks executed after the transaction is committed.
+	onRollbackFuncs   []func()          // onRollbackFuncs are the callbacks executed after the transaction is rolled back.
+	onCommittedFuncs  []func(err error) // onCommittedFuncs are the hooks executed after every Commit completes.
+	onRolledBackFuncs []func(err error) // onRolledBackFuncs are the hooks executed after every Rollback completes.
+	statementCount    gtype.Int         // statementCount is the count of statements executed through this transaction.
+	savePointPrefix   string            // savePointPrefix is the custom prefix of save point names for nested transaction.
+	identityMap       *gmap.StrAnyMap   // identityMap caches the records loaded by Model.Find with identity map enabled.
+	affectedRows      gtype.Int64       // affectedRows is the sum of rows affected by write statements executed through this transaction.
+	checkpoints       *gmap.StrAnyMap   // checkpoints maps checkpoint names to the affected rows sum when they are created.
+	managedLevels     []int             // managedLevels are the nested levels of the procedures managed by Transaction function.
+	statementTimeout  time.Duration     // statementTimeout is the max execution time for each statement of this transaction.
+	statementTimedOut bool              // statementTimedOut marks any statement of this transaction has been cancelled by statementTimeout.
+	debug             *gtype.Bool       // debug is the debug setting of this transaction, which follows the one of db if nil.
+	beginStack        string            // beginStack is the stack where this transaction begins, which is captured for leak detection.
+	maxDepth          int               // maxDepth is the max nesting depth of nested transactions, which is unlimited if it is 0.
+	savePoints        []string          // savePoints are the names of active save points in creation order, which is for introspection only.
+	nestedPointNames  map[int]string    // nestedPointNames maps the nesting levels to the save point names given by TransactionNamed.
+	stmts             *gmap.StrAnyMap   // stmts caches the prepared statements of this transaction by their sql.
 }
 
 const (
 	transactionPointerPrefix    = "transaction"
+	savePointNamePattern        = `^[a-zA-Z_][a-zA-Z0-9_]*$`
 	contextTransactionKeyPrefix = "TransactionObjectForGroup_"
-	transactionIdForLoggerCtx   = "TransactionId"
+	contextTransactionGroupsKey = "TransactionGroups"
+	defaultTransactionIdCtxKey  = "TransactionId"
+	stackFilterKeyForTx         = "/database/gdb/gdb"
 )
 
-var transactionIdGenerator = gtype.NewUint64()
+var (
+	// transactionIdCtxKey is the context key storing the transaction id for logging and tracing,
+	// which can be customized using SetTransactionIdContextKey.
+	transactionIdCtxKey = gtype.NewString(defaultTransactionIdCtxKey)
+
+	// transactionIdGenerator is the sequence of transaction id, which is seeded from the process
+	// starting timestamp in nanoseconds, so that it does not restart from 1 after process restarts.
+	transactionIdGenerator = gtype.NewUint64(uint64(time.Now().UnixNano()))
+
+	// transactionIdSuffix is the process unique suffix of transaction id,
+	// which is composed of the checksum of host name and the process id.
+	transactionIdSuffix = newTransactionIdSuffix()
+)
+
+// newTransactionIdSuffix creates and returns the process unique suffix of transaction id.
+func newTransactionIdSuffix() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf(`%08x%06x`, crc32.ChecksumIEEE([]byte(hostname)), os.Getpid()&0xffffff)
+}
+
+// newTransactionId creates and returns an id for new transaction, which is unique across processes
+// and hosts, and monotonically increasing in one process, eg: "17f0c2b9e5a3d4c1a1b2c3d4000a2f".
+// It is composed of the increased sequence in 16 hex chars and the process unique suffix,
+// which contains only letters and digits so that it can be used in the save point name.
+func newTransactionId() string {
+	return fmt.Sprintf(`%016x%s`, transactionIdGenerator.Add(1), transactionIdSuffix)
+}
+
+// SetTransactionIdContextKey sets the context key storing the transaction id for logging and tracing,
+// which is "TransactionId" in default. It is useful if the default key clashes with the one used
+// by the application, or to follow the field naming convention of the external logging system.
+// The default key is restored if `key` is empty.
+func SetTransactionIdContextKey(key string) {
+	if key == "" {
+		key = defaultTransactionIdCtxKey
+	}
+	transactionIdCtxKey.Set(key)
+}
+
+// GetTransactionIdContextKey returns the context key storing the transaction id for logging and tracing.
+func GetTransactionIdContextKey() string {
+	return transactionIdCtxKey.Val()
+}
+
+const (
+	transactionRetryBaseInterval = 50 * time.Millisecond // The waiting interval before the first retry.
+	transactionRetryMaxInterval  = 2 * time.Second       // The maximum waiting interval between retries.
+)
 
 // Begin starts and returns the transaction object.
 // You should call Commit or Rollback functions of the transaction object
 // if you no longer use the transaction. Commit or Rollback functions will also
 // close the transaction automatically.
+//
+// In debug mode, a warning with the stack where the transaction begins is logged if the
+// transaction is garbage collected without being committed or rolled back.
 func (c *Core) Begin(ctx context.Context) (tx TX, err error) {
-	return c.doBeginCtx(ctx)
+	return c.doBeginCtx(ctx, nil)
+}
+
+// BeginTx starts and returns the transaction object with given transaction options `opts`,
+// which specifies the isolation level and read-only mode of the transaction,
+// for example, a SERIALIZABLE read-only transaction for reporting queries.
+// It begins the transaction with the default options of the driver if `opts` is nil,
+// which is the same as Begin, and TX.GetOptions of the transaction returns nil.
+//
+// The options are kept by the transaction object and can be retrieved using TX.GetOptions.
+// Note that nested transactions use save points, which ignore the options.
+//
+// Note that the given context `ctx` is used until the transaction is committed or rolled back.
+// If the context is canceled, the underlying driver rolls back the transaction.
+func (c *Core) BeginTx(ctx context.Context, opts *sql.TxOptions) (tx TX, err error) {
+	return c.doBeginCtx(ctx, opts)
 }
 
-func (c *Core) doBeginCtx(ctx context.Context) (TX, error) {
+// BeginRead starts and returns a read-only transaction on the slave node, which is used for
+// long analytical transactions against a replica without touching the master node.
+// It begins on the master node if master-slave is not configured.
+//
+// The returned transaction rejects all writing operations, like Exec/Insert/Update/Delete,
+// with error `transaction is read-only`.
+func (c *Core) BeginRead(ctx context.Context) (tx TX, err error) {
+	slave, err := c.db.Slave()
+	if err != nil {
+		return nil, err
+	}
+	return c.doBeginCtxWithDb(ctx, slave, &sql.TxOptions{ReadOnly: true})
+}
+
+func (c *Core) doBeginCtx(ctx context.Context, opts *sql.TxOptions) (TX, error) {
 	master, err := c.db.Master()
 	if err != nil {
 		return nil, err
 	}
-	var out DoCommitOutput
-	out, err = c.db.DoCommit(ctx, DoCommitInput{
-		Db:            master,
-		Sql:           "BEGIN",
+	return c.doBeginCtxWithDb(ctx, master, opts)
+}
+
+func (c *Core) doBeginCtxWithDb(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (TX, error) {
+	var input = DoCommitInput{
+		Db:            db,
+		Sql:           formatBeginSql(opts),
 		Type:          SqlTypeBegin,
+		TxOptions:     opts,
 		IsTransaction: true,
-	})
+	}
+	out, err := c.db.DoCommit(ctx, input)
+	// The pool might hand out stale connections after the database server restarts,
+	// in which case it retries once with the connection re-acquired from the pool.
+	if err != nil && errors.Is(err, driver.ErrBadConn) {
+		c.db.GetLogger().Warningf(ctx, `begin transaction failed with bad connection, retrying once: %v`, err)
+		out, err = c.db.DoCommit(ctx, input)
+	}
+	if err == nil && c.db.GetDebug() {
+		if txCore, ok := out.Tx.(*TXCore); ok {
+			txCore.trackLeak()
+		}
+	}
 	return out.Tx, err
 }
 
+// formatBeginSql formats and returns the sql string of transaction beginning for logging,
+// which contains the isolation level and read-only mode of the transaction if given.
+func formatBeginSql(opts *sql.TxOptions) string {
+	var beginSql = "BEGIN"
+	if opts == nil {
+		return beginSql
+	}
+	if opts.Isolation != sql.LevelDefault {
+		beginSql += " ISOLATION LEVEL " + gstr.ToUpper(opts.Isolation.String())
+	}
+	if opts.ReadOnly {
+		beginSql += " READ ONLY"
+	}
+	return beginSql
+}
+
+// TxOptions is the options for transaction, which is used by Core.TransactionOpts.
+type TxOptions struct {
+	// Isolation is the isolation level of the transaction.
+	// The default isolation level of the driver is used if it is sql.LevelDefault.
+	Isolation sql.IsolationLevel
+
+	// ReadOnly begins a read-only transaction on the slave node like BeginRead,
+	// which rejects all writing operations.
+	ReadOnly bool
+
+	// Timeout is the deadline of each transaction attempt, which is counted from the beginning of
+	// the transaction. The transaction is rolled back by the driver if it is not finished in time.
+	// Note that the earlier deadline takes effect if the parent context has its own deadline,
+	// so it can shorten but never extend the deadline of the parent context.
+	// There's no timeout if it is 0.
+	Timeout time.Duration
+
+	// MaxRetries is the max retry times of the transaction when it fails with a retryable error,
+	// like deadlock, which is the same as TransactionWithRetry. There's no retry if it is 0.
+	MaxRetries int
+}
+
 // Transaction wraps the transaction logic using function `f`.
 // It rollbacks the transaction and returns the error from function `f` if
 // it returns non-nil error. It commits the transaction and returns nil if
 // function `f` returns nil.
 //
 // Note that, you should not Commit or Rollback the transaction in function `f`
-// as it is automatically handled by this function.
+// as it is automatically handled by this function, and calling them in function `f`
+// returns error unless a nested transaction is begun by Begin in function `f`.
 func (c *Core) Transaction(ctx context.Context, f func(ctx context.Context, tx TX) error) (err error) {
+	return c.TransactionOpts(ctx, TxOptions{}, f)
+}
+
+// TransactionOpts wraps the transaction logic using function `f` like Transaction, with given
+// transaction options `opts`, which centralizes the isolation level, read-only routing,
+// per-transaction deadline and retry of retryable errors in one call.
+// It is the same as Transaction if `opts` is zero value.
+//
+// Note that the options are ignored if there's already a transaction in `ctx`,
+// as function `f` runs in the nested transaction using save point.
+func (c *Core) TransactionOpts(
+	ctx context.Context, opts TxOptions, f func(ctx context.Context, tx TX) error,
+) (err error) {
 	if ctx == nil {
 		ctx = c.db.GetCtx()
 	}
 	ctx = c.injectInternalCtxData(ctx)
 	// Check transaction object from context.
-	var tx TX
-	tx = TXFromCtx(ctx, c.db.GetGroup())
-	if tx != nil {
+	if tx := TXFromCtx(ctx, c.db.GetGroup()); tx != nil {
 		return tx.Transaction(ctx, f)
 	}
-	tx, err = c.doBeginCtx(ctx)
+	var interval = transactionRetryBaseInterval
+	for retries := 0; ; retries++ {
+		err = c.doTransaction(ctx, opts, f)
+		if err == nil || retries >= opts.MaxRetries || !c.isRetryableError(err) {
+			return err
+		}
+		intlog.Printf(ctx, `transaction retry %d/%d after %s: %+v`, retries+1, opts.MaxRetries, interval, err)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return err
+		}
+		if interval *= 2; interval > transactionRetryMaxInterval {
+			interval = transactionRetryMaxInterval
+		}
+	}
+}
+
+// doTransaction runs function `f` in a new transaction with given options `opts` only once.
+func (c *Core) doTransaction(
+	ctx context.Context, opts TxOptions, f func(ctx context.Context, tx TX) error,
+) (err error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		// It is called after the transaction is committed or rolled back.
+		defer cancel()
+	}
+	var tx TX
+	if opts.ReadOnly {
+		var slave *sql.DB
+		if slave, err = c.db.Slave(); err != nil {
+			return err
+		}
+		tx, err = c.doBeginCtxWithDb(ctx, slave, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: true})
+	} else if opts.Isolation != sql.LevelDefault {
+		tx, err = c.doBeginCtx(ctx, &sql.TxOptions{Isolation: opts.Isolation})
+	} else {
+		tx, err = c.doBeginCtx(ctx, nil)
+	}
 	if err != nil {
 		return err
 	}
@@ -88,16 +313,16 @@ func (c *Core) Transaction(ctx context.Context, f func(ctx context.Context, tx T
 	defer func() {
 		if err == nil {
 			if exception := recover(); exception != nil {
-				if v, ok := exception.(error); ok && gerror.HasStack(v) {
-					err = v
-				} else {
-					err = gerror.NewCodef(gcode.CodeInternalPanic, "%+v", exception)
-				}
+				err = newTXPanicError(exception)
 			}
 		}
 		if err != nil {
+			// The transaction might be already aborted, eg: by a failed Assert.
+			if tx.IsClosed() {
+				return
+			}
 			if e := tx.Rollback(); e != nil {
-				err = e
+				err = joinRollbackError(err, e)
 			}
 		} else {
 			if e := tx.Commit(); e != nil {
@@ -105,6 +330,11 @@ func (c *Core) Transaction(ctx context.Context, f func(ctx context.Context, tx T
 			}
 		}
 	}()
+	// Mark the transaction as managed, so that it cannot be committed or rolled back in function `f`.
+	if txCore, ok := tx.(*TXCore); ok {
+		txCore.enterManagedScope()
+		defer txCore.leaveManagedScope()
+	}
 	err = f(tx.GetCtx(), tx)
 	return
 }
@@ -125,9 +355,81 @@ func WithTX(ctx context.Context, tx TX) context.Context {
 	}
 	// Inject transaction object and id into context.
 	ctx = context.WithValue(ctx, transactionKeyForContext(group), tx)
+	// Record the group of injected transaction object for TXsFromCtx.
+	var groups []string
+	if v, ok := ctx.Value(contextTransactionGroupsKey).([]string); ok {
+		groups = append(groups, v...)
+	}
+	ctx = context.WithValue(ctx, contextTransactionGroupsKey, append(groups, group))
+	return ctx
+}
+
+// WithTXs injects given transaction objects into context under the keys of their database groups,
+// and returns a new context, which carries the transactions of multiple database groups in one
+// context for coordinated writes across groups.
+//
+// Note that the transaction of a group that is already injected is not overwritten,
+// so the first transaction of the same group takes effect.
+func WithTXs(ctx context.Context, txs ...TX) context.Context {
+	for _, tx := range txs {
+		ctx = WithTX(ctx, tx)
+	}
 	return ctx
 }
 
+// TXsFromCtx retrieves and returns all the transaction objects injected into context by WithTX or WithTXs,
+// which is a map of database group name to its transaction object.
+// The closed transactions are not returned. It returns an empty map if no transaction is injected.
+func TXsFromCtx(ctx context.Context) map[string]TX {
+	var txs = make(map[string]TX)
+	if ctx == nil {
+		return txs
+	}
+	groups, _ := ctx.Value(contextTransactionGroupsKey).([]string)
+	for _, group := range groups {
+		if tx := TXFromCtx(ctx, group); tx != nil {
+			txs[group] = tx
+		}
+	}
+	return txs
+}
+
+// TransactionWithRetry wraps the transaction logic using function `f` like Transaction,
+// but it re-runs `f` in a fresh transaction if the transaction fails with a retryable error,
+// like deadlock or lock wait timeout, which is detected by DB.IsRetryableError of the driver.
+//
+// It waits with exponential backoff before each retry, and retries at most `maxRetries` times.
+// It stops retrying and returns the error immediately if the error is not retryable,
+// or else it returns the last error after the retries are exhausted.
+// It also stops retrying if `ctx` is done while waiting, and returns the last error.
+//
+// The retryable errors can be customized using Core.SetRetryableErrorFunc.
+//
+// Note that it does not retry if there's already a transaction in `ctx`, as the retryable
+// errors abort the whole transaction, which should be retried by the outermost one.
+func (c *Core) TransactionWithRetry(
+	ctx context.Context, maxRetries int, f func(ctx context.Context, tx TX) error,
+) (err error) {
+	return c.TransactionOpts(ctx, TxOptions{MaxRetries: maxRetries}, f)
+}
+
+// IsRetryableError checks and returns whether the given error of transaction is retryable,
+// like deadlock or serialization failure, which is used by TransactionWithRetry.
+// It always returns false by default, and the driver should implement this function
+// for its specific error codes.
+func (c *Core) IsRetryableError(err error) bool {
+	return false
+}
+
+// isRetryableError checks `err` using the custom function set by SetRetryableErrorFunc if any,
+// or else the DB.IsRetryableError of the driver.
+func (c *Core) isRetryableError(err error) bool {
+	if c.retryableErrorFunc != nil {
+		return c.retryableErrorFunc(err)
+	}
+	return c.db.IsRetryableError(err)
+}
+
 // TXFromCtx retrieves and returns transaction object from context.
 // It is usually used in nested transaction feature, and it returns nil if it is not set previously.
 func TXFromCtx(ctx context.Context, group string) TX {
@@ -151,9 +453,60 @@ func transactionKeyForContext(group string) string {
 	return contextTransactionKeyPrefix + group
 }
 
-// transactionKeyForNestedPoint forms and returns the transaction key at current save point.
+// transactionKeyForNestedPoint forms and returns the quoted transaction key at current save point.
 func (tx *TXCore) transactionKeyForNestedPoint() string {
-	return tx.db.GetCore().QuoteWord(transactionPointerPrefix + gconv.String(tx.transactionCount))
+	return tx.db.GetCore().QuoteWord(tx.nestedPointName())
+}
+
+// nestedPointName forms and returns the save point name of nested transaction at current save point.
+// The default save point name is namespaced with the transaction id, eg: "transaction_<id>_1",
+// so that it never collides with the save point names chosen by application.
+func (tx *TXCore) nestedPointName() string {
+	return tx.nestedPointNameOf(tx.transactionCount)
+}
+
+// nestedPointNameOf forms and returns the save point name of nested transaction at nesting level `level`.
+func (tx *TXCore) nestedPointNameOf(level int) string {
+	if name, ok := tx.nestedPointNames[level]; ok {
+		return name
+	}
+	if tx.savePointPrefix != "" {
+		return tx.savePointPrefix + gconv.String(level)
+	}
+	return fmt.Sprintf(`%s_%s_%d`, transactionPointerPrefix, tx.transactionId, level)
+}
+
+// SetSavePointPrefix sets the prefix of save point names for nested transaction,
+// which is "transaction" in default. The nested Begin uses save point name of `prefix` plus the nesting count.
+// It is used for making the save point names predictable, as the default save point names are
+// namespaced with the transaction id for avoiding collision with save points created by application.
+//
+// The parameter `prefix` should contain only letters, digits and underscores and not start with digit.
+// Note that it cannot be changed if current transaction is in a nested transaction procedure.
+func (tx *TXCore) SetSavePointPrefix(prefix string) error {
+	if !gregex.IsMatchString(savePointNamePattern, prefix) {
+		return gerror.NewCodef(
+			gcode.CodeInvalidParameter,
+			`invalid save point prefix "%s", which should contain only letters, digits and underscores`,
+			prefix,
+		)
+	}
+	if tx.transactionCount > 0 {
+		return gerror.NewCode(
+			gcode.CodeInvalidOperation,
+			`cannot change save point prefix in nested transaction procedure`,
+		)
+	}
+	tx.savePointPrefix = prefix
+	return nil
+}
+
+// GetSavePointPrefix returns the prefix of save point names for nested transaction.
+func (tx *TXCore) GetSavePointPrefix() string {
+	if tx.savePointPrefix != "" {
+		return tx.savePointPrefix
+	}
+	return transactionPointerPrefix
 }
 
 // Ctx sets the context for current transaction.
@@ -165,100 +518,702 @@ func (tx *TXCore) Ctx(ctx context.Context) TX {
 	return tx
 }
 
-// GetCtx returns the context for current transaction.
+// getCtxDb returns the db bound with the context of current transaction, which is created once
+// and reused by the models of current transaction until the context changes.
+func (tx *TXCore) getCtxDb() DB {
+	if tx.ctx == nil {
+		return tx.db
+	}
+	if tx.ctxDb == nil || tx.ctxDbCtx != tx.ctx {
+		tx.ctxDb = tx.db.Ctx(tx.ctx)
+		tx.ctxDbCtx = tx.ctx
+	}
+	return tx.ctxDb
+}
+
+// GetCtx returns the context for current transaction, which is the accessor for the context
+// bound to the transaction and can be used for propagating cancellation or tracing information.
 func (tx *TXCore) GetCtx() context.Context {
 	return tx.ctx
 }
 
+// Id returns the id of current transaction, which is the same as the "txid" printed by the
+// sql logger without nesting level, so that the application logs can be correlated with the sql logs.
+func (tx *TXCore) Id() string {
+	return tx.transactionId
+}
+
+// loggerTransactionId returns the transaction id printed as "txid" by the sql logger, which is
+// the id of current transaction plus current nesting level, eg: "17f0c2b9e5a3d4c1a1b2c3d4000a2f:2",
+// so that all statements of one transaction can be correlated across nested transaction procedure.
+func (tx *TXCore) loggerTransactionId() string {
+	return fmt.Sprintf(`%s:%d`, tx.transactionId, tx.transactionCount)
+}
+
 // GetDB returns the DB for current transaction.
 func (tx *TXCore) GetDB() DB {
 	return tx.db
 }
 
 // GetSqlTX returns the underlying transaction object for current transaction.
+// It returns nil for XA transaction, which uses a dedicated connection instead.
 func (tx *TXCore) GetSqlTX() *sql.Tx {
 	return tx.tx
 }
 
+// UnderlyingTx returns the raw database/sql transaction object of current transaction,
+// which is used for driver-specific features that are not exposed by gdb, eg: bulk copying.
+//
+// Note that statements executed on the returned object bypass the logging, tracing, hooks
+// and statement timeout of gdb.
+//
+// It returns error if the transaction has already been committed or rolled back,
+// or if it is an XA transaction, which runs on a dedicated connection instead.
+func (tx *TXCore) UnderlyingTx() (*sql.Tx, error) {
+	if tx.isClosed {
+		return nil, gerror.NewCode(
+			gcode.CodeInvalidOperation,
+			`transaction has already been committed or rolled back`,
+		)
+	}
+	if tx.tx == nil {
+		return nil, gerror.NewCode(
+			gcode.CodeInvalidOperation,
+			`underlying transaction is not available for XA transaction`,
+		)
+	}
+	return tx.tx, nil
+}
+
+// rawLink returns the underlying link for current transaction.
+func (tx *TXCore) rawLink() rawTxLink {
+	if tx.conn != nil {
+		return tx.conn
+	}
+	return tx.tx
+}
+
+// GetOptions returns the transaction options that current transaction begins with.
+// It returns nil if the transaction begins without options.
+func (tx *TXCore) GetOptions() *sql.TxOptions {
+	return tx.options
+}
+
+// Debug enables/disables the SQL logging for all statements of current transaction, including
+// the COMMIT/ROLLBACK statements, regardless of the debug setting of db.
+// The transaction follows the debug setting of db if this function is never called.
+func (tx *TXCore) Debug(enabled bool) TX {
+	tx.debug = gtype.NewBool(enabled)
+	return tx
+}
+
+// SetStatementTimeout sets the max execution time `d` for each statement executed through current
+// transaction, which prevents long-running statements from holding locks indefinitely.
+// The statement is cancelled when timeout, and the returned error wraps context.DeadlineExceeded.
+// It disables the statement timeout if `d` <= 0, which is the default.
+func (tx *TXCore) SetStatementTimeout(d time.Duration) {
+	tx.statementTimeout = d
+}
+
+// SetMetricsLabel sets the business operation label for the metrics of current transaction,
+// eg: "checkout", "signup", which is passed to the sink set by Core.SetTxMetricsSink.
+// The label is empty by default.
+func (tx *TXCore) SetMetricsLabel(label string) TX {
+	tx.metricsLabel = label
+	return tx
+}
+
+// StartTime returns the time when current transaction begins.
+func (tx *TXCore) StartTime() time.Time {
+	return tx.startTime
+}
+
+// Elapsed returns the elapsed time since current transaction begins, which is commonly used for
+// duration-based alerting or bailing out of long-running procedure in current transaction.
+func (tx *TXCore) Elapsed() time.Duration {
+	return time.Since(tx.startTime)
+}
+
+// StatementCount returns the count of Exec/Query statements executed through current transaction.
+func (tx *TXCore) StatementCount() int {
+	return tx.statementCount.Val()
+}
+
+// Checkpoint creates or resets the checkpoint of given `name`, which records the current sum of rows
+// affected by write statements in current transaction. It is used with AffectedSince for measuring
+// the impact of each phase of multi-phase transaction.
+func (tx *TXCore) Checkpoint(name string) {
+	tx.checkpoints.Set(name, tx.affectedRows.Val())
+}
+
+// AffectedSince returns the sum of rows affected by write statements executed since the checkpoint
+// of given `name` was created using Checkpoint. It returns error if the checkpoint does not exist.
+func (tx *TXCore) AffectedSince(checkpoint string) (int64, error) {
+	v := tx.checkpoints.Get(checkpoint)
+	if v == nil {
+		return 0, gerror.NewCodef(gcode.CodeInvalidParameter, `checkpoint "%s" does not exist`, checkpoint)
+	}
+	return tx.affectedRows.Val() - v.(int64), nil
+}
+
+// GetMetricsLabel returns the business operation label for the metrics of current transaction.
+func (tx *TXCore) GetMetricsLabel() string {
+	return tx.metricsLabel
+}
+
+// recordMetrics passes the duration and outcome metrics of current transaction to the metrics sink
+// if there's one.
+func (tx *TXCore) recordMetrics(committed bool, err error) {
+	sink := tx.db.GetTxMetricsSink()
+	if sink == nil {
+		return
+	}
+	sink.RecordTx(tx.ctx, TxMetrics{
+		Group:         tx.db.GetGroup(),
+		Label:         tx.metricsLabel,
+		TransactionId: tx.transactionId,
+		Duration:      tx.Elapsed(),
+		Committed:     committed,
+		Error:         err,
+	})
+}
+
 // Commit commits current transaction.
 // Note that it releases previous saved transaction point if it's in a nested transaction procedure,
 // or else it commits the hole transaction.
-func (tx *TXCore) Commit() error {
+func (tx *TXCore) Commit() (err error) {
+	if tx.isInManagedScope() {
+		return gerror.NewCode(gcode.CodeInvalidOperation, `cannot commit a managed transaction`)
+	}
+	if tx.transactionCount == 0 && tx.IsXA() {
+		return gerror.NewCode(gcode.CodeInvalidOperation, `cannot commit an XA transaction by Commit, use CommitXA instead`)
+	}
+	defer tx.runOutcomeHooks(tx.onCommittedFuncs, &err)
 	if tx.transactionCount > 0 {
 		tx.transactionCount--
-		_, err := tx.Exec("RELEASE SAVEPOINT " + tx.transactionKeyForNestedPoint())
+		if _, err = tx.doExec("RELEASE SAVEPOINT " + tx.transactionKeyForNestedPoint()); err == nil {
+			tx.removeSavePoint(tx.nestedPointName())
+		}
+		delete(tx.nestedPointNames, tx.transactionCount)
 		return err
 	}
-	_, err := tx.db.DoCommit(tx.ctx, DoCommitInput{
+	_, err = tx.db.DoCommit(tx.ctx, DoCommitInput{
 		Tx:            tx.tx,
+		Link:          newTxLink(tx),
 		Sql:           "COMMIT",
 		Type:          SqlTypeTXCommit,
 		IsTransaction: true,
 	})
+	tx.identityMap.Clear()
+	tx.closeStmts()
 	if err == nil {
 		tx.isClosed = true
+		tx.savePoints = nil
+		tx.untrackLeak()
+		tx.runCallbacks(tx.onCommitFuncs)
+	} else {
+		tx.runCallbacks(tx.onRollbackFuncs)
 	}
+	tx.recordMetrics(err == nil, err)
 	return err
 }
 
 // Rollback aborts current transaction.
 // Note that it aborts current transaction if it's in a nested transaction procedure,
 // or else it aborts the hole transaction.
-func (tx *TXCore) Rollback() error {
+func (tx *TXCore) Rollback() (err error) {
+	if tx.isInManagedScope() {
+		return gerror.NewCode(gcode.CodeInvalidOperation, `cannot rollback a managed transaction`)
+	}
 	if tx.transactionCount > 0 {
+		defer tx.runOutcomeHooks(tx.onRolledBackFuncs, &err)
 		tx.transactionCount--
-		_, err := tx.Exec("ROLLBACK TO SAVEPOINT " + tx.transactionKeyForNestedPoint())
+		if _, err = tx.doExec("ROLLBACK TO SAVEPOINT " + tx.transactionKeyForNestedPoint()); err == nil {
+			// The nested transaction is finished, so its save point is no longer tracked.
+			tx.removeSavePoint(tx.nestedPointName())
+		}
+		delete(tx.nestedPointNames, tx.transactionCount)
 		return err
 	}
-	_, err := tx.db.DoCommit(tx.ctx, DoCommitInput{
+	return tx.doRollback()
+}
+
+// doRollback aborts the hole transaction ignoring any nested transaction procedure.
+func (tx *TXCore) doRollback() (err error) {
+	if tx.IsXA() {
+		return tx.RollbackXA()
+	}
+	defer tx.runOutcomeHooks(tx.onRolledBackFuncs, &err)
+	_, err = tx.db.DoCommit(tx.ctx, DoCommitInput{
 		Tx:            tx.tx,
+		Link:          newTxLink(tx),
 		Sql:           "ROLLBACK",
 		Type:          SqlTypeTXRollback,
 		IsTransaction: true,
 	})
+	if err != nil && tx.isAbortedByCancellation(err) {
+		// The transaction has already been rolled back by the underlying driver
+		// as its context or statement is cancelled or timeout.
+		err = nil
+	}
+	tx.identityMap.Clear()
+	tx.closeStmts()
 	if err == nil {
 		tx.isClosed = true
+		tx.savePoints = nil
+		tx.untrackLeak()
+		tx.runCallbacks(tx.onRollbackFuncs)
 	}
+	tx.recordMetrics(false, err)
 	return err
 }
 
+// trackLeak captures the stack where current transaction begins, and sets the finalizer that logs
+// a warning with the captured stack if current transaction is garbage collected without being
+// committed or rolled back, which helps finding the code that leaks the transaction.
+func (tx *TXCore) trackLeak() {
+	tx.beginStack = gdebug.StackWithFilter([]string{stackFilterKeyForTx})
+	runtime.SetFinalizer(tx, (*TXCore).finalizeLeak)
+}
+
+// untrackLeak removes the finalizer set by trackLeak as current transaction is closed.
+func (tx *TXCore) untrackLeak() {
+	if tx.beginStack != "" {
+		runtime.SetFinalizer(tx, nil)
+	}
+}
+
+// finalizeLeak is the finalizer of leaked transaction, which logs the warning and
+// rolls back the underlying transaction to release its connection.
+func (tx *TXCore) finalizeLeak() {
+	if tx.isClosed {
+		return
+	}
+	tx.db.GetLogger().Warningf(
+		tx.ctx,
+		"transaction %s is garbage collected without Commit or Rollback, which begins at:\n%s",
+		tx.transactionId, tx.beginStack,
+	)
+	if tx.tx != nil {
+		_ = tx.tx.Rollback()
+	}
+}
+
+// enterManagedScope marks current nested level of the transaction as managed by Transaction function.
+func (tx *TXCore) enterManagedScope() {
+	tx.managedLevels = append(tx.managedLevels, tx.transactionCount)
+}
+
+// leaveManagedScope unmarks the latest managed level, which is called before Transaction function
+// commits or rolls back the transaction.
+func (tx *TXCore) leaveManagedScope() {
+	if n := len(tx.managedLevels); n > 0 {
+		tx.managedLevels = tx.managedLevels[:n-1]
+	}
+}
+
+// isInManagedScope checks and returns whether the Commit or Rollback is called directly in the function
+// of Transaction, in which the transaction is managed by Transaction function.
+// It returns false if the call is for a nested transaction begun by Begin in that function.
+func (tx *TXCore) isInManagedScope() bool {
+	n := len(tx.managedLevels)
+	return n > 0 && tx.managedLevels[n-1] == tx.transactionCount
+}
+
+// isAbortedByCancellation checks and returns whether the rollback error `err` is caused by the
+// transaction that has already been aborted by the underlying driver, as the context of the transaction
+// is cancelled, or the connection is closed by the driver because of the statement timeout.
+func (tx *TXCore) isAbortedByCancellation(err error) bool {
+	if errors.Is(err, sql.ErrTxDone) && tx.ctx.Err() != nil {
+		return true
+	}
+	return tx.statementTimedOut && (errors.Is(err, sql.ErrTxDone) || errors.Is(err, driver.ErrBadConn))
+}
+
+// OnCommit registers callback function `f` which is executed after the hole transaction is
+// committed successfully, commonly used for cache invalidation or event publishing.
+// Note that releasing the save point of a nested transaction does not trigger the callbacks.
+func (tx *TXCore) OnCommit(f func()) {
+	tx.onCommitFuncs = append(tx.onCommitFuncs, f)
+}
+
+// OnRollback registers callback function `f` which is executed after the hole transaction is
+// rolled back successfully, or the COMMIT statement fails.
+// Note that rolling back to the save point of a nested transaction does not trigger the callbacks.
+func (tx *TXCore) OnRollback(f func()) {
+	tx.onRollbackFuncs = append(tx.onRollbackFuncs, f)
+}
+
+// OnCommitted registers hook function `f` which is executed after every Commit completes,
+// including releasing the save point of a nested transaction. The parameter `err` of `f` is the
+// outcome of the Commit, which is nil if it succeeds. It is commonly used for transaction metrics.
+func (tx *TXCore) OnCommitted(f func(err error)) {
+	tx.onCommittedFuncs = append(tx.onCommittedFuncs, f)
+}
+
+// OnRolledBack registers hook function `f` which is executed after every Rollback completes,
+// including rolling back to the save point of a nested transaction. The parameter `err` of `f` is
+// the outcome of the Rollback, which is nil if it succeeds.
+func (tx *TXCore) OnRolledBack(f func(err error)) {
+	tx.onRolledBackFuncs = append(tx.onRolledBackFuncs, f)
+}
+
+// runOutcomeHooks executes the given hook functions with the outcome error in their registration order.
+func (tx *TXCore) runOutcomeHooks(funcs []func(err error), err *error) {
+	for _, f := range funcs {
+		f(*err)
+	}
+}
+
+// runCallbacks executes the given callback functions in their registration order.
+func (tx *TXCore) runCallbacks(funcs []func()) {
+	for _, f := range funcs {
+		f()
+	}
+}
+
+// Assert runs the assertion function `f` in current transaction only if debug mode is enabled,
+// which is commonly used for verifying invariants (e.g. a COUNT) after writing operations.
+// It does nothing and returns nil if debug mode is disabled, so it costs nothing in production.
+//
+// If `f` returns error, the hole transaction is rolled back, ignoring any nested transaction
+// procedure, and an assertion error wrapping the error from `f` is returned.
+func (tx *TXCore) Assert(f func(tx TX) error) error {
+	if !tx.db.GetDebug() {
+		return nil
+	}
+	assertErr := f(tx)
+	if assertErr == nil {
+		return nil
+	}
+	tx.transactionCount = 0
+	if err := tx.doRollback(); err != nil {
+		intlog.Errorf(tx.ctx, `rollback failed after transaction assertion failure: %+v`, err)
+	}
+	return gerror.WrapCode(gcode.CodeInvalidOperation, assertErr, `transaction assertion failed`)
+}
+
+// ValidateConstraints forces all deferred constraints of current transaction to be checked immediately
+// using `SET CONSTRAINTS ALL IMMEDIATE` statement, which makes integrity violations surface at a
+// controlled point rather than at an opaque commit failure.
+//
+// It is commonly used in data-migration transactions on databases that support deferrable
+// constraints, like PostgreSQL and Oracle. The returned error contains the violated constraint
+// reported by the database server.
+func (tx *TXCore) ValidateConstraints() error {
+	if _, err := tx.doExec("SET CONSTRAINTS ALL IMMEDIATE"); err != nil {
+		return gerror.WrapCode(gcode.CodeDbOperationError, err, `deferred constraints validation failed`)
+	}
+	return nil
+}
+
+// IsReadOnly checks and returns whether current transaction is read-only,
+// which rejects all writing operations.
+func (tx *TXCore) IsReadOnly() bool {
+	return tx.options != nil && tx.options.ReadOnly
+}
+
+// newTxReadOnlyError creates and returns the error for writing operations on read-only transaction.
+func newTxReadOnlyError() error {
+	return gerror.NewCode(gcode.CodeInvalidOperation, `transaction is read-only`)
+}
+
 // IsClosed checks and returns this transaction has already been committed or rolled back.
 func (tx *TXCore) IsClosed() bool {
 	return tx.isClosed
 }
 
+// NestedLevel returns the nesting level of current transaction, which is the count of
+// nested transactions (save points) that are begun but not yet committed or rolled back.
+// It returns 0 if current transaction is at the top level.
+func (tx *TXCore) NestedLevel() int {
+	return tx.transactionCount
+}
+
+// IsNested checks and returns whether current transaction is in a nested transaction procedure,
+// in which Commit releases the save point rather than committing the whole transaction.
+func (tx *TXCore) IsNested() bool {
+	return tx.transactionCount > 0
+}
+
+// SetMaxDepth sets the max nesting depth `n` of nested transactions, which makes nested Begin
+// return error instead of creating another save point if the depth exceeds the limit.
+// It is used for spotting runaway recursion in transaction helpers.
+// The nesting depth is unlimited if `n` <= 0, which is the default.
+func (tx *TXCore) SetMaxDepth(n int) {
+	tx.maxDepth = n
+}
+
 // Begin starts a nested transaction procedure.
+// It returns error if the nesting depth exceeds the max depth set by SetMaxDepth.
 func (tx *TXCore) Begin() error {
-	_, err := tx.Exec("SAVEPOINT " + tx.transactionKeyForNestedPoint())
+	return tx.beginNested("")
+}
+
+// beginNested starts a nested transaction procedure using the save point of given name `name`,
+// or the save point named by current nesting level if `name` is empty.
+func (tx *TXCore) beginNested(name string) error {
+	if tx.maxDepth > 0 && tx.NestedLevel() >= tx.maxDepth {
+		return gerror.NewCodef(
+			gcode.CodeInvalidOperation,
+			`nested transaction exceeds the max depth %d, current depth is %d`,
+			tx.maxDepth, tx.NestedLevel(),
+		)
+	}
+	if name != "" {
+		if tx.nestedPointNames == nil {
+			tx.nestedPointNames = make(map[int]string)
+		}
+		tx.nestedPointNames[tx.transactionCount] = name
+	}
+	_, err := tx.doExec("SAVEPOINT " + tx.transactionKeyForNestedPoint())
 	if err != nil {
+		delete(tx.nestedPointNames, tx.transactionCount)
 		return err
 	}
+	tx.addSavePoint(tx.nestedPointName())
 	tx.transactionCount++
 	return nil
 }
 
 // SavePoint performs `SAVEPOINT xxx` SQL statement that saves transaction at current point.
-// The parameter `point` specifies the point name that will be saved to server.
+// The parameter `point` specifies the point name that will be saved to server,
+// which should contain only letters, digits and underscores and not start with digit.
 func (tx *TXCore) SavePoint(point string) error {
-	_, err := tx.Exec("SAVEPOINT " + tx.db.GetCore().QuoteWord(point))
+	if err := checkSavePointName(point); err != nil {
+		return err
+	}
+	_, err := tx.doExec("SAVEPOINT " + tx.db.GetCore().QuoteWord(point))
+	if err == nil {
+		tx.addSavePoint(point)
+	}
 	return err
 }
 
 // RollbackTo performs `ROLLBACK TO SAVEPOINT xxx` SQL statement that rollbacks to specified saved transaction.
-// The parameter `point` specifies the point name that was saved previously.
+// The parameter `point` specifies the point name that was saved previously,
+// which should contain only letters, digits and underscores and not start with digit.
+//
+// Unlike the nested Rollback, which rollbacks to the save point of current nested transaction and decreases
+// the nesting depth by one, it does not change the nesting depth if `point` is created in current nested
+// transaction. However, if `point` is created before nested transactions begun by Begin, the save points of
+// those nested transactions are dropped by the database, so they are finished and the nesting depth is
+// decreased accordingly, which keeps the following nested Commit/Rollback consistent with the database.
+// Use RollbackToSavePointKeeping to keep the nesting depth in this case.
+//
+// It returns error if it would finish the nested transaction managed by Transaction function.
+// Note that only the save points created by Begin and SavePoint are tracked for the nesting depth.
 func (tx *TXCore) RollbackTo(point string) error {
-	_, err := tx.Exec("ROLLBACK TO SAVEPOINT " + tx.db.GetCore().QuoteWord(point))
+	if err := checkSavePointName(point); err != nil {
+		return err
+	}
+	var droppedLevels = tx.nestedLevelsAfter(point)
+	if n := len(tx.managedLevels); n > 0 && tx.transactionCount-droppedLevels < tx.managedLevels[n-1] {
+		return gerror.NewCodef(
+			gcode.CodeInvalidOperation,
+			`cannot rollback to save point "%s" created before the managed transaction`,
+			point,
+		)
+	}
+	_, err := tx.doExec("ROLLBACK TO SAVEPOINT " + tx.db.GetCore().QuoteWord(point))
+	if err == nil {
+		tx.rollbackSavePointsTo(point)
+		// The nested transactions whose save points are dropped are finished.
+		for i := 0; i < droppedLevels; i++ {
+			tx.transactionCount--
+			delete(tx.nestedPointNames, tx.transactionCount)
+		}
+	}
 	return err
 }
 
+// RollbackToSavePointKeeping performs `ROLLBACK TO SAVEPOINT xxx` SQL statement like RollbackTo,
+// but it always keeps the nesting depth of nested transactions, which is not changed by this function.
+//
+// If `point` is created before nested transactions begun by Begin, the save points of those nested
+// transactions, which are dropped by the database, are created again right after the rollback, so that
+// the nested transactions remain active and can be committed or rolled back by the nested Commit/Rollback
+// as usual. Note that the changes of those nested transactions after `point` are rolled back as well.
+func (tx *TXCore) RollbackToSavePointKeeping(point string) error {
+	if err := checkSavePointName(point); err != nil {
+		return err
+	}
+	var droppedLevels = tx.nestedLevelsAfter(point)
+	if _, err := tx.doExec("ROLLBACK TO SAVEPOINT " + tx.db.GetCore().QuoteWord(point)); err != nil {
+		return err
+	}
+	tx.rollbackSavePointsTo(point)
+	for level := tx.transactionCount - droppedLevels; level < tx.transactionCount; level++ {
+		name := tx.nestedPointNameOf(level)
+		if _, err := tx.doExec("SAVEPOINT " + tx.db.GetCore().QuoteWord(name)); err != nil {
+			// The nested transactions whose save points cannot be created again are finished.
+			for tx.transactionCount > level {
+				tx.transactionCount--
+				delete(tx.nestedPointNames, tx.transactionCount)
+			}
+			return err
+		}
+		tx.addSavePoint(name)
+	}
+	return nil
+}
+
+// nestedLevelsAfter returns the count of the innermost nested transactions whose save points are created
+// after save point `point`, which are dropped by rolling back to `point`.
+// It returns 0 if `point` is not tracked.
+func (tx *TXCore) nestedLevelsAfter(point string) int {
+	var index = -1
+	for i := len(tx.savePoints) - 1; i >= 0; i-- {
+		if tx.savePoints[i] == point {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return 0
+	}
+	var (
+		levels    int
+		pointsSet = make(map[string]struct{}, len(tx.savePoints)-index-1)
+	)
+	for _, v := range tx.savePoints[index+1:] {
+		pointsSet[v] = struct{}{}
+	}
+	for level := tx.transactionCount - 1; level >= 0; level-- {
+		if _, ok := pointsSet[tx.nestedPointNameOf(level)]; !ok {
+			break
+		}
+		levels++
+	}
+	return levels
+}
+
+// SavePoints returns the names of the active save points of current transaction in creation order,
+// which are created by nested Begin and SavePoint. It is commonly used for debugging complex nested logic.
+//
+// The save points created after the one rolled back to by RollbackTo are dropped, and the save points
+// released by nested Commit or finished by nested Rollback are removed, which reflects the server state.
+// Note that the save points created by raw statements through Exec are not tracked.
+func (tx *TXCore) SavePoints() []string {
+	points := make([]string, len(tx.savePoints))
+	copy(points, tx.savePoints)
+	return points
+}
+
+// addSavePoint tracks the newly created save point `point`.
+// The previous save point of the same name is replaced, as most databases do.
+func (tx *TXCore) addSavePoint(point string) {
+	for i, v := range tx.savePoints {
+		if v == point {
+			tx.savePoints = append(tx.savePoints[:i], tx.savePoints[i+1:]...)
+			break
+		}
+	}
+	tx.savePoints = append(tx.savePoints, point)
+}
+
+// removeSavePoint removes the released save point `point` and the ones created after it.
+func (tx *TXCore) removeSavePoint(point string) {
+	for i := len(tx.savePoints) - 1; i >= 0; i-- {
+		if tx.savePoints[i] == point {
+			tx.savePoints = tx.savePoints[:i]
+			return
+		}
+	}
+}
+
+// rollbackSavePointsTo drops the save points created after save point `point`,
+// which remains active after rolling back to it.
+func (tx *TXCore) rollbackSavePointsTo(point string) {
+	for i := len(tx.savePoints) - 1; i >= 0; i-- {
+		if tx.savePoints[i] == point {
+			tx.savePoints = tx.savePoints[:i+1]
+			return
+		}
+	}
+}
+
+// checkSavePointName checks whether the save point name `point` contains only letters, digits and underscores,
+// which prevents SQL injection through save point names that originate from user input.
+func checkSavePointName(point string) error {
+	if !gregex.IsMatchString(savePointNamePattern, point) {
+		return gerror.NewCodef(
+			gcode.CodeInvalidParameter,
+			`invalid save point name "%s", which should match pattern "%s"`,
+			point, savePointNamePattern,
+		)
+	}
+	return nil
+}
+
+// SavePointFunc wraps the save point logic using function `f`, which is like Transaction but
+// using the save point of given name `point`.
+// It performs `SAVEPOINT xxx` before calling function `f`, and performs `ROLLBACK TO SAVEPOINT xxx`
+// if function `f` returns non-nil error or panics, or else it performs `RELEASE SAVEPOINT xxx`.
+// The parameter `point` specifies the point name, and nested calls should use distinct names.
+func (tx *TXCore) SavePointFunc(point string, f func() error) (err error) {
+	if err = tx.SavePoint(point); err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			if exception := recover(); exception != nil {
+				err = newTXPanicError(exception)
+			}
+		}
+		if err != nil {
+			if e := tx.RollbackTo(point); e != nil {
+				err = joinRollbackError(err, e)
+			}
+		} else {
+			if _, e := tx.doExec("RELEASE SAVEPOINT " + tx.db.GetCore().QuoteWord(point)); e != nil {
+				err = e
+			} else {
+				tx.removeSavePoint(point)
+			}
+		}
+	}()
+	err = f()
+	return
+}
+
 // Transaction wraps the transaction logic using function `f`.
 // It rollbacks the transaction and returns the error from function `f` if
 // it returns non-nil error. It commits the transaction and returns nil if
 // function `f` returns nil.
 //
 // Note that, you should not Commit or Rollback the transaction in function `f`
-// as it is automatically handled by this function.
+// as it is automatically handled by this function, and calling them in function `f`
+// returns error unless a nested transaction is begun by Begin in function `f`.
 func (tx *TXCore) Transaction(ctx context.Context, f func(ctx context.Context, tx TX) error) (err error) {
+	return tx.doTransaction(ctx, "", f)
+}
+
+// TransactionNamed wraps the nested transaction logic using function `f` like Transaction,
+// but it uses given `name` as the save point name of the nested transaction instead of the
+// one named by nesting level, so that the reentrant calls of the same logical unit use a
+// predictable save point, which shows up clearly in the sql logs.
+//
+// The parameter `name` should contain only letters, digits and underscores and not start with digit.
+// It returns error if the save point of `name` is already active in current transaction,
+// rather than silently overwriting it.
+func (tx *TXCore) TransactionNamed(ctx context.Context, name string, f func(ctx context.Context, tx TX) error) (err error) {
+	if err = checkSavePointName(name); err != nil {
+		return err
+	}
+	for _, point := range tx.savePoints {
+		if point == name {
+			return gerror.NewCodef(
+				gcode.CodeInvalidOperation, `save point "%s" already exists in current transaction`, name,
+			)
+		}
+	}
+	return tx.doTransaction(ctx, name, f)
+}
+
+// doTransaction wraps the nested transaction logic using function `f`,
+// which uses the save point of given name `name` if it is not empty.
+func (tx *TXCore) doTransaction(ctx context.Context, name string, f func(ctx context.Context, tx TX) error) (err error) {
 	if ctx != nil {
 		tx.ctx = ctx
 	}
@@ -267,23 +1222,23 @@ func (tx *TXCore) Transaction(ctx context.Context, f func(ctx context.Context, t
 		// Inject transaction object into context.
 		tx.ctx = WithTX(tx.ctx, tx)
 	}
-	err = tx.Begin()
+	err = tx.beginNested(name)
 	if err != nil {
 		return err
 	}
 	defer func() {
 		if err == nil {
 			if exception := recover(); exception != nil {
-				if v, ok := exception.(error); ok && gerror.HasStack(v) {
-					err = v
-				} else {
-					err = gerror.NewCodef(gcode.CodeInternalPanic, "%+v", exception)
-				}
+				err = newTXPanicError(exception)
 			}
 		}
 		if err != nil {
+			// The transaction might be already aborted, eg: by a failed Assert.
+			if tx.IsClosed() {
+				return
+			}
 			if e := tx.Rollback(); e != nil {
-				err = e
+				err = joinRollbackError(err, e)
 			}
 		} else {
 			if e := tx.Commit(); e != nil {
@@ -291,6 +1246,9 @@ func (tx *TXCore) Transaction(ctx context.Context, f func(ctx context.Context, t
 			}
 		}
 	}()
+	// Mark the nested transaction as managed, so that it cannot be committed or rolled back in function `f`.
+	tx.enterManagedScope()
+	defer tx.leaveManagedScope()
 	err = f(tx.ctx, tx)
 	return
 }
@@ -298,22 +1256,154 @@ func (tx *TXCore) Transaction(ctx context.Context, f func(ctx context.Context, t
 // Query does query operation on transaction.
 // See Core.Query.
 func (tx *TXCore) Query(sql string, args ...interface{}) (result Result, err error) {
-	return tx.db.DoQuery(tx.ctx, &txLink{tx.tx}, sql, args...)
+	return tx.QueryWithContext(tx.ctx, sql, args...)
+}
+
+// QueryWithContext does query operation on transaction using given context `ctx` instead of the
+// context of the transaction, which is commonly used for a single statement having its own deadline.
+//
+// Note that QueryContext is the raw implementation of interface Link, which has no SQL logging.
+func (tx *TXCore) QueryWithContext(ctx context.Context, sql string, args ...interface{}) (result Result, err error) {
+	ctx, cancel := tx.statementCtx(ctx)
+	defer cancel()
+	result, err = tx.db.DoQuery(ctx, newTxLink(tx), sql, args...)
+	return result, tx.checkStatementTimeout(ctx, err)
 }
 
 // Exec does none query operation on transaction.
 // See Core.Exec.
 func (tx *TXCore) Exec(sql string, args ...interface{}) (sql.Result, error) {
-	return tx.db.DoExec(tx.ctx, &txLink{tx.tx}, sql, args...)
+	return tx.ExecWithContext(tx.ctx, sql, args...)
+}
+
+// ExecWithContext does none query operation on transaction using given context `ctx` instead of the
+// context of the transaction, which is commonly used for a single statement having its own deadline.
+//
+// Note that ExecContext is the raw implementation of interface Link, which has no SQL logging.
+func (tx *TXCore) ExecWithContext(ctx context.Context, sql string, args ...interface{}) (sql.Result, error) {
+	if tx.IsReadOnly() {
+		return nil, newTxReadOnlyError()
+	}
+	return tx.doExecWithContext(ctx, sql, args...)
+}
+
+// ExecResult is the result of TX.ExecResult, in which the affected rows and the last insert id
+// of the statement are already resolved.
+type ExecResult struct {
+	RowsAffected    int64 // RowsAffected is the number of rows affected by the statement.
+	LastInsertId    int64 // LastInsertId is the last insert id, which is 0 if LastInsertIdErr is not nil.
+	LastInsertIdErr error // LastInsertIdErr is ErrLastInsertIdUnsupported if the driver does not support LastInsertId.
+}
+
+var (
+	// ErrLastInsertIdUnsupported is attached to ExecResult if the driver does not support LastInsertId, eg: PostgreSQL.
+	ErrLastInsertIdUnsupported = gerror.NewWithOption(gerror.Option{
+		Text: "last insert id is not supported by the driver",
+		Code: gcode.CodeNotSupported,
+	})
+)
+
+// ExecResult does none query operation on transaction like Exec, and returns both the affected rows
+// and the last insert id of the statement, which trims the boilerplate of checking them separately.
+// It returns error if the statement fails or the affected rows cannot be retrieved.
+//
+// Only some drivers populate the last insert id: MySQL/MariaDB/TiDB and SQLite populate both the
+// affected rows and the last insert id, while PostgreSQL, SQL Server, Oracle and DM populate only
+// the affected rows. If the driver does not support it, the LastInsertId of the result is 0 and
+// the LastInsertIdErr is ErrLastInsertIdUnsupported, rather than failing the whole call.
+func (tx *TXCore) ExecResult(sql string, args ...interface{}) (*ExecResult, error) {
+	result, err := tx.Exec(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	var execResult = &ExecResult{}
+	if execResult.RowsAffected, err = result.RowsAffected(); err != nil {
+		return nil, err
+	}
+	if execResult.LastInsertId, err = result.LastInsertId(); err != nil {
+		// The driver does not support LastInsertId, eg: PostgreSQL.
+		intlog.Printf(tx.ctx, `retrieve last insert id failed: %+v`, err)
+		execResult.LastInsertId = 0
+		execResult.LastInsertIdErr = ErrLastInsertIdUnsupported
+	}
+	return execResult, nil
+}
+
+// doExec does none query operation on transaction without read-only checks,
+// which is used for internal statements like save points.
+func (tx *TXCore) doExec(sql string, args ...interface{}) (sql.Result, error) {
+	return tx.doExecWithContext(tx.ctx, sql, args...)
+}
+
+// doExecWithContext does none query operation on transaction using given context without read-only checks.
+func (tx *TXCore) doExecWithContext(ctx context.Context, sql string, args ...interface{}) (result sql.Result, err error) {
+	ctx, cancel := tx.statementCtx(ctx)
+	defer cancel()
+	result, err = tx.db.DoExec(ctx, newTxLink(tx), sql, args...)
+	return result, tx.checkStatementTimeout(ctx, err)
+}
+
+// statementCtx derives and returns the context for single statement from `ctx`,
+// which has the statement timeout of current transaction if it is set.
+func (tx *TXCore) statementCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if tx.statementTimeout > 0 {
+		return context.WithTimeout(ctx, tx.statementTimeout)
+	}
+	return ctx, func() {}
+}
+
+// checkStatementTimeout checks whether the statement error `err` is caused by the statement timeout,
+// and if so, it marks the transaction and makes sure the returned error wraps context.DeadlineExceeded.
+func (tx *TXCore) checkStatementTimeout(statementCtx context.Context, err error) error {
+	if err == nil || tx.statementTimeout <= 0 || statementCtx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	tx.statementTimedOut = true
+	if errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return gerror.WrapCodef(gcode.CodeDbOperationError, context.DeadlineExceeded, `%v`, err)
 }
 
 // Prepare creates a prepared statement for later queries or executions.
 // Multiple queries or executions may be run concurrently from the
 // returned statement.
-// The caller must call the statement's Close method
-// when the statement is no longer needed.
+//
+// The prepared statement is cached by its `sql` in current transaction, so that repeated
+// Prepare of identical `sql` returns the cached statement, which is closed automatically
+// when the transaction is committed or rolled back, and its Close method does nothing.
+// The caching can be disabled by configuration "txStmtCacheDisabled", in which case
+// the caller must call the statement's Close method when the statement is no longer needed.
 func (tx *TXCore) Prepare(sql string) (*Stmt, error) {
-	return tx.db.DoPrepare(tx.ctx, &txLink{tx.tx}, sql)
+	if tx.db.GetConfig().TxStmtCacheDisabled {
+		return tx.db.DoPrepare(tx.ctx, newTxLink(tx), sql)
+	}
+	if v := tx.stmts.Get(sql); v != nil {
+		return v.(*Stmt), nil
+	}
+	stmt, err := tx.db.DoPrepare(tx.ctx, newTxLink(tx), sql)
+	if err != nil {
+		return nil, err
+	}
+	stmt.cached = true
+	if !tx.stmts.SetIfNotExist(sql, stmt) {
+		// The same sql is prepared and cached concurrently.
+		_ = stmt.Stmt.Close()
+		return tx.stmts.Get(sql).(*Stmt), nil
+	}
+	return stmt, nil
+}
+
+// closeStmts closes and removes all the cached prepared statements of current transaction.
+func (tx *TXCore) closeStmts() {
+	tx.stmts.LockFunc(func(m map[string]interface{}) {
+		for sql, v := range m {
+			if err := v.(*Stmt).Stmt.Close(); err != nil {
+				intlog.Errorf(tx.ctx, `close prepared statement failed: %+v`, err)
+			}
+			delete(m, sql)
+		}
+	})
 }
 
 // GetAll queries and returns data records from database.
@@ -397,17 +1487,38 @@ func (tx *TXCore) GetValue(sql string, args ...interface{}) (Value, error) {
 }
 
 // GetCount queries and returns the count from database.
+// The simple query is rewritten as "SELECT COUNT(1) FROM ..." directly, and the query that already
+// starts with "SELECT COUNT(" is committed directly. The query containing GROUP BY, DISTINCT, UNION,
+// LIMIT or sub queries is wrapped as sub query of "SELECT COUNT(1) FROM (...)" statement,
+// as the rewriting of its select fields is unsafe.
 func (tx *TXCore) GetCount(sql string, args ...interface{}) (int64, error) {
-	if !gregex.IsMatchString(`(?i)SELECT\s+COUNT\(.+\)\s+FROM`, sql) {
-		sql, _ = gregex.ReplaceString(`(?i)(SELECT)\s+(.+)\s+(FROM)`, `$1 COUNT($2) $3`, sql)
-	}
-	value, err := tx.GetValue(sql, args...)
+	value, err := tx.GetValue(formatCountSql(sql), args...)
 	if err != nil {
 		return 0, err
 	}
 	return value.Int64(), nil
 }
 
+// formatCountSql formats and returns the count statement for given query `sql`.
+func formatCountSql(sql string) string {
+	var (
+		selects, _ = gregex.MatchAllString(`(?i)\bSELECT\b`, sql)
+		isUnsafe   = len(selects) > 1 || gregex.IsMatchString(
+			`(?i)\b(GROUP\s+BY|DISTINCT|UNION|LIMIT|HAVING|OFFSET)\b`, sql,
+		)
+	)
+	if isUnsafe {
+		return fmt.Sprintf(`SELECT COUNT(1) FROM (%s) AS _count_alias`, sql)
+	}
+	if gregex.IsMatchString(`(?i)^\s*SELECT\s+COUNT\(`, sql) {
+		return sql
+	}
+	// ORDER BY is meaningless for counting and is rejected by some databases without GROUP BY.
+	sql, _ = gregex.ReplaceString(`(?is)\s+ORDER\s+BY\s+.+$`, ``, sql)
+	countSql, _ := gregex.ReplaceString(`(?is)^\s*SELECT\s+.+?\s+FROM\s+`, `SELECT COUNT(1) FROM `, sql)
+	return countSql
+}
+
 // Insert does "INSERT INTO ..." statement for the table.
 // If there's already one unique record of the data in the table, it returns error.
 //
@@ -416,12 +1527,10 @@ func (tx *TXCore) GetCount(sql string, args ...interface{}) (int64, error) {
 // Data(g.Map{"uid": 10000, "name":"john"})
 // Data(g.Slice{g.Map{"uid": 10000, "name":"john"}, g.Map{"uid": 20000, "name":"smith"})
 //
-// The parameter `batch` specifies the batch operation count when given data is slice.
+// The parameter `batch` specifies the batch operation count when given data is slice,
+// which is the BatchSize of configuration if not given.
 func (tx *TXCore) Insert(table string, data interface{}, batch ...int) (sql.Result, error) {
-	if len(batch) > 0 {
-		return tx.Model(table).Ctx(tx.ctx).Data(data).Batch(batch[0]).Insert()
-	}
-	return tx.Model(table).Ctx(tx.ctx).Data(data).Insert()
+	return tx.Model(table).Data(data).Batch(tx.getBatch(batch)).Insert()
 }
 
 // InsertIgnore does "INSERT IGNORE INTO ..." statement for the table.
@@ -432,20 +1541,277 @@ func (tx *TXCore) Insert(table string, data interface{}, batch ...int) (sql.Resu
 // Data(g.Map{"uid": 10000, "name":"john"})
 // Data(g.Slice{g.Map{"uid": 10000, "name":"john"}, g.Map{"uid": 20000, "name":"smith"})
 //
-// The parameter `batch` specifies the batch operation count when given data is slice.
+// The parameter `batch` specifies the batch operation count when given data is slice,
+// which is the BatchSize of configuration if not given.
 func (tx *TXCore) InsertIgnore(table string, data interface{}, batch ...int) (sql.Result, error) {
+	return tx.Model(table).Data(data).Batch(tx.getBatch(batch)).InsertIgnore()
+}
+
+// InsertIgnoreResult does "INSERT IGNORE INTO ..." statement for the table with single record `data`,
+// and returns whether the record was actually inserted or skipped due to a duplicate unique key.
+//
+// It determines the outcome by the affected rows count of the statement, whose reliability depends
+// on the driver:
+// 1. MySQL/MariaDB: reliable, the affected rows count is 1 if inserted and 0 if skipped.
+// 2. PostgreSQL: reliable, it uses "ON CONFLICT DO NOTHING" that returns no row if skipped.
+// 3. SQLite: reliable, it uses "INSERT OR IGNORE" that affects no row if skipped.
+// 4. Other drivers that do not support insert ignore feature return an error.
+func (tx *TXCore) InsertIgnoreResult(table string, data interface{}) (inserted bool, err error) {
+	result, err := tx.Model(table).Data(data).InsertIgnore()
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// InsertIfNotExists inserts single record `data` into the table only if there's no record matching
+// `existsCondition`, and returns whether the record was inserted.
+//
+// It does "INSERT INTO ... SELECT ... WHERE NOT EXISTS (SELECT 1 FROM ... WHERE ...)" statement,
+// which does the existence check and the inserting in one statement instead of two round trips.
+// Note that it does not prevent duplicates under concurrency by itself: without a unique index,
+// concurrent statements can all pass the NOT EXISTS check and insert duplicated records, eg: under
+// READ COMMITTED isolation level, which is the default of PostgreSQL. A unique constraint on the
+// columns of `existsCondition` is still required for that.
+//
+// The parameter `existsCondition` is the same as the parameter of Model.Where function, see Model.Where.
+func (tx *TXCore) InsertIfNotExists(
+	table string, data interface{}, existsCondition interface{}, args ...interface{},
+) (inserted bool, err error) {
+	var core = tx.db.GetCore()
+	dataMap, err := core.ConvertDataForRecord(tx.ctx, data, table)
+	if err != nil {
+		return false, err
+	}
+	if len(dataMap) == 0 {
+		return false, gerror.NewCode(gcode.CodeMissingParameter, "inserting into table with empty data")
+	}
+	var (
+		model  = tx.Model(table).Where(existsCondition, args...)
+		keys   = make([]string, 0, len(dataMap))
+		fields = make([]string, 0, len(dataMap))
+		values = make([]interface{}, 0, len(dataMap))
+	)
+	for key := range dataMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, core.QuoteWord(key))
+		values = append(values, dataMap[key])
+	}
+	holders, err := tx.getInsertSelectHolders(model, keys)
+	if err != nil {
+		return false, err
+	}
+	var (
+		conditionWhere, conditionExtra, conditionArgs = model.formatCondition(tx.ctx, false, false)
+		insertSql                                     = fmt.Sprintf(
+			"INSERT INTO %s(%s) SELECT %s%s WHERE NOT EXISTS (SELECT 1 FROM %s%s)",
+			core.QuotePrefixTableName(table),
+			gstr.Join(fields, ","),
+			gstr.Join(holders, ","),
+			tx.getFromDualClause(),
+			model.tables,
+			conditionWhere+conditionExtra,
+		)
+	)
+	result, err := tx.Exec(insertSql, append(values, conditionArgs...)...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// getInsertSelectHolders returns the placeholders of columns `keys` of the table of `model` in the SELECT
+// clause of "INSERT INTO ... SELECT ..." statement. The placeholders are casted to the column types
+// for pgsql, as its untyped placeholders in SELECT are resolved as text, which fails inserting into
+// the columns of other types, eg: integer and timestamp.
+func (tx *TXCore) getInsertSelectHolders(model *Model, keys []string) ([]string, error) {
+	var holders = make([]string, len(keys))
+	for i := range holders {
+		holders[i] = "?"
+	}
+	switch gstr.ToLower(tx.db.GetConfig().Type) {
+	case "pgsql":
+		tableFields, err := model.TableFields(model.tablesInit)
+		if err != nil {
+			return nil, err
+		}
+		for i, key := range keys {
+			if field, ok := tableFields[key]; ok && field.Type != "" {
+				holders[i] = fmt.Sprintf("CAST(? AS %s)", tx.db.GetCore().QuoteWord(field.Type))
+			}
+		}
+	}
+	return holders, nil
+}
+
+// getBatch returns the batch size for batch Insert/Replace/Save operations of transaction,
+// which is `batch` if given, or else the BatchSize of configuration. It returns 0 if none of them
+// is specified, which means all the data are committed in one statement.
+func (tx *TXCore) getBatch(batch []int) int {
 	if len(batch) > 0 {
-		return tx.Model(table).Ctx(tx.ctx).Data(data).Batch(batch[0]).InsertIgnore()
+		return batch[0]
 	}
-	return tx.Model(table).Ctx(tx.ctx).Data(data).InsertIgnore()
+	return tx.db.GetConfig().BatchSize
+}
+
+// getFromDualClause returns the "FROM DUAL" clause for the databases that require a table
+// in SELECT statement, or else an empty string.
+func (tx *TXCore) getFromDualClause() string {
+	switch gstr.ToLower(tx.db.GetConfig().Type) {
+	case "mysql", "mariadb", "tidb", "oracle", "dm":
+		return " FROM DUAL"
+	}
+	return ""
 }
 
 // InsertAndGetId performs action Insert and returns the last insert id that automatically generated.
 func (tx *TXCore) InsertAndGetId(table string, data interface{}, batch ...int) (int64, error) {
-	if len(batch) > 0 {
-		return tx.Model(table).Ctx(tx.ctx).Data(data).Batch(batch[0]).InsertAndGetId()
+	return tx.Model(table).Data(data).Batch(tx.getBatch(batch)).InsertAndGetId()
+}
+
+// InsertAndReturning performs action Insert and returns the inserted record, which contains the
+// columns of `returning`, or all columns if `returning` is not given. It is commonly used for
+// retrieving the server generated columns, eg: auto increment id and default timestamp.
+//
+// It uses `RETURNING` clause for the drivers supporting it, eg: pgsql, or else it falls back to
+// querying the inserted record by the last insert id using primary key, eg: mysql.
+// Note that only the first inserted record is returned if `data` contains multiple records.
+func (tx *TXCore) InsertAndReturning(table string, data interface{}, returning ...string) (Record, error) {
+	if len(returning) == 0 {
+		returning = []string{"*"}
+	}
+	result, returningResult, err := tx.doInsertReturning(table, data, returning)
+	if err != nil {
+		return nil, err
+	}
+	if returningResult != nil {
+		if records := returningResult.Returning(); len(records) > 0 {
+			return records[0], nil
+		}
+		return nil, nil
+	}
+	lastInsertId, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	model := tx.Model(table).WherePri(lastInsertId)
+	if returning[0] != "*" {
+		model = model.Fields(gconv.Interfaces(returning)...)
 	}
-	return tx.Model(table).Ctx(tx.ctx).Data(data).InsertAndGetId()
+	return model.One()
+}
+
+// InsertAndReturn performs action Insert with `RETURNING` clause, and scans the columns `returning`
+// of the inserted records into `pointer`, or all columns if `returning` is empty.
+// The parameter `pointer` can be type of *struct/**struct for inserting single record,
+// or *[]struct/*[]*struct for inserting multiple records in `data`, which are inserted using
+// one statement, so that all the inserted records are returned in order.
+//
+// Unlike InsertAndReturning, it does not fall back to querying the inserted records,
+// and returns error with code gcode.CodeNotSupported without inserting anything
+// if the database does not support `RETURNING` clause, eg: mysql.
+func (tx *TXCore) InsertAndReturn(table string, data interface{}, returning []string, pointer interface{}) error {
+	if !tx.isReturningSupported() {
+		return gerror.NewCodef(
+			gcode.CodeNotSupported,
+			`RETURNING clause is not supported by database type "%s"`, tx.db.GetConfig().Type,
+		)
+	}
+	if len(returning) == 0 {
+		returning = []string{"*"}
+	}
+	_, returningResult, err := tx.doInsertReturning(table, data, returning)
+	if err != nil {
+		return err
+	}
+	if returningResult == nil {
+		return gerror.NewCodef(
+			gcode.CodeNotSupported,
+			`RETURNING clause is not supported by driver of database type "%s"`, tx.db.GetConfig().Type,
+		)
+	}
+	var records = returningResult.Returning()
+	if reflectType := reflect.TypeOf(pointer); reflectType != nil && reflectType.Kind() == reflect.Ptr &&
+		reflectType.Elem().Kind() == reflect.Slice {
+		return records.Structs(pointer)
+	}
+	if len(records) == 0 {
+		return sql.ErrNoRows
+	}
+	return records[0].Struct(pointer)
+}
+
+// doInsertReturning inserts `data` into `table` using one statement with `RETURNING` clause of columns
+// `returning`, and returns the result of inserting along with the returning result, which is nil if
+// the driver does not support `RETURNING` clause.
+func (tx *TXCore) doInsertReturning(
+	table string, data interface{}, returning []string,
+) (result sql.Result, returningResult ReturningResult, err error) {
+	// All records are inserted using one statement, as only the records returned by
+	// the last statement are kept in the result of batch inserting.
+	var batch = 1
+	if reflectValue := reflect.Indirect(reflect.ValueOf(data)); (reflectValue.Kind() == reflect.Slice ||
+		reflectValue.Kind() == reflect.Array) && reflectValue.Len() > 0 {
+		batch = reflectValue.Len()
+	}
+	return tx.doWithReturning(returning, func(ctx context.Context) (sql.Result, error) {
+		return tx.Model(table).Ctx(ctx).Data(data).Batch(batch).Insert()
+	})
+}
+
+// doWithReturning calls `f` with the context carrying the returning columns `returning`, and returns
+// the result of `f` along with the returning result, which is nil if the driver does not support
+// `RETURNING` clause.
+func (tx *TXCore) doWithReturning(
+	returning []string, f func(ctx context.Context) (sql.Result, error),
+) (result sql.Result, returningResult ReturningResult, err error) {
+	// The returning columns are passed to driver using the context of the model in `f` only.
+	// As the model also sets the context of the transaction, it restores the context even if
+	// `f` panics, so that the following statements of the transaction are not affected.
+	var txCtx = tx.ctx
+	defer func() {
+		tx.ctx = txCtx
+	}()
+	result, err = f(tx.db.GetCore().InjectReturning(txCtx, returning))
+	if err != nil {
+		return nil, nil, err
+	}
+	if sqlResult, ok := result.(*SqlResult); ok && sqlResult.Result != nil {
+		result = sqlResult.Result
+	}
+	returningResult, _ = result.(ReturningResult)
+	return result, returningResult, nil
+}
+
+// isLockingReadSupported checks and returns whether the database supports "SELECT ... FOR UPDATE".
+// Note that SQLite does not support it, as its writing transactions are serialized by database lock.
+func (tx *TXCore) isLockingReadSupported() bool {
+	switch gstr.ToLower(tx.db.GetConfig().Type) {
+	case "sqlite":
+		return false
+	}
+	return true
+}
+
+// isReturningSupported checks and returns whether the database supports `RETURNING` clause
+// for inserting and updating statements, which passes the returning columns to driver using context.
+func (tx *TXCore) isReturningSupported() bool {
+	switch gstr.ToLower(tx.db.GetConfig().Type) {
+	case "pgsql":
+		return true
+	}
+	return false
 }
 
 // Replace does "REPLACE INTO ..." statement for the table.
@@ -461,10 +1827,7 @@ func (tx *TXCore) InsertAndGetId(table string, data interface{}, batch ...int) (
 // If given data is type of slice, it then does batch replacing, and the optional parameter
 // `batch` specifies the batch operation count.
 func (tx *TXCore) Replace(table string, data interface{}, batch ...int) (sql.Result, error) {
-	if len(batch) > 0 {
-		return tx.Model(table).Ctx(tx.ctx).Data(data).Batch(batch[0]).Replace()
-	}
-	return tx.Model(table).Ctx(tx.ctx).Data(data).Replace()
+	return tx.Model(table).Data(data).Batch(tx.getBatch(batch)).Replace()
 }
 
 // Save does "INSERT INTO ... ON DUPLICATE KEY UPDATE..." statement for the table.
@@ -479,10 +1842,7 @@ func (tx *TXCore) Replace(table string, data interface{}, batch ...int) (sql.Res
 // If given data is type of slice, it then does batch saving, and the optional parameter
 // `batch` specifies the batch operation count.
 func (tx *TXCore) Save(table string, data interface{}, batch ...int) (sql.Result, error) {
-	if len(batch) > 0 {
-		return tx.Model(table).Ctx(tx.ctx).Data(data).Batch(batch[0]).Save()
-	}
-	return tx.Model(table).Ctx(tx.ctx).Data(data).Save()
+	return tx.Model(table).Data(data).Batch(tx.getBatch(batch)).Save()
 }
 
 // Update does "UPDATE ... " statement for the table.
@@ -500,10 +1860,84 @@ func (tx *TXCore) Save(table string, data interface{}, batch ...int) (sql.Result
 // "age IN(?,?)", 18, 50
 // User{ Id : 1, UserName : "john"}.
 func (tx *TXCore) Update(table string, data interface{}, condition interface{}, args ...interface{}) (sql.Result, error) {
-	return tx.Model(table).Ctx(tx.ctx).Data(data).Where(condition, args...).Update()
+	return tx.Model(table).Data(data).Where(condition, args...).Update()
+}
+
+// Increment atomically increments the `column` of the record matching `condition` by `by`
+// and returns the value after incrementing, which is commonly used for counters and sequences
+// stored in table rows.
+//
+// It does "UPDATE ... SET column=column+by WHERE ... RETURNING column" if the database supports
+// `RETURNING` clause, or else it locks the matching record using "SELECT ... FOR UPDATE" to retrieve
+// its primary key, and then increments and retrieves the column of the record by the primary key,
+// which is exact even if the condition uses the incremented column. As the record is locked by the
+// transaction until it ends, there's no read-modify-write race with other transactions.
+//
+// The parameter `condition` should match only one record, it returns an error if no record
+// matches the condition.
+func (tx *TXCore) Increment(
+	table, column string, by int64, condition interface{}, args ...interface{},
+) (newValue int64, err error) {
+	if tx.isReturningSupported() {
+		_, returningResult, err := tx.doWithReturning([]string{column}, func(ctx context.Context) (sql.Result, error) {
+			return tx.Model(table).Ctx(ctx).Where(condition, args...).Increment(column, by)
+		})
+		if err != nil {
+			return 0, err
+		}
+		if returningResult == nil {
+			return 0, gerror.NewCodef(
+				gcode.CodeNotSupported,
+				`RETURNING clause is not supported by driver of database type "%s"`, tx.db.GetConfig().Type,
+			)
+		}
+		records := returningResult.Returning()
+		if len(records) == 0 {
+			return 0, newIncrementNoRecordError(table, column)
+		}
+		return records[0][column].Int64(), nil
+	}
+	primaryKey := tx.Model(table).getPrimaryKey()
+	if primaryKey == "" {
+		return 0, gerror.NewCodef(
+			gcode.CodeNotSupported,
+			`table "%s" has no primary key for incrementing column "%s"`, table, column,
+		)
+	}
+	var model = tx.Model(table).Where(condition, args...)
+	if tx.isLockingReadSupported() {
+		model = model.LockUpdate()
+	}
+	primaryValue, err := model.Value(primaryKey)
+	if err != nil {
+		return 0, err
+	}
+	if primaryValue.IsNil() {
+		return 0, newIncrementNoRecordError(table, column)
+	}
+	if _, err = tx.Model(table).Where(primaryKey, primaryValue.Val()).Increment(column, by); err != nil {
+		return 0, err
+	}
+	value, err := tx.Model(table).Where(primaryKey, primaryValue.Val()).Value(column)
+	if err != nil {
+		return 0, err
+	}
+	return value.Int64(), nil
+}
+
+// newIncrementNoRecordError creates and returns the error that no record matches the condition
+// for incrementing `column` of `table`.
+func newIncrementNoRecordError(table, column string) error {
+	return gerror.NewCodef(
+		gcode.CodeInvalidOperation,
+		`no record of table "%s" matches the condition for incrementing column "%s"`,
+		table, column,
+	)
 }
 
 // Delete does "DELETE FROM ... " statement for the table.
+// Like Model.Delete, it does soft deleting that updates the deleting time field instead if the table
+// has one, eg: "deleted_at", which is rolled back along with the transaction. Use DeleteForce for real deleting.
 //
 // The parameter `condition` can be type of string/map/gmap/slice/struct/*struct, etc.
 // It is commonly used with parameter `args`.
@@ -515,22 +1949,31 @@ func (tx *TXCore) Update(table string, data interface{}, condition interface{},
 // "age IN(?,?)", 18, 50
 // User{ Id : 1, UserName : "john"}.
 func (tx *TXCore) Delete(table string, condition interface{}, args ...interface{}) (sql.Result, error) {
-	return tx.Model(table).Ctx(tx.ctx).Where(condition, args...).Delete()
+	return tx.Model(table).Where(condition, args...).Delete()
+}
+
+// DeleteForce does "DELETE FROM ... " statement for the table, which really deletes the records
+// even if the table has soft deleting field. See Delete for parameter `condition`.
+func (tx *TXCore) DeleteForce(table string, condition interface{}, args ...interface{}) (sql.Result, error) {
+	return tx.Model(table).Unscoped().Where(condition, args...).Delete()
 }
 
 // QueryContext implements interface function Link.QueryContext.
 func (tx *TXCore) QueryContext(ctx context.Context, sql string, args ...interface{}) (*sql.Rows, error) {
-	return tx.tx.QueryContext(ctx, sql, args...)
+	return tx.rawLink().QueryContext(ctx, sql, args...)
 }
 
 // ExecContext implements interface function Link.ExecContext.
 func (tx *TXCore) ExecContext(ctx context.Context, sql string, args ...interface{}) (sql.Result, error) {
-	return tx.tx.ExecContext(ctx, sql, args...)
+	if tx.IsReadOnly() {
+		return nil, newTxReadOnlyError()
+	}
+	return tx.rawLink().ExecContext(ctx, sql, args...)
 }
 
 // PrepareContext implements interface function Link.PrepareContext.
 func (tx *TXCore) PrepareContext(ctx context.Context, sql string) (*sql.Stmt, error) {
-	return tx.tx.PrepareContext(ctx, sql)
+	return tx.rawLink().PrepareContext(ctx, sql)
 }
 
 // IsOnMaster implements interface function Link.IsOnMaster.
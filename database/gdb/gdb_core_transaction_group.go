@@ -0,0 +1,146 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/internal/intlog"
+)
+
+// TXGroup is the coordinator of transactions on multiple database groups,
+// which commits the transactions as one logical unit using best-effort two-phase commit
+// based on XA transaction. It is commonly used for writing to sharded databases.
+//
+// Note that the two-phase commit is not atomic: if committing any prepared transaction fails
+// in the second phase, the transactions already committed cannot be rolled back, and the
+// failed ones remain prepared on their database servers, which should be recovered manually
+// using `XA RECOVER` and `XA COMMIT`/`XA ROLLBACK` with their xids in the returned error.
+type TXGroup struct {
+	dbs []DB // dbs is the databases of different groups that transactions are begun on.
+}
+
+// NewTXGroup creates and returns a transaction coordinator of given databases.
+// The given databases should be of different groups and support XA transaction.
+func NewTXGroup(dbs ...DB) *TXGroup {
+	return &TXGroup{
+		dbs: dbs,
+	}
+}
+
+// Transaction begins XA transactions on all databases of the group, and wraps the transaction logic
+// using function `f`, in which `ctx` carries all the transactions, so that operations of any database
+// of the group using `ctx` are automatically performed on its transaction. The parameter `txs` of `f`
+// is the map of database group name to its transaction object.
+//
+// It rollbacks all the transactions and returns the error from function `f` if it returns non-nil error.
+// Or else it prepares all the transactions, and commits all of them if all prepares succeed, or
+// rollbacks all of them if any prepare fails.
+//
+// Note that, you should not commit or rollback the transactions in function `f`
+// as it is automatically handled by this function.
+func (g *TXGroup) Transaction(ctx context.Context, f func(ctx context.Context, txs map[string]TX) error) (err error) {
+	if len(g.dbs) == 0 {
+		return gerror.NewCode(gcode.CodeInvalidParameter, `no database given for transaction group`)
+	}
+	var groups = make(map[string]struct{}, len(g.dbs))
+	for _, db := range g.dbs {
+		group := db.GetGroup()
+		if _, ok := groups[group]; ok {
+			return gerror.NewCodef(
+				gcode.CodeInvalidParameter, `duplicated database group "%s" for transaction group`, group,
+			)
+		}
+		groups[group] = struct{}{}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var (
+		txs       = make(map[string]TX, len(g.dbs))
+		txSlice   = make([]TX, 0, len(g.dbs))
+		xids      = make([]string, 0, len(g.dbs))
+		xidPrefix = newTransactionId()
+	)
+	for i, db := range g.dbs {
+		xid := fmt.Sprintf(`%s-%d`, xidPrefix, i)
+		tx, beginErr := db.BeginXA(ctx, xid)
+		if beginErr != nil {
+			g.rollbackAll(txSlice)
+			return beginErr
+		}
+		txs[db.GetGroup()] = tx
+		txSlice = append(txSlice, tx)
+		xids = append(xids, xid)
+	}
+	defer func() {
+		if err == nil {
+			if exception := recover(); exception != nil {
+				err = newTXPanicError(exception)
+			}
+		}
+		if err != nil {
+			g.rollbackAll(txSlice)
+			return
+		}
+		err = g.commitAll(txSlice, xids)
+	}()
+	err = f(WithTXs(ctx, txSlice...), txs)
+	return
+}
+
+// commitAll performs the two-phase commit of given XA transactions.
+// It returns the error of the first failed commit, which also contains the xids of all the
+// transactions that failed committing in the second phase.
+func (g *TXGroup) commitAll(txs []TX, xids []string) error {
+	// The first phase: prepare all.
+	for _, tx := range txs {
+		err := tx.EndXA()
+		if err == nil {
+			err = tx.PrepareXA()
+		}
+		if err != nil {
+			g.rollbackAll(txs)
+			return err
+		}
+	}
+	// The second phase: commit all.
+	var (
+		firstErr   error
+		failedXids []string
+	)
+	for i, tx := range txs {
+		if err := tx.CommitXA(false); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failedXids = append(failedXids, xids[i])
+		}
+	}
+	if firstErr != nil {
+		return gerror.WrapCodef(
+			gcode.CodeInternalError, firstErr,
+			`commit prepared XA transactions %v failed, which should be recovered manually`, failedXids,
+		)
+	}
+	return nil
+}
+
+// rollbackAll rollbacks given XA transactions that are not closed, ignoring the errors.
+func (g *TXGroup) rollbackAll(txs []TX) {
+	for _, tx := range txs {
+		if tx.IsClosed() {
+			continue
+		}
+		if err := tx.RollbackXA(); err != nil {
+			intlog.Errorf(tx.GetCtx(), `rollback XA transaction failed: %+v`, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// TXPanicError is the error for the panic recovered from the function of transaction,
+// which keeps the recovered value. It can be retrieved from the returned error using errors.As, eg:
+//
+//	var panicErr *gdb.TXPanicError
+//	if errors.As(err, &panicErr) {
+//		fmt.Println(panicErr.Value)
+//	}
+type TXPanicError struct {
+	Value interface{} // Value is the value recovered from the panic.
+}
+
+// Error implements the interface error.
+func (e *TXPanicError) Error() string {
+	if v, ok := e.Value.(error); ok {
+		return v.Error()
+	}
+	return fmt.Sprintf(`%+v`, e.Value)
+}
+
+// Unwrap returns the recovered value if it is an error, or else it returns nil.
+func (e *TXPanicError) Unwrap() error {
+	if v, ok := e.Value.(error); ok {
+		return v
+	}
+	return nil
+}
+
+// Code returns the code of the recovered value if it is an error with stack,
+// or else it returns gcode.CodeInternalPanic.
+func (e *TXPanicError) Code() gcode.Code {
+	if v, ok := e.Value.(error); ok && gerror.HasStack(v) {
+		return gerror.Code(v)
+	}
+	return gcode.CodeInternalPanic
+}
+
+// newTXPanicError creates and returns the error with stack for value `exception` recovered from the panic
+// of transaction function, whose code and text are the ones of TXPanicError.
+func newTXPanicError(exception interface{}) error {
+	return gerror.NewWithOption(gerror.Option{
+		Error: &TXPanicError{Value: exception},
+		Stack: true,
+		Code:  gcode.CodeNil,
+	})
+}
+
+// txJoinError is the error joining the error that causes the rollback of transaction and the error
+// of the rollback itself, so that neither of them is lost.
+type txJoinError struct {
+	errs []error
+}
+
+// joinRollbackError joins and returns the error `err` causing the rollback and the error `rollbackErr`
+// of the rollback itself.
+func joinRollbackError(err, rollbackErr error) error {
+	if err == nil {
+		return rollbackErr
+	}
+	return &txJoinError{errs: []error{err, rollbackErr}}
+}
+
+// Error implements the interface error.
+func (e *txJoinError) Error() string {
+	var texts = make([]string, len(e.errs))
+	for i, err := range e.errs {
+		texts[i] = err.Error()
+	}
+	return strings.Join(texts, "; rollback failed: ")
+}
+
+// Code returns the error code of the error causing the rollback.
+func (e *txJoinError) Code() gcode.Code {
+	return gerror.Code(e.errs[0])
+}
+
+// Is reports whether any of the joined errors matches `target`.
+func (e *txJoinError) Is(target error) bool {
+	for _, err := range e.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the joined errors that matches `target`.
+func (e *txJoinError) As(target interface{}) bool {
+	for _, err := range e.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
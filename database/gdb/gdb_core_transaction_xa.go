@@ -0,0 +1,168 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+// BeginXA starts and returns the XA transaction object of given transaction id `xid`,
+// which performs `XA START xid` on a dedicated connection of master node.
+//
+// The XA transaction should be finished by EndXA, PrepareXA and CommitXA for two-phase commit,
+// or by EndXA and CommitXA with `onePhase` true, or by RollbackXA. Note that Commit is not
+// available for XA transaction, and Rollback performs RollbackXA.
+//
+// The parameter `xid` is the global transaction id, which is quoted as string literal in statements.
+// It is commonly used for coordinating writes across multiple MySQL databases.
+func (c *Core) BeginXA(ctx context.Context, xid string) (tx TX, err error) {
+	if xid == "" {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, `xid should not be empty for XA transaction`)
+	}
+	master, err := c.db.Master()
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.db.DoCommit(ctx, DoCommitInput{
+		Db:            master,
+		Sql:           fmt.Sprintf(`XA START %s`, quoteXid(xid)),
+		Type:          SqlTypeBeginXA,
+		IsTransaction: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if txCore, ok := out.Tx.(*TXCore); ok {
+		txCore.xid = xid
+	}
+	return out.Tx, nil
+}
+
+// quoteXid quotes and returns the XA transaction id `xid` as string literal for XA statements,
+// which escapes the quote and backslash characters to prevent SQL injection.
+func quoteXid(xid string) string {
+	return `'` + gstr.ReplaceByMap(xid, map[string]string{
+		`\`: `\\`,
+		`'`: `''`,
+	}) + `'`
+}
+
+// IsXA checks and returns whether current transaction is an XA transaction begun by BeginXA.
+func (tx *TXCore) IsXA() bool {
+	return tx.xid != ""
+}
+
+// EndXA performs `XA END xid` statement, which ends the work of current XA transaction.
+// It should be called before PrepareXA or CommitXA with `onePhase` true.
+func (tx *TXCore) EndXA() error {
+	if err := tx.checkXA(); err != nil {
+		return err
+	}
+	if err := tx.doXACommit(SqlTypeTXXAEnd, `XA END`); err != nil {
+		return err
+	}
+	tx.xaEnded = true
+	return nil
+}
+
+// PrepareXA performs `XA PREPARE xid` statement, which is the first phase of two-phase commit.
+func (tx *TXCore) PrepareXA() error {
+	if err := tx.checkXA(); err != nil {
+		return err
+	}
+	return tx.doXACommit(SqlTypeTXXAPrepare, `XA PREPARE`)
+}
+
+// CommitXA performs `XA COMMIT xid` statement, which is the second phase of two-phase commit,
+// and releases the dedicated connection of current XA transaction if it succeeds.
+// The parameter `onePhase` specifies committing the ended but not prepared XA transaction
+// in one phase using `XA COMMIT xid ONE PHASE`.
+func (tx *TXCore) CommitXA(onePhase bool) (err error) {
+	if err = tx.checkXA(); err != nil {
+		return err
+	}
+	defer tx.runOutcomeHooks(tx.onCommittedFuncs, &err)
+	if onePhase {
+		err = tx.doXACommit(SqlTypeTXXACommit, `XA COMMIT`, `ONE PHASE`)
+	} else {
+		err = tx.doXACommit(SqlTypeTXXACommit, `XA COMMIT`)
+	}
+	tx.identityMap.Clear()
+	tx.closeStmts()
+	if err == nil {
+		tx.closeXA()
+		tx.runCallbacks(tx.onCommitFuncs)
+	}
+	tx.recordMetrics(err == nil, err)
+	return err
+}
+
+// RollbackXA performs `XA ROLLBACK xid` statement, which aborts current XA transaction,
+// and releases the dedicated connection of current XA transaction if it succeeds.
+// It performs `XA END xid` automatically if the XA transaction is not ended yet.
+func (tx *TXCore) RollbackXA() (err error) {
+	if err = tx.checkXA(); err != nil {
+		return err
+	}
+	defer tx.runOutcomeHooks(tx.onRolledBackFuncs, &err)
+	if !tx.xaEnded {
+		if err = tx.doXACommit(SqlTypeTXXAEnd, `XA END`); err != nil {
+			return err
+		}
+		tx.xaEnded = true
+	}
+	err = tx.doXACommit(SqlTypeTXXARollback, `XA ROLLBACK`)
+	tx.identityMap.Clear()
+	tx.closeStmts()
+	if err == nil {
+		tx.closeXA()
+		tx.runCallbacks(tx.onRollbackFuncs)
+	}
+	tx.recordMetrics(false, err)
+	return err
+}
+
+// checkXA checks whether current transaction is an XA transaction that is not closed.
+func (tx *TXCore) checkXA() error {
+	if !tx.IsXA() {
+		return gerror.NewCode(gcode.CodeInvalidOperation, `current transaction is not an XA transaction`)
+	}
+	if tx.isClosed {
+		return gerror.NewCode(gcode.CodeInvalidOperation, `XA transaction has already been committed or rolled back`)
+	}
+	return nil
+}
+
+// doXACommit commits the XA statement of given `sqlType` to underlying driver,
+// in which the quoted xid of current transaction is placed after `command`.
+func (tx *TXCore) doXACommit(sqlType SqlType, command string, suffix ...string) error {
+	var xaSql = fmt.Sprintf(`%s %s`, command, quoteXid(tx.xid))
+	if len(suffix) > 0 {
+		xaSql += " " + gstr.Join(suffix, " ")
+	}
+	_, err := tx.db.DoCommit(tx.ctx, DoCommitInput{
+		Link:          newTxLink(tx),
+		Sql:           xaSql,
+		Type:          sqlType,
+		IsTransaction: true,
+	})
+	return err
+}
+
+// closeXA marks current XA transaction closed and returns its dedicated connection to the pool.
+func (tx *TXCore) closeXA() {
+	tx.isClosed = true
+	tx.savePoints = nil
+	if tx.conn != nil {
+		_ = tx.conn.Close()
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
@@ -19,12 +20,12 @@ import (
 	"github.com/gogf/gf/v2/util/gconv"
 
 	"github.com/gogf/gf/v2"
+	"github.com/gogf/gf/v2/container/gmap"
 	"github.com/gogf/gf/v2/container/gvar"
 	"github.com/gogf/gf/v2/errors/gcode"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/internal/intlog"
 	"github.com/gogf/gf/v2/os/gtime"
-	"github.com/gogf/gf/v2/util/guid"
 )
 
 // Query commits one query SQL to underlying driver and returns the execution result.
@@ -40,7 +41,7 @@ func (c *Core) DoQuery(ctx context.Context, link Link, sql string, args ...inter
 	if link == nil {
 		if tx := TXFromCtx(ctx, c.db.GetGroup()); tx != nil {
 			// Firstly, check and retrieve transaction link from context.
-			link = &txLink{tx.GetSqlTX()}
+			link = newTxLink(tx)
 		} else if link, err = c.SlaveLink(); err != nil {
 			// Or else it creates one from master node.
 			return nil, err
@@ -48,7 +49,7 @@ func (c *Core) DoQuery(ctx context.Context, link Link, sql string, args ...inter
 	} else if !link.IsTransaction() {
 		// If current link is not transaction link, it checks and retrieves transaction from context.
 		if tx := TXFromCtx(ctx, c.db.GetGroup()); tx != nil {
-			link = &txLink{tx.GetSqlTX()}
+			link = newTxLink(tx)
 		}
 	}
 
@@ -73,6 +74,10 @@ func (c *Core) DoQuery(ctx context.Context, link Link, sql string, args ...inter
 			return nil, nil
 		}
 	}
+	// Transaction statement counting.
+	if l, ok := link.(*txLink); ok && l.core != nil {
+		l.core.statementCount.Add(1)
+	}
 	// Link execution.
 	var out DoCommitOutput
 	out, err = c.db.DoCommit(ctx, DoCommitInput{
@@ -99,7 +104,7 @@ func (c *Core) DoExec(ctx context.Context, link Link, sql string, args ...interf
 	if link == nil {
 		if tx := TXFromCtx(ctx, c.db.GetGroup()); tx != nil {
 			// Firstly, check and retrieve transaction link from context.
-			link = &txLink{tx.GetSqlTX()}
+			link = newTxLink(tx)
 		} else if link, err = c.MasterLink(); err != nil {
 			// Or else it creates one from master node.
 			return nil, err
@@ -107,7 +112,7 @@ func (c *Core) DoExec(ctx context.Context, link Link, sql string, args ...interf
 	} else if !link.IsTransaction() {
 		// If current link is not transaction link, it checks and retrieves transaction from context.
 		if tx := TXFromCtx(ctx, c.db.GetGroup()); tx != nil {
-			link = &txLink{tx.GetSqlTX()}
+			link = newTxLink(tx)
 		}
 	}
 
@@ -134,6 +139,10 @@ func (c *Core) DoExec(ctx context.Context, link Link, sql string, args ...interf
 			return new(SqlResult), nil
 		}
 	}
+	// Transaction statement counting.
+	if l, ok := link.(*txLink); ok && l.core != nil {
+		l.core.statementCount.Add(1)
+	}
 	// Link execution.
 	var out DoCommitOutput
 	out, err = c.db.DoCommit(ctx, DoCommitInput{
@@ -144,6 +153,12 @@ func (c *Core) DoExec(ctx context.Context, link Link, sql string, args ...interf
 		Type:          SqlTypeExecContext,
 		IsTransaction: link.IsTransaction(),
 	})
+	// Transaction affected rows counting.
+	if l, ok := link.(*txLink); ok && l.core != nil && err == nil && out.Result != nil {
+		if rowsAffected, e := out.Result.RowsAffected(); e == nil {
+			l.core.affectedRows.Add(rowsAffected)
+		}
+	}
 	return out.Result, err
 }
 
@@ -156,6 +171,18 @@ func (c *Core) DoFilter(ctx context.Context, link Link, sql string, args []inter
 
 // DoCommit commits current sql and arguments to underlying sql driver.
 func (c *Core) DoCommit(ctx context.Context, in DoCommitInput) (out DoCommitOutput, err error) {
+	// The transaction id is generated before beginning, so that the SQL hooks can see it.
+	var beginTransactionId string
+	if in.Type == SqlTypeBegin || in.Type == SqlTypeBeginXA {
+		beginTransactionId = newTransactionId()
+	}
+	// SQL hooks, which might modify the sql and its arguments or short-circuit the execution.
+	if len(c.sqlHooks) > 0 {
+		if in, err = c.runSqlHooks(ctx, in, beginTransactionId); err != nil {
+			return out, err
+		}
+	}
+
 	var (
 		sqlTx                *sql.Tx
 		sqlStmt              *sql.Stmt
@@ -169,6 +196,11 @@ func (c *Core) DoCommit(ctx context.Context, in DoCommitInput) (out DoCommitOutp
 		timestampMilli1      = gtime.TimestampMilli()
 	)
 
+	// Transaction id with nesting level for logging and tracing.
+	if l, ok := in.Link.(*txLink); ok && l.core != nil {
+		ctx = context.WithValue(ctx, GetTransactionIdContextKey(), l.core.loggerTransactionId())
+	}
+
 	// Trace span start.
 	tr := otel.GetTracerProvider().Tracer(traceInstrumentName, trace.WithInstrumentationVersion(gf.VERSION))
 	ctx, span := tr.Start(ctx, string(in.Type), trace.WithSpanKind(trace.SpanKindInternal))
@@ -177,24 +209,42 @@ func (c *Core) DoCommit(ctx context.Context, in DoCommitInput) (out DoCommitOutp
 	// Execution cased by type.
 	switch in.Type {
 	case SqlTypeBegin:
-		if sqlTx, err = in.Db.Begin(); err == nil {
-			out.Tx = &TXCore{
-				db:            c.db,
-				tx:            sqlTx,
-				ctx:           context.WithValue(ctx, transactionIdForLoggerCtx, transactionIdGenerator.Add(1)),
-				master:        in.Db,
-				transactionId: guid.S(),
-			}
-			ctx = out.Tx.GetCtx()
+		// The context is used for cancellation of the transaction in underlying driver,
+		// which rollbacks the transaction if the context is cancelled.
+		sqlTx, err = in.Db.BeginTx(ctx, in.TxOptions)
+		if err == nil {
+			var txCore = c.newTXCore(ctx, in, beginTransactionId)
+			txCore.tx = sqlTx
+			out.Tx = txCore
+			ctx = context.WithValue(txCore.ctx, GetTransactionIdContextKey(), txCore.loggerTransactionId())
 		}
 		out.RawResult = sqlTx
 
+	case SqlTypeBeginXA:
+		// XA transaction uses a dedicated connection instead of local transaction,
+		// as `XA START` cannot be performed in an active local transaction.
+		var sqlConn *sql.Conn
+		if sqlConn, err = in.Db.Conn(ctx); err == nil {
+			if _, err = sqlConn.ExecContext(ctx, in.Sql); err != nil {
+				_ = sqlConn.Close()
+			} else {
+				var txCore = c.newTXCore(ctx, in, beginTransactionId)
+				txCore.conn = sqlConn
+				out.Tx = txCore
+				ctx = context.WithValue(txCore.ctx, GetTransactionIdContextKey(), txCore.loggerTransactionId())
+			}
+		}
+		out.RawResult = sqlConn
+
 	case SqlTypeTXCommit:
 		err = in.Tx.Commit()
 
 	case SqlTypeTXRollback:
 		err = in.Tx.Rollback()
 
+	case SqlTypeTXXAEnd, SqlTypeTXXAPrepare, SqlTypeTXXACommit, SqlTypeTXXARollback:
+		_, err = in.Link.ExecContext(ctx, in.Sql)
+
 	case SqlTypeExecContext:
 		if c.db.GetDryRun() {
 			sqlResult = new(SqlResult)
@@ -243,7 +293,15 @@ func (c *Core) DoCommit(ctx context.Context, in DoCommitInput) (out DoCommitOutp
 		out.Result = sqlResult
 
 	case sqlRows != nil:
+		var columns []string
+		if c.interceptor != nil {
+			// Column names should be retrieved before the rows are closed.
+			columns, _ = sqlRows.Columns()
+		}
 		out.Records, err = c.RowsToResult(ctx, sqlRows)
+		if err == nil && c.interceptor != nil {
+			out.Records = c.interceptor(ctx, columns, out.Records)
+		}
 		rowsAffected = int64(len(out.Records))
 
 	case sqlStmt != nil:
@@ -270,14 +328,26 @@ func (c *Core) DoCommit(ctx context.Context, in DoCommitInput) (out DoCommitOutp
 			IsTransaction: in.IsTransaction,
 		}
 	)
+	// Statement count of the transaction, which is logged at COMMIT.
+	if in.Type == SqlTypeTXCommit || in.Type == SqlTypeTXXACommit {
+		if l, ok := in.Link.(*txLink); ok && l.core != nil {
+			sqlObj.StatementCount = l.core.StatementCount()
+		}
+	}
 
 	// Tracing.
 	c.traceSpanEnd(ctx, span, sqlObj)
 
 	// Logging.
-	if c.db.GetDebug() {
+	if c.isSqlLoggingEnabled(in.Link) {
 		c.writeSqlToLogger(ctx, sqlObj)
 	}
+	if c.isSlowSql(sqlObj) {
+		c.writeSlowSqlToLogger(ctx, sqlObj)
+	}
+	if err != nil && in.IsTransaction {
+		err = c.wrapDeadlockError(ctx, err)
+	}
 	if err != nil && err != sql.ErrNoRows {
 		err = gerror.WrapCode(
 			gcode.CodeDbOperationError,
@@ -288,6 +358,66 @@ func (c *Core) DoCommit(ctx context.Context, in DoCommitInput) (out DoCommitOutp
 	return out, err
 }
 
+// runSqlHooks calls the SQL hooks in registration order with the statement of `in`,
+// and returns `in` with the sql and arguments modified by the hooks.
+// The parameter `beginTransactionId` is the id of the transaction to begin if `in` is of BEGIN.
+func (c *Core) runSqlHooks(ctx context.Context, in DoCommitInput, beginTransactionId string) (DoCommitInput, error) {
+	var hookInput = &SqlHookInput{
+		Sql: &Sql{
+			Sql:           in.Sql,
+			Type:          in.Type,
+			Args:          in.Args,
+			Group:         c.db.GetGroup(),
+			Schema:        c.db.GetSchema(),
+			IsTransaction: in.IsTransaction,
+		},
+		Link:          in.Link,
+		TransactionId: beginTransactionId,
+	}
+	if l, ok := in.Link.(*txLink); ok && l.core != nil {
+		hookInput.TransactionId = l.core.transactionId
+	}
+	for _, hook := range c.sqlHooks {
+		hookInput.Sql.Format = FormatSqlWithArgs(hookInput.Sql.Sql, hookInput.Sql.Args)
+		if err := hook(ctx, hookInput); err != nil {
+			return in, err
+		}
+	}
+	in.Sql, in.Args = hookInput.Sql.Sql, hookInput.Sql.Args
+	return in, nil
+}
+
+// newTXCore creates and returns the transaction object with id `transactionId`
+// for transaction beginning input `in`.
+func (c *Core) newTXCore(ctx context.Context, in DoCommitInput, transactionId string) *TXCore {
+	return &TXCore{
+		db:            c.db,
+		ctx:           context.WithValue(ctx, GetTransactionIdContextKey(), transactionId),
+		master:        in.Db,
+		transactionId: transactionId,
+		options:       in.TxOptions,
+		startTime:     time.Now(),
+		identityMap:   gmap.NewStrAnyMap(true),
+		checkpoints:   gmap.NewStrAnyMap(true),
+		stmts:         gmap.NewStrAnyMap(true),
+	}
+}
+
+// isSqlLoggingEnabled checks and returns whether the sql committed through `link` should be written to logger,
+// in which the debug setting of the transaction takes precedence over the one of db.
+func (c *Core) isSqlLoggingEnabled(link Link) bool {
+	if l, ok := link.(*txLink); ok && l.core != nil && l.core.debug != nil {
+		return l.core.debug.Val()
+	}
+	return c.db.GetDebug()
+}
+
+// isSlowSql checks and returns whether the duration of `sql` exceeds the configured SlowLogThreshold.
+func (c *Core) isSlowSql(sql *Sql) bool {
+	var threshold = c.db.GetConfig().SlowLogThreshold
+	return threshold > 0 && time.Duration(sql.End-sql.Start)*time.Millisecond > threshold
+}
+
 // Prepare creates a prepared statement for later queries or executions.
 // Multiple queries or executions may be run concurrently from the
 // returned statement.
@@ -319,7 +449,7 @@ func (c *Core) DoPrepare(ctx context.Context, link Link, sql string) (stmt *Stmt
 	if link == nil {
 		if tx := TXFromCtx(ctx, c.db.GetGroup()); tx != nil {
 			// Firstly, check and retrieve transaction link from context.
-			link = &txLink{tx.GetSqlTX()}
+			link = newTxLink(tx)
 		} else {
 			// Or else it creates one from master node.
 			var err error
@@ -330,7 +460,7 @@ func (c *Core) DoPrepare(ctx context.Context, link Link, sql string) (stmt *Stmt
 	} else if !link.IsTransaction() {
 		// If current link is not transaction link, it checks and retrieves transaction from context.
 		if tx := TXFromCtx(ctx, c.db.GetGroup()); tx != nil {
-			link = &txLink{tx.GetSqlTX()}
+			link = newTxLink(tx)
 		}
 	}
 
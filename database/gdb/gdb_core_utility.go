@@ -28,7 +28,7 @@ func (c *Core) GetDB() DB {
 func (c *Core) GetLink(ctx context.Context, master bool, schema string) (Link, error) {
 	tx := TXFromCtx(ctx, c.db.GetGroup())
 	if tx != nil {
-		return &txLink{tx.GetSqlTX()}, nil
+		return newTxLink(tx), nil
 	}
 	if master {
 		link, err := c.db.GetCore().MasterLink(schema)
@@ -53,6 +53,7 @@ type Model struct {
 	onConflict     interface{}       // onConflict is used for conflict keys on Upsert clause.
 	tableAliasMap  map[string]string // Table alias to true table name, usually used in join statements.
 	softTimeOption SoftTimeOption    // SoftTimeOption is the option to customize soft time feature for Model.
+	identityMap    bool              // identityMap enables the transaction-scoped identity map for Find.
 }
 
 // ModelHandler is a function that handles given Model and returns a new Model that is custom modified.
@@ -189,10 +190,12 @@ func (m *Model) Partition(partitions ...string) *Model {
 }
 
 // Model acts like Core.Model except it operates on transaction.
+// The returned model is bound with the context of the transaction automatically,
+// which can be overridden by Model.Ctx.
 // See Core.Model.
 func (tx *TXCore) Model(tableNameQueryOrStruct ...interface{}) *Model {
 	model := tx.db.Model(tableNameQueryOrStruct...)
-	model.db = tx.db
+	model.db = tx.getCtxDb()
 	model.tx = tx
 	return model
 }
@@ -0,0 +1,93 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+)
+
+// Cursor iterates the query result of Model in batches using keyset pagination.
+// It is created by Model.Cursor.
+type Cursor struct {
+	model     *Model      // model is the base query model without keyset condition.
+	key       string      // key is the primary key name used for keyset pagination.
+	batchSize int         // batchSize is the maximum record count of each batch.
+	lastValue interface{} // lastValue is the key value of the last record in previous batch.
+	finished  bool        // finished marks that there are no more records.
+}
+
+// Cursor creates and returns a Cursor which iterates the query result in batches of `batchSize`
+// records. Different from Chunk, it uses keyset pagination on the primary key, that is
+// `WHERE key > last ORDER BY key ASC LIMIT size`, instead of OFFSET, so each batch costs
+// the same no matter how deep the iteration goes.
+//
+// If the model is created from a transaction, all batches are queried in that transaction,
+// which gives a consistent snapshot across batches on databases using MVCC.
+//
+// Note that the table must have a primary key, and the model should not have its own
+// order or limit, as they conflict with keyset pagination.
+func (m *Model) Cursor(batchSize int) (*Cursor, error) {
+	if batchSize <= 0 {
+		return nil, gerror.NewCodef(
+			gcode.CodeInvalidParameter,
+			`invalid batch size %d for cursor, it should be greater than 0`,
+			batchSize,
+		)
+	}
+	if m.orderBy != "" || m.limit > 0 {
+		return nil, gerror.NewCode(
+			gcode.CodeInvalidOperation,
+			`cursor does not support custom order or limit, as it orders by primary key`,
+		)
+	}
+	primaryKey := m.getPrimaryKey()
+	if primaryKey == "" {
+		return nil, gerror.NewCodef(
+			gcode.CodeInvalidOperation,
+			`primary key not found for table "%s", which is required by cursor`,
+			m.tablesInit,
+		)
+	}
+	return &Cursor{
+		model:     m.Clone(),
+		key:       primaryKey,
+		batchSize: batchSize,
+	}, nil
+}
+
+// Next retrieves and returns the next batch of records.
+// It returns empty result if there are no more records.
+func (c *Cursor) Next() (Result, error) {
+	if c.finished {
+		return nil, nil
+	}
+	model := c.model.Clone()
+	if c.lastValue != nil {
+		model = model.WhereGT(c.key, c.lastValue)
+	}
+	result, err := model.OrderAsc(c.key).Limit(c.batchSize).All()
+	if err != nil {
+		return nil, err
+	}
+	if len(result) < c.batchSize {
+		c.finished = true
+	}
+	if len(result) > 0 {
+		lastValue, ok := result[len(result)-1][c.key]
+		if !ok || lastValue.IsNil() {
+			c.finished = true
+			return nil, gerror.NewCodef(
+				gcode.CodeInvalidOperation,
+				`primary key "%s" is not in the selected fields, which is required by cursor`,
+				c.key,
+			)
+		}
+		c.lastValue = lastValue.Val()
+	}
+	return result, nil
+}
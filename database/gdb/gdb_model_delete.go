@@ -28,6 +28,9 @@ func (m *Model) Delete(where ...interface{}) (result sql.Result, err error) {
 			m.checkAndRemoveSelectCache(ctx)
 		}
 	}()
+	if m.tx != nil && m.tx.IsReadOnly() {
+		return nil, newTxReadOnlyError()
+	}
 	var (
 		conditionWhere, conditionExtra, conditionArgs = m.formatCondition(ctx, false, false)
 		conditionStr                                  = conditionWhere + conditionExtra
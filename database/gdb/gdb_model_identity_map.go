@@ -0,0 +1,59 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"fmt"
+
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// WithIdentityMap enables the transaction-scoped identity map for Find, which is keyed by
+// table and primary key. The record that was already loaded by Find in current transaction is
+// returned directly without querying the database again. The identity map is cleared when the
+// transaction is committed or rolled back.
+//
+// It only takes effect in transaction, eg: tx.Model("user").WithIdentityMap().Find(1, &user).
+//
+// Note that the identity map trades consistency for fewer queries:
+// 1. The cached record is not refreshed by later writing operations in the same transaction,
+// so do not use it for the record that is updated after it is loaded.
+// 2. The record is cached by table and primary key only, so the other conditions and fields
+// of the model take effect only when the record is loaded from database for the first time.
+func (m *Model) WithIdentityMap() *Model {
+	model := m.getModel()
+	model.identityMap = true
+	return model
+}
+
+// Find retrieves the record by primary key `id` and converts it to `pointer`,
+// which can be type of *struct/**struct.
+// It uses the transaction-scoped identity map if it is enabled using WithIdentityMap.
+//
+// Also see WherePri and Scan.
+func (m *Model) Find(id interface{}, pointer interface{}) error {
+	var txObj = m.tx
+	if txObj == nil {
+		txObj = TXFromCtx(m.GetCtx(), m.db.GetGroup())
+	}
+	tx, ok := txObj.(*TXCore)
+	if !m.identityMap || !ok {
+		return m.WherePri(id).Scan(pointer)
+	}
+	var identityKey = fmt.Sprintf(`%s:%s`, m.tablesInit, gconv.String(id))
+	if v := tx.identityMap.Get(identityKey); v != nil {
+		return v.(Record).Struct(pointer)
+	}
+	one, err := m.WherePri(id).One()
+	if err != nil {
+		return err
+	}
+	if !one.IsEmpty() {
+		tx.identityMap.Set(identityKey, one)
+	}
+	return one.Struct(pointer)
+}
@@ -255,6 +255,9 @@ func (m *Model) doInsertWithOption(ctx context.Context, insertOption InsertOptio
 			m.checkAndRemoveSelectCache(ctx)
 		}
 	}()
+	if m.tx != nil && m.tx.IsReadOnly() {
+		return nil, newTxReadOnlyError()
+	}
 	if m.data == nil {
 		return nil, gerror.NewCode(gcode.CodeMissingParameter, "inserting into table with empty data")
 	}
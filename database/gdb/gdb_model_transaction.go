@@ -26,3 +26,17 @@ func (m *Model) Transaction(ctx context.Context, f func(ctx context.Context, tx
 	}
 	return m.db.Transaction(ctx, f)
 }
+
+// TransactionWithModel wraps the transaction logic using function `f` like Transaction,
+// in which the parameter `model` is a clone of current model bound to the transaction.
+// The `model` is safe for multiple operations, so that every operation using `model`
+// in function `f` is automatically performed on the transaction.
+//
+// It reuses the transaction in `ctx` if there's one, which is the same as Core.Transaction.
+func (m *Model) TransactionWithModel(
+	ctx context.Context, f func(ctx context.Context, tx TX, model *Model) error,
+) (err error) {
+	return m.Transaction(ctx, func(ctx context.Context, tx TX) error {
+		return f(ctx, tx, m.Clone().TX(tx).Ctx(ctx).Safe())
+	})
+}
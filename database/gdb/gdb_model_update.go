@@ -41,6 +41,9 @@ func (m *Model) Update(dataAndWhere ...interface{}) (result sql.Result, err erro
 			m.checkAndRemoveSelectCache(ctx)
 		}
 	}()
+	if m.tx != nil && m.tx.IsReadOnly() {
+		return nil, newTxReadOnlyError()
+	}
 	if m.data == nil {
 		return nil, gerror.NewCode(gcode.CodeMissingParameter, "updating table with empty data")
 	}
@@ -84,6 +87,11 @@ func (m *Model) Update(dataAndWhere ...interface{}) (result sql.Result, err erro
 	if err != nil {
 		return nil, err
 	}
+	if dataMap, ok := newData.(Map); ok {
+		if newData, err = m.includeSpecifiedFieldsForUpdate(dataMap); err != nil {
+			return nil, err
+		}
+	}
 
 	if !gstr.ContainsI(conditionStr, " WHERE ") {
 		intlog.Printf(
@@ -113,6 +121,45 @@ func (m *Model) Update(dataAndWhere ...interface{}) (result sql.Result, err erro
 	return in.Next(ctx)
 }
 
+// includeSpecifiedFieldsForUpdate forcibly includes the fields specified by Model.Fields into updating
+// data `data` even if they are zero values, which are omitted by struct "omitempty" tag or OmitEmpty feature.
+// It makes it possible updating a bool to false or an int to 0 from struct, for example:
+// Model("user").Fields("active", "count").Data(user).Update().
+func (m *Model) includeSpecifiedFieldsForUpdate(data Map) (Map, error) {
+	if len(m.fields) == 0 || m.fields == "*" {
+		return data, nil
+	}
+	switch reflection.OriginTypeAndKind(m.data).OriginKind {
+	case reflect.Map, reflect.Struct:
+	default:
+		return data, nil
+	}
+	var (
+		err          error
+		charL, charR = m.db.GetChars()
+		chars        = charL + charR
+		fullData     = gconv.Map(m.data, gconv.MapOption{
+			Tags: structTagPriority,
+		})
+	)
+	fullData, err = m.db.GetCore().mappingAndFilterData(
+		m.GetCtx(), m.schema, m.tablesInit, fullData, m.filter,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range gstr.SplitAndTrim(m.fields, ",") {
+		field = gstr.Trim(field, chars)
+		if _, ok := data[field]; ok {
+			continue
+		}
+		if v, ok := fullData[field]; ok {
+			data[field] = v
+		}
+	}
+	return data, nil
+}
+
 // UpdateAndGetAffected performs update statement and returns the affected rows number.
 func (m *Model) UpdateAndGetAffected(dataAndWhere ...interface{}) (affected int64, err error) {
 	result, err := m.Update(dataAndWhere...)
@@ -19,6 +19,15 @@ type SqlResult struct {
 	Affected int64
 }
 
+// ReturningResult is the sql.Result carrying the records returned by the `RETURNING` clause of
+// the inserting or updating statement, which is implemented by the drivers supporting `RETURNING` clause.
+type ReturningResult interface {
+	sql.Result
+
+	// Returning returns the records returned by the `RETURNING` clause.
+	Returning() Result
+}
+
 // MustGetAffected returns the affected rows count, if any error occurs, it panics.
 func (r *SqlResult) MustGetAffected() int64 {
 	rows, err := r.RowsAffected()
@@ -22,9 +22,10 @@ import (
 // prepare itself on the new connection automatically.
 type Stmt struct {
 	*sql.Stmt
-	core *Core
-	link Link
-	sql  string
+	core   *Core
+	link   Link
+	sql    string
+	cached bool // cached marks the statement is cached by transaction, which is closed along with the transaction.
 }
 
 // ExecContext executes a prepared statement with the given arguments and
@@ -113,6 +114,11 @@ func (s *Stmt) QueryRow(args ...interface{}) *sql.Row {
 }
 
 // Close closes the statement.
+// It does nothing if the statement is cached by transaction, as it is reused by the transaction
+// and closed automatically when the transaction is committed or rolled back.
 func (s *Stmt) Close() error {
+	if s.cached {
+		return nil
+	}
 	return s.Stmt.Close()
 }
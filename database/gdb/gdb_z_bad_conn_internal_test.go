@@ -0,0 +1,46 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/gogf/gf/v2/container/gtype"
+	"github.com/gogf/gf/v2/os/glog"
+	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+func Test_Begin_RetryBadConn(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx           = context.Background()
+			buffer        = bytes.NewBuffer(nil)
+			beginBadConns = gtype.NewInt()
+		)
+		fakeDB, err := New(newFakeConfigNode(fakeDriverOption{BeginBadConns: beginBadConns}))
+		t.AssertNil(err)
+		fakeDB.GetLogger().(*glog.Logger).SetWriter(buffer)
+		fakeDB.GetLogger().(*glog.Logger).SetStdoutPrint(false)
+
+		// The attempts of database/sql for one Begin all fail, which are retried once.
+		beginBadConns.Set(3)
+		tx, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		t.AssertNil(tx.Rollback())
+		t.Assert(gstr.Contains(buffer.String(), "retrying once"), true)
+
+		// It gives up after retrying once.
+		beginBadConns.Set(100)
+		_, err = fakeDB.Begin(ctx)
+		t.Assert(errors.Is(err, driver.ErrBadConn), true)
+	})
+}
@@ -0,0 +1,40 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+var errFakeDeadlock = errors.New("Error 1213: Deadlock found when trying to get lock")
+
+func Test_DeadlockDetail(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		fakeDB, err := New(newFakeConfigNode(fakeDriverOption{
+			ExecError:     errFakeDeadlock,
+			DeadlockError: errFakeDeadlock,
+		}))
+		t.AssertNil(err)
+
+		err = fakeDB.Transaction(ctx, func(ctx context.Context, tx TX) error {
+			_, err := tx.Exec("UPDATE user SET name='john' WHERE id=1")
+			return err
+		})
+		t.AssertNE(err, nil)
+		t.Assert(errors.Is(err, errFakeDeadlock), true)
+		t.Assert(DeadlockDetail(err), "LATEST DETECTED DEADLOCK")
+
+		// Statement not in transaction.
+		_, err = fakeDB.Exec(ctx, "UPDATE user SET name='john' WHERE id=1")
+		t.Assert(errors.Is(err, errFakeDeadlock), true)
+		t.Assert(DeadlockDetail(err), "")
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/gogf/gf/v2/container/gmap"
+	"github.com/gogf/gf/v2/container/gtype"
+)
+
+const fakeDriverName = "gdb-fake"
+
+var (
+	// fakeDriverOptions is the registered behavior options of fake databases, keyed by database name.
+	fakeDriverOptions = gmap.NewStrAnyMap(true)
+	// fakeDriverSeq is the sequence for generating unique database names of fake databases.
+	fakeDriverSeq = gtype.NewInt()
+)
+
+// fakeDriverOption is the behavior option of the fake sql driver, in which the zero value means
+// Begin/Commit/Rollback always succeed and the executing statements succeed without doing anything.
+// All tables of the fake database have fields "id" as primary key and "name".
+type fakeDriverOption struct {
+	ExecError     error      // ExecError is the error of all the statements if given.
+	CommitError   error      // CommitError is the error of COMMIT if given.
+	DeadlockError error      // DeadlockError is the error recognized as deadlock by the ORM driver if given.
+	BeginBadConns *gtype.Int // BeginBadConns is the count of the following Begin calls failing with driver.ErrBadConn.
+}
+
+// fakeSqlDriver is the configurable sql driver for testing without database server.
+type fakeSqlDriver struct{}
+
+type fakeConn struct {
+	option *fakeDriverOption
+}
+
+type fakeTx struct {
+	option *fakeDriverOption
+}
+
+// newFakeConfigNode registers `option` and returns the configuration node of the fake database using it.
+func newFakeConfigNode(option fakeDriverOption) ConfigNode {
+	var name = fmt.Sprintf(`fake%d`, fakeDriverSeq.Add(1))
+	fakeDriverOptions.Set(name, &option)
+	return ConfigNode{Type: fakeDriverName, Name: name}
+}
+
+// getFakeDriverOption returns the registered option of fake database `name`, or the zero option if not found.
+func getFakeDriverOption(name string) *fakeDriverOption {
+	if v := fakeDriverOptions.Get(name); v != nil {
+		return v.(*fakeDriverOption)
+	}
+	return &fakeDriverOption{}
+}
+
+func (d fakeSqlDriver) Open(name string) (driver.Conn, error) {
+	return fakeConn{option: getFakeDriverOption(name)}, nil
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if c.option.ExecError != nil {
+		return nil, c.option.ExecError
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	if c.option.BeginBadConns != nil && c.option.BeginBadConns.Add(-1) >= 0 {
+		return nil, driver.ErrBadConn
+	}
+	return fakeTx{option: c.option}, nil
+}
+
+func (c fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.option.ExecError != nil {
+		return nil, c.option.ExecError
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (tx fakeTx) Commit() error {
+	return tx.option.CommitError
+}
+
+func (tx fakeTx) Rollback() error {
+	return nil
+}
+
+// fakeDriver is the ORM driver for fakeSqlDriver.
+type fakeDriver struct {
+	*Core
+}
+
+func (d *fakeDriver) New(core *Core, node *ConfigNode) (DB, error) {
+	return &fakeDriver{Core: core}, nil
+}
+
+func (d *fakeDriver) Open(config *ConfigNode) (*sql.DB, error) {
+	return sql.Open(fakeDriverName, config.Name)
+}
+
+func (d *fakeDriver) TableFields(ctx context.Context, table string, schema ...string) (map[string]*TableField, error) {
+	return map[string]*TableField{
+		"id":   {Index: 0, Name: "id", Type: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+		"name": {Index: 1, Name: "name", Type: "varchar(45)", Null: true},
+	}, nil
+}
+
+func (d *fakeDriver) IsDeadlockError(err error) bool {
+	var option = getFakeDriverOption(d.GetConfig().Name)
+	return option.DeadlockError != nil && errors.Is(err, option.DeadlockError)
+}
+
+func (d *fakeDriver) GetDeadlockDetail(ctx context.Context) (string, error) {
+	return "LATEST DETECTED DEADLOCK", nil
+}
+
+func init() {
+	sql.Register(fakeDriverName, fakeSqlDriver{})
+	if err := Register(fakeDriverName, &fakeDriver{}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func Test_TX_getBatch(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		fakeDB, err := New(newFakeConfigNode(fakeDriverOption{}))
+		t.AssertNil(err)
+		t.Assert(fakeDB.GetConfig().BatchSize, 0)
+
+		tx, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		txCore := tx.(*TXCore)
+
+		// No batching if not specified.
+		t.Assert(txCore.getBatch(nil), 0)
+		t.Assert(txCore.getBatch([]int{10}), 10)
+		t.Assert(tx.Model("user").getBatch(), 0)
+
+		// The configured batch size applies to the batch operations of transaction only,
+		// and the specified batch takes precedence over it.
+		fakeDB.GetConfig().BatchSize = 100
+		t.Assert(txCore.getBatch(nil), 100)
+		t.Assert(txCore.getBatch([]int{10}), 10)
+		t.Assert(tx.Model("user").getBatch(), 0)
+		t.Assert(fakeDB.Model("user").getBatch(), 0)
+	})
+}
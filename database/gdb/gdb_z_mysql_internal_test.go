@@ -326,3 +326,23 @@ func Test_isSubQuery(t *testing.T) {
 		t.Assert(isSubQuery("select 1"), true)
 	})
 }
+
+func Test_Func_formatCountSql(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := map[string]string{
+			"SELECT * FROM user":                           "SELECT COUNT(1) FROM user",
+			"select id, name from user where id>?":         "SELECT COUNT(1) FROM user where id>?",
+			"SELECT * FROM user WHERE id>? ORDER BY id":    "SELECT COUNT(1) FROM user WHERE id>?",
+			"SELECT COUNT(*) FROM user WHERE id>?":         "SELECT COUNT(*) FROM user WHERE id>?",
+			"SELECT DISTINCT name FROM user":               "SELECT COUNT(1) FROM (SELECT DISTINCT name FROM user) AS _count_alias",
+			"SELECT COUNT(1) FROM user GROUP BY name":      "SELECT COUNT(1) FROM (SELECT COUNT(1) FROM user GROUP BY name) AS _count_alias",
+			"SELECT * FROM user LIMIT 10":                  "SELECT COUNT(1) FROM (SELECT * FROM user LIMIT 10) AS _count_alias",
+			"SELECT id FROM a UNION SELECT id FROM b":      "SELECT COUNT(1) FROM (SELECT id FROM a UNION SELECT id FROM b) AS _count_alias",
+			"SELECT * FROM user WHERE id IN (SELECT 1)":    "SELECT COUNT(1) FROM (SELECT * FROM user WHERE id IN (SELECT 1)) AS _count_alias",
+			"SELECT selected_at FROM user WHERE deleted=0": "SELECT COUNT(1) FROM user WHERE deleted=0",
+		}
+		for k, v := range array {
+			t.Assert(formatCountSql(k), v)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func Test_Core_RegisterHook(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx       = context.Background()
+			errDenied = errors.New("denied")
+			hooked    []*SqlHookInput
+		)
+		fakeDB, err := New(newFakeConfigNode(fakeDriverOption{}))
+		t.AssertNil(err)
+		fakeDB.RegisterHook(func(ctx context.Context, in *SqlHookInput) error {
+			if in.Sql.Type == SqlTypeExecContext {
+				in.Sql.Sql += " AND tenant_id=?"
+				in.Sql.Args = append(in.Sql.Args, 1)
+			}
+			return nil
+		})
+		fakeDB.RegisterHook(func(ctx context.Context, in *SqlHookInput) error {
+			hooked = append(hooked, &SqlHookInput{
+				Sql:           &Sql{Sql: in.Sql.Sql, Type: in.Sql.Type, Format: in.Sql.Format},
+				TransactionId: in.TransactionId,
+			})
+			// It short-circuits the statement, which is not committed to the driver.
+			if in.Sql.Type == SqlTypeExecContext {
+				return errDenied
+			}
+			return nil
+		})
+
+		tx, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		_, err = tx.Exec("DELETE FROM user WHERE id=?", 2)
+		t.Assert(errors.Is(err, errDenied), true)
+		t.AssertNil(tx.Commit())
+
+		t.Assert(len(hooked), 3)
+		t.Assert(hooked[0].Sql.Type, SqlTypeBegin)
+		t.Assert(hooked[0].Sql.Sql, "BEGIN")
+		t.Assert(hooked[1].Sql.Type, SqlTypeExecContext)
+		t.Assert(hooked[1].Sql.Format, "DELETE FROM user WHERE id=2 AND tenant_id=1")
+		t.Assert(hooked[2].Sql.Type, SqlTypeTXCommit)
+		// The statements of the transaction are correlated by the transaction id.
+		t.AssertNE(hooked[0].TransactionId, "")
+		t.Assert(hooked[1].TransactionId, hooked[0].TransactionId)
+		t.Assert(hooked[2].TransactionId, hooked[0].TransactionId)
+		t.Assert(tx.GetCtx().Value(GetTransactionIdContextKey()), hooked[0].TransactionId)
+
+		// The transaction is not begun if the hook short-circuits BEGIN.
+		fakeDB.RegisterHook(func(ctx context.Context, in *SqlHookInput) error {
+			if in.Sql.Type == SqlTypeBegin {
+				return errDenied
+			}
+			return nil
+		})
+		_, err = fakeDB.Begin(ctx)
+		t.Assert(errors.Is(err, errDenied), true)
+	})
+}
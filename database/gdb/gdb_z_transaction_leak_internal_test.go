@@ -0,0 +1,321 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/glog"
+	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+// safeBuffer is the bytes buffer that can be written by the finalizer goroutine concurrently.
+type safeBuffer struct {
+	buffer *bytes.Buffer
+	lock   chan struct{}
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.lock <- struct{}{}
+	defer func() { <-b.lock }()
+	return b.buffer.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.lock <- struct{}{}
+	defer func() { <-b.lock }()
+	return b.buffer.String()
+}
+
+func beginLeakedTransaction(t *gtest.T, db DB) {
+	_, err := db.Begin(ctx)
+	t.AssertNil(err)
+}
+
+func Test_Transaction_Leak_Warning(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		fakeDB, err := New(ConfigNode{Type: fakeDriverName})
+		t.AssertNil(err)
+
+		var (
+			buffer = &safeBuffer{buffer: bytes.NewBuffer(nil), lock: make(chan struct{}, 1)}
+			logger = glog.New()
+		)
+		logger.SetWriter(buffer)
+		logger.SetStdoutPrint(false)
+		fakeDB.SetLogger(logger)
+		fakeDB.SetDebug(true)
+
+		beginLeakedTransaction(t, fakeDB)
+		for i := 0; i < 50 && !gstr.Contains(buffer.String(), "garbage collected"); i++ {
+			runtime.GC()
+			time.Sleep(20 * time.Millisecond)
+		}
+		var content = buffer.String()
+		t.Assert(gstr.Contains(content, "garbage collected without Commit or Rollback"), true)
+		// The stack of package gdb is filtered, which contains this test file.
+		t.Assert(gstr.Contains(content, "gtest.C"), true)
+	})
+
+	// No warning for closed transaction.
+	gtest.C(t, func(t *gtest.T) {
+		fakeDB, err := New(ConfigNode{Type: fakeDriverName})
+		t.AssertNil(err)
+
+		var (
+			buffer = &safeBuffer{buffer: bytes.NewBuffer(nil), lock: make(chan struct{}, 1)}
+			logger = glog.New()
+		)
+		logger.SetWriter(buffer)
+		logger.SetStdoutPrint(false)
+		fakeDB.SetLogger(logger)
+		fakeDB.SetDebug(true)
+
+		tx, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		t.AssertNil(tx.Commit())
+		tx = nil
+		for i := 0; i < 5; i++ {
+			runtime.GC()
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Assert(gstr.Contains(buffer.String(), "garbage collected"), false)
+	})
+}
+
+func Test_SetTransactionIdContextKey(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		fakeDB, err := New(ConfigNode{Type: fakeDriverName})
+		t.AssertNil(err)
+
+		t.Assert(GetTransactionIdContextKey(), "TransactionId")
+		SetTransactionIdContextKey("GdbTxId")
+		defer SetTransactionIdContextKey("")
+		t.Assert(GetTransactionIdContextKey(), "GdbTxId")
+
+		tx, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		t.Assert(tx.GetCtx().Value("GdbTxId"), tx.Id())
+		t.AssertNil(tx.GetCtx().Value("TransactionId"))
+	})
+	gtest.C(t, func(t *gtest.T) {
+		SetTransactionIdContextKey("")
+		t.Assert(GetTransactionIdContextKey(), "TransactionId")
+	})
+}
+
+func Test_BeginTx_Options(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		fakeDB, err := New(ConfigNode{Type: fakeDriverName})
+		t.AssertNil(err)
+
+		tx, err := fakeDB.BeginTx(ctx, nil)
+		t.AssertNil(err)
+		t.AssertNil(tx.GetOptions())
+		t.AssertNil(tx.Rollback())
+
+		opts := &sql.TxOptions{}
+		tx, err = fakeDB.BeginTx(ctx, opts)
+		t.AssertNil(err)
+		t.Assert(tx.GetOptions() == opts, true)
+		t.AssertNil(tx.Rollback())
+	})
+}
+
+func Test_Transaction_Id(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		fakeDB, err := New(ConfigNode{Type: fakeDriverName})
+		t.AssertNil(err)
+
+		tx1, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		defer tx1.Rollback()
+		tx2, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		defer tx2.Rollback()
+
+		var (
+			id1 = tx1.Id()
+			id2 = tx2.Id()
+		)
+		t.Assert(len(id1), 30)
+		t.Assert(len(id2), 30)
+		t.Assert(tx1.GetCtx().Value(GetTransactionIdContextKey()), id1)
+		// The ids share the process unique suffix and increase monotonically.
+		t.Assert(id1[16:], transactionIdSuffix)
+		t.Assert(id2[16:], transactionIdSuffix)
+		t.Assert(id1 < id2, true)
+		// The sequence is seeded from timestamp instead of zero.
+		t.Assert(id1[:16] > fmt.Sprintf(`%016x`, uint64(time.Now().Add(-time.Hour).UnixNano())), true)
+	})
+}
+
+func Test_TX_Model_CtxDb(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		fakeDB, err := New(ConfigNode{Type: fakeDriverName})
+		t.AssertNil(err)
+
+		tx, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+
+		// The db bound with the context of transaction is reused by the models.
+		model1 := tx.Model("user")
+		model2 := tx.Model("user")
+		t.Assert(model1.db == model2.db, true)
+		t.Assert(model1.db == fakeDB, false)
+		t.Assert(model1.db.GetCtx().Value(GetTransactionIdContextKey()), tx.Id())
+
+		// It binds the db again if the context of transaction changes.
+		tx.Ctx(context.WithValue(ctx, "key", "value"))
+		model3 := tx.Model("user")
+		t.Assert(model3.db == model1.db, false)
+		t.Assert(model3.db.GetCtx().Value("key"), "value")
+		t.Assert(tx.Model("user").db == model3.db, true)
+	})
+}
+
+func Test_WithTXs(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		AddConfigNode("group1", ConfigNode{Type: fakeDriverName})
+		AddConfigNode("group2", ConfigNode{Type: fakeDriverName})
+		db1, err := NewByGroup("group1")
+		t.AssertNil(err)
+		db2, err := NewByGroup("group2")
+		t.AssertNil(err)
+
+		tx1, err := db1.Begin(ctx)
+		t.AssertNil(err)
+		defer tx1.Rollback()
+		tx2, err := db2.Begin(ctx)
+		t.AssertNil(err)
+		defer tx2.Rollback()
+		tx3, err := db2.Begin(ctx)
+		t.AssertNil(err)
+		defer tx3.Rollback()
+
+		t.Assert(len(TXsFromCtx(ctx)), 0)
+
+		// The transaction of the same group is not overwritten.
+		txCtx := WithTXs(ctx, tx1, tx2, tx3)
+		txs := TXsFromCtx(txCtx)
+		t.Assert(len(txs), 2)
+		t.Assert(txs["group1"].Id(), tx1.Id())
+		t.Assert(txs["group2"].Id(), tx2.Id())
+		t.Assert(TXFromCtx(txCtx, "group1").Id(), tx1.Id())
+		t.Assert(TXFromCtx(txCtx, "group2").Id(), tx2.Id())
+
+		// The closed transaction is not returned.
+		t.AssertNil(tx1.Rollback())
+		txs = TXsFromCtx(txCtx)
+		t.Assert(len(txs), 1)
+		t.Assert(txs["group2"].Id(), tx2.Id())
+	})
+}
+
+func Test_TXGroup_Invalid(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var option = fakeDriverOption{ExecError: errors.New("XA not supported")}
+		AddConfigNode("xa_group1", newFakeConfigNode(option))
+		AddConfigNode("xa_group2", newFakeConfigNode(option))
+		db1, err := NewByGroup("xa_group1")
+		t.AssertNil(err)
+		db2, err := NewByGroup("xa_group2")
+		t.AssertNil(err)
+
+		var called bool
+		f := func(ctx context.Context, txs map[string]TX) error {
+			called = true
+			return nil
+		}
+		t.AssertNE(NewTXGroup().Transaction(ctx, f), nil)
+		t.AssertNE(NewTXGroup(db1, db2, db1).Transaction(ctx, f), nil)
+		// The XA statements always fail with the fake driver.
+		t.AssertNE(NewTXGroup(db1, db2).Transaction(ctx, f), nil)
+		t.Assert(called, false)
+	})
+}
+
+func Test_Transaction_Panic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		fakeDB, err := New(ConfigNode{Type: fakeDriverName})
+		t.AssertNil(err)
+
+		err = fakeDB.Transaction(ctx, func(ctx context.Context, tx TX) error {
+			panic("error")
+		})
+		var panicErr *TXPanicError
+		t.Assert(errors.As(err, &panicErr), true)
+		t.Assert(panicErr.Value, "error")
+		t.Assert(gerror.Code(err), gcode.CodeInternalPanic)
+		t.Assert(gerror.HasStack(err), true)
+
+		// The recovered error is kept along with its code.
+		var recoveredErr = gerror.NewCode(gcode.CodeInvalidParameter, "invalid")
+		err = fakeDB.Transaction(ctx, func(ctx context.Context, tx TX) error {
+			panic(recoveredErr)
+		})
+		t.Assert(errors.As(err, &panicErr), true)
+		t.Assert(panicErr.Value, recoveredErr)
+		t.Assert(errors.Is(err, recoveredErr), true)
+		t.Assert(gerror.Code(err), gcode.CodeInvalidParameter)
+		t.Assert(err.Error(), "invalid")
+	})
+}
+
+func Test_JoinRollbackError(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			err         = gerror.NewCode(gcode.CodeInternalPanic, "panic")
+			rollbackErr = gerror.New("connection lost")
+			joinedErr   = joinRollbackError(err, rollbackErr)
+		)
+		t.Assert(errors.Is(joinedErr, err), true)
+		t.Assert(errors.Is(joinedErr, rollbackErr), true)
+		t.Assert(gerror.Code(joinedErr), gcode.CodeInternalPanic)
+		t.Assert(joinedErr.Error(), "panic; rollback failed: connection lost")
+		t.Assert(joinRollbackError(nil, rollbackErr), rollbackErr)
+	})
+}
+
+type fakeTxMetricsSink struct {
+	metrics []TxMetrics
+}
+
+func (s *fakeTxMetricsSink) RecordTx(ctx context.Context, metrics TxMetrics) {
+	s.metrics = append(s.metrics, metrics)
+}
+
+func Test_TX_Metrics_CommitFailed(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var commitErr = errors.New("connection lost")
+		fakeDB, err := New(newFakeConfigNode(fakeDriverOption{CommitError: commitErr}))
+		t.AssertNil(err)
+		sink := &fakeTxMetricsSink{}
+		fakeDB.SetTxMetricsSink(sink)
+
+		tx, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+		err = tx.Commit()
+		t.Assert(errors.Is(err, commitErr), true)
+		t.Assert(len(sink.metrics), 1)
+		t.Assert(sink.metrics[0].Committed, false)
+		t.Assert(errors.Is(sink.metrics[0].Error, commitErr), true)
+	})
+}
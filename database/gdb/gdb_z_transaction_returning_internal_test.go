@@ -0,0 +1,67 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func Test_TX_InsertAndReturning_Ctx(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			returnings = make(map[string][]string)
+			panicSql   string
+		)
+		node := newFakeConfigNode(fakeDriverOption{})
+		node.BatchSize = 1
+		fakeDB, err := New(node)
+		t.AssertNil(err)
+		fakeDB.RegisterHook(func(ctx context.Context, in *SqlHookInput) error {
+			if in.Sql.Type != SqlTypeExecContext {
+				return nil
+			}
+			returnings[in.Sql.Sql] = fakeDB.GetCore().GetReturningFromCtx(ctx)
+			if in.Sql.Sql == panicSql {
+				panic("hook panic")
+			}
+			return nil
+		})
+		tx, err := fakeDB.Begin(ctx)
+		t.AssertNil(err)
+		defer tx.Rollback()
+
+		// All the records are inserted using one statement in spite of the configured batch size.
+		_, _ = tx.InsertAndReturning("user", []map[string]interface{}{
+			{"id": 1, "name": "john"},
+			{"id": 2, "name": "smith"},
+		}, "id")
+		t.Assert(returnings, map[string][]string{
+			"INSERT INTO user(id,name) VALUES(?,?),(?,?) ": {"id"},
+		})
+
+		// The returning columns do not affect the following statements of the transaction.
+		_, err = tx.Exec("UPDATE user SET name='john'")
+		t.AssertNil(err)
+		t.Assert(returnings["UPDATE user SET name='john'"], nil)
+
+		// Nor does the panic in inserting.
+		panicSql = "INSERT INTO user(id,name) VALUES(?,?) "
+		func() {
+			defer func() {
+				t.Assert(recover(), "hook panic")
+			}()
+			_, _ = tx.InsertAndReturning("user", map[string]interface{}{"id": 3, "name": "alice"}, "id")
+		}()
+		t.Assert(returnings[panicSql], []string{"id"})
+		_, err = tx.Exec("UPDATE user SET name='smith'")
+		t.AssertNil(err)
+		t.Assert(returnings["UPDATE user SET name='smith'"], nil)
+	})
+}
@@ -10,6 +10,8 @@ package glog
 import (
 	"context"
 
+	"github.com/gogf/gf/v2/container/garray"
+	"github.com/gogf/gf/v2/container/gtype"
 	"github.com/gogf/gf/v2/internal/command"
 	"github.com/gogf/gf/v2/os/grpool"
 	"github.com/gogf/gf/v2/util/gconv"
@@ -52,6 +54,12 @@ var (
 	// It uses only one asynchronous worker to ensure log sequence.
 	asyncPool = grpool.New(1)
 
+	// registeredCtxKeys is the context keys registered by RegisterCtxKeys for all loggers.
+	registeredCtxKeys = garray.NewArray(true)
+
+	// testMode marks all loggers writing synchronously and deterministically for unit testing.
+	testMode = gtype.NewBool()
+
 	// defaultDebug enables debug level or not in default,
 	// which can be configured using command option or system environment.
 	defaultDebug = true
@@ -67,6 +75,46 @@ func DefaultLogger() *Logger {
 	return defaultLogger
 }
 
+// RegisterCtxKeys registers context keys for all loggers, the values of which are retrieved
+// from context and appended to every logging content as structured fields, in addition to
+// the Config.CtxKeys of each logger. The key whose value is missing in context is omitted.
+func RegisterCtxKeys(keys ...interface{}) {
+	for _, key := range keys {
+		registeredCtxKeys.Append(key)
+	}
+}
+
+// Flush blocks until all the pending asynchronous logging contents are written,
+// which guarantees no logging loss on clean exit of the process. It also outputs the sampling
+// summaries of default logger. It returns immediately if the asynchronous logging is closed by Close,
+// or it is in test mode, in which all the logging content is written synchronously.
+func Flush() {
+	if testMode.Val() {
+		return
+	}
+	defaultLogger.flushSampling()
+	if asyncPool.IsClosed() {
+		return
+	}
+	// As there's only one asynchronous worker, all the previous logging jobs are
+	// done when the flushing job is executed.
+	var done = make(chan struct{})
+	if err := asyncPool.Add(context.Background(), func(ctx context.Context) {
+		close(done)
+	}); err != nil {
+		return
+	}
+	<-done
+}
+
+// Close flushes the pending asynchronous logging contents and stops the background
+// goroutine of asynchronous logging output.
+// Note that the logging contents are written synchronously after Close even if F_ASYNC is set.
+func Close() {
+	Flush()
+	asyncPool.Close()
+}
+
 // SetDefaultLogger sets the default logger for package glog.
 // Note that there might be concurrent safety issue if calls this function
 // in different goroutines.
@@ -33,6 +33,12 @@ func Cat(category string) *Logger {
 	return defaultLogger.Cat(category)
 }
 
+// Module is a chaining function,
+// which sets the logical module name to `name` for current logging content output.
+func Module(name string) *Logger {
+	return defaultLogger.Module(name)
+}
+
 // File is a chaining function,
 // which sets file name `pattern` for the current logging content output.
 func File(pattern string) *Logger {
@@ -64,6 +70,12 @@ func Stack(enabled bool, skip ...int) *Logger {
 	return defaultLogger.Stack(enabled, skip...)
 }
 
+// ErrorStack is a chaining function,
+// which enables/disables outputting the stacks of the logged errors that have stacks.
+func ErrorStack(enabled ...bool) *Logger {
+	return defaultLogger.ErrorStack(enabled...)
+}
+
 // StackWithFilter is a chaining function,
 // which sets stack filter for the current logging content output .
 func StackWithFilter(filter string) *Logger {
@@ -96,3 +108,9 @@ func Line(long ...bool) *Logger {
 func Async(enabled ...bool) *Logger {
 	return defaultLogger.Async(enabled...)
 }
+
+// Json is a chaining function,
+// which enables/disables outputting each logging entry as a single json object.
+func Json(enabled ...bool) *Logger {
+	return defaultLogger.Json(enabled...)
+}
@@ -9,6 +9,7 @@ package glog
 import (
 	"context"
 	"io"
+	"time"
 )
 
 // SetConfig set configurations for the defaultLogger.
@@ -57,6 +58,24 @@ func SetWriter(writer io.Writer) {
 	defaultLogger.SetWriter(writer)
 }
 
+// SetNetworkWriter sets a NetworkWriter as the logging writer, which ships each logging record
+// in json format of FormatJson to the TCP or UDP endpoint `address`.
+func SetNetworkWriter(network, address string, option ...NetworkWriterOption) error {
+	return defaultLogger.SetNetworkWriter(network, address, option...)
+}
+
+// SetWriterForLevel sets the customized logging `writer` for the logging content of exactly `level`
+// for default defaultLogger.
+func SetWriterForLevel(level int, writer io.Writer) {
+	defaultLogger.SetWriterForLevel(level, writer)
+}
+
+// AddWriter adds the customized logging `writer` for default defaultLogger,
+// which works together with the previously set writers.
+func AddWriter(writer io.Writer) {
+	defaultLogger.AddWriter(writer)
+}
+
 // GetWriter returns the customized writer object, which implements the io.Writer interface.
 // It returns nil if no customized writer set.
 func GetWriter() io.Writer {
@@ -74,6 +93,55 @@ func SetAsync(enabled bool) {
 	defaultLogger.SetAsync(enabled)
 }
 
+// SetFormat sets the logging output format for default defaultLogger, which can be FormatText or FormatJson.
+func SetFormat(format string) {
+	defaultLogger.SetFormat(format)
+}
+
+// SetSampler sets the sampler for default defaultLogger, which drops the high-frequency logging content.
+func SetSampler(sampler Sampler) {
+	defaultLogger.SetSampler(sampler)
+}
+
+// SetSampling sets the sampler outputting only 1 of every `n` identical logging content for default defaultLogger.
+func SetSampling(n int) {
+	defaultLogger.SetSampling(n)
+}
+
+// SetSamplingRate sets the sampler outputting at most `perSecond` identical logging content per second
+// for default defaultLogger.
+func SetSamplingRate(perSecond int) {
+	defaultLogger.SetSamplingRate(perSecond)
+}
+
+// SetRedactKeys sets the keys whose values are replaced with "***" for default defaultLogger.
+func SetRedactKeys(keys []string) {
+	defaultLogger.SetRedactKeys(keys)
+}
+
+// SetRedactPattern sets the regular expression pattern whose matched parts of string values
+// are replaced with "***" for default defaultLogger.
+func SetRedactPattern(pattern string) error {
+	return defaultLogger.SetRedactPattern(pattern)
+}
+
+// SetTimePrecision sets the precision of the RFC3339 logging time in FormatJson for default defaultLogger.
+func SetTimePrecision(precision time.Duration) {
+	defaultLogger.SetTimePrecision(precision)
+}
+
+// SetTestMode enables/disables the test mode for all loggers, which makes logging synchronous and
+// deterministic for unit testing. In test mode, the logging content is written immediately
+// regardless of the async setting, so assertions can be made right after logging without waiting.
+func SetTestMode(enabled bool) {
+	testMode.Set(enabled)
+}
+
+// IsTestMode checks and returns whether the test mode is enabled.
+func IsTestMode() bool {
+	return testMode.Val()
+}
+
 // SetStdoutPrint sets whether ouptput the logging contents to stdout, which is true in default.
 func SetStdoutPrint(enabled bool) {
 	defaultLogger.SetStdoutPrint(enabled)
@@ -130,11 +198,32 @@ func SetStack(enabled bool) {
 	defaultLogger.SetStack(enabled)
 }
 
+// SetErrorStack enables/disables outputting the stacks of the logged errors that have stacks
+// for default defaultLogger.
+func SetErrorStack(enabled bool) {
+	defaultLogger.SetErrorStack(enabled)
+}
+
 // SetLevelStr sets the logging level by level string.
 func SetLevelStr(levelStr string) error {
 	return defaultLogger.SetLevelStr(levelStr)
 }
 
+// SetModuleLevel sets the default logging level for logical module `name`.
+func SetModuleLevel(name string, level int) {
+	defaultLogger.SetModuleLevel(name, level)
+}
+
+// SetModuleLevelStr sets the default logging level for logical module `name` by level string.
+func SetModuleLevelStr(name string, levelStr string) error {
+	return defaultLogger.SetModuleLevelStr(name, levelStr)
+}
+
+// GetModuleLevel returns the default logging level for logical module `name`.
+func GetModuleLevel(name string) int {
+	return defaultLogger.GetModuleLevel(name)
+}
+
 // SetLevelPrefix sets the prefix string for specified level.
 func SetLevelPrefix(level int, prefix string) {
 	defaultLogger.SetLevelPrefix(level, prefix)
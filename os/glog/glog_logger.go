@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/gogf/gf/v2/debug/gdebug"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/internal/consts"
 	"github.com/gogf/gf/v2/internal/errors"
 	"github.com/gogf/gf/v2/internal/intlog"
@@ -36,6 +37,7 @@ import (
 type Logger struct {
 	parent *Logger // Parent logger, if it is not empty, it means the logger is used in chaining function.
 	config Config  // Logger configuration.
+	module string  // Logical module name for module level filtering, which is set by chaining function Module.
 }
 
 const (
@@ -58,6 +60,11 @@ const (
 	F_TIME_STD   = F_TIME_DATE | F_TIME_MILLI
 )
 
+const (
+	FormatText = "text" // Output logging content in human-readable text, which is the default format.
+	FormatJson = "json" // Output each logging entry as a single json object per line.
+)
+
 // New creates and returns a custom logger.
 func New() *Logger {
 	return &Logger{
@@ -78,6 +85,7 @@ func (l *Logger) Clone() *Logger {
 	return &Logger{
 		config: l.config,
 		parent: l,
+		module: l.module,
 	}
 }
 
@@ -121,6 +129,34 @@ func (l *Logger) print(ctx context.Context, level int, stack string, values ...a
 		}
 	)
 
+	// Logging sampling, which is checked before the logging content is printed, and the sampler
+	// identifies the logging content by its raw values, which are not formatted for sampling.
+	// It does no sampling in test mode, as all the logging content should be deterministic.
+	if l.config.Sampler != nil && !testMode.Val() && !isSamplingSummary(values) {
+		if !l.config.Sampler.Sample(ctx, input) {
+			return
+		}
+		// The sampler created by SetSampling or SetSamplingRate outputs summary lines
+		// for the dropped logging content instead of the marker.
+		if _, ok := l.config.Sampler.(*countSampler); !ok {
+			// It uses full slice expression to avoid modifying the underlying array of `values`.
+			input.Values = append(values[:len(values):len(values)], sampledMarker)
+		}
+	}
+
+	// Stacks of the logged errors, which point to where the errors are created
+	// and are more helpful than the caller stack.
+	if l.config.ErrorStack {
+		if errorStack := l.getErrorStack(values); errorStack != "" {
+			input.Stack = errorStack
+		}
+	}
+
+	// Sensitive content redaction, which does not modify the given `values`.
+	if len(l.config.RedactKeys) > 0 || l.config.RedactPattern != "" {
+		input.Values = l.redactValues(input.Values)
+	}
+
 	// Logging handlers.
 	if len(l.config.Handlers) > 0 {
 		input.handlers = append(input.handlers, l.config.Handlers...)
@@ -191,24 +227,26 @@ func (l *Logger) print(ctx context.Context, level int, stack string, values ...a
 			input.TraceId = traceId.String()
 		}
 		// Context values.
-		if len(l.config.CtxKeys) > 0 {
-			for _, ctxKey := range l.config.CtxKeys {
-				var ctxValue interface{}
-				if ctxValue = ctx.Value(ctxKey); ctxValue == nil {
-					ctxValue = ctx.Value(gctx.StrKey(gconv.String(ctxKey)))
+		for _, ctxKey := range l.getCtxKeys() {
+			var ctxValue interface{}
+			if ctxValue = ctx.Value(ctxKey); ctxValue == nil {
+				ctxValue = ctx.Value(gctx.StrKey(gconv.String(ctxKey)))
+			}
+			if ctxValue != nil {
+				if input.CtxStr != "" {
+					input.CtxStr += ", "
 				}
-				if ctxValue != nil {
-					if input.CtxStr != "" {
-						input.CtxStr += ", "
-					}
-					input.CtxStr += gconv.String(ctxValue)
+				input.CtxStr += gconv.String(ctxValue)
+				if input.CtxFields == nil {
+					input.CtxFields = make(map[string]interface{})
 				}
+				input.CtxFields[gconv.String(ctxKey)] = ctxValue
 			}
 		}
 	}
-	if l.config.Flags&F_ASYNC > 0 {
+	if l.config.Flags&F_ASYNC > 0 && !testMode.Val() && !asyncPool.IsClosed() {
 		input.IsAsync = true
-		err := asyncPool.Add(ctx, func(ctx context.Context) {
+		err := defaultAsyncBuffer.Add(ctx, func(ctx context.Context) {
 			input.Next(ctx)
 		})
 		if err != nil {
@@ -219,6 +257,49 @@ func (l *Logger) print(ctx context.Context, level int, stack string, values ...a
 	}
 }
 
+// getCtxKeys returns the context keys for logging, which are the Config.CtxKeys
+// and the keys registered by RegisterCtxKeys that are not configured.
+func (l *Logger) getCtxKeys() []interface{} {
+	if registeredCtxKeys.Len() == 0 {
+		return l.config.CtxKeys
+	}
+	var ctxKeys = make([]interface{}, len(l.config.CtxKeys))
+	copy(ctxKeys, l.config.CtxKeys)
+	for _, registeredKey := range registeredCtxKeys.Slice() {
+		var found bool
+		for _, configKey := range l.config.CtxKeys {
+			if configKey == registeredKey {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ctxKeys = append(ctxKeys, registeredKey)
+		}
+	}
+	return ctxKeys
+}
+
+// Flush blocks until all the pending asynchronous logging contents are written.
+// Note that the asynchronous logging contents of all loggers are flushed, as they share
+// the same background goroutine. It also outputs the summaries of the logging content dropped
+// by the sampler created by SetSampling or SetSamplingRate.
+func (l *Logger) Flush() {
+	l.flushSampling()
+	Flush()
+}
+
+// flushSampling outputs the summaries of the logging content dropped by the sampler
+// created by SetSampling or SetSamplingRate immediately. It does nothing in test mode.
+func (l *Logger) flushSampling() {
+	if testMode.Val() {
+		return
+	}
+	if sampler, ok := l.config.Sampler.(*countSampler); ok {
+		sampler.Flush(l)
+	}
+}
+
 // doFinalPrint outputs the logging content according configuration.
 func (l *Logger) doFinalPrint(ctx context.Context, input *HandlerInput) *bytes.Buffer {
 	var buffer *bytes.Buffer
@@ -237,27 +318,59 @@ func (l *Logger) doFinalPrint(ctx context.Context, input *HandlerInput) *bytes.B
 	}
 
 	// Used custom writer.
-	if l.config.Writer != nil {
+	if writer := l.getWriterByLevel(input.Level); writer != nil {
 		// Output to custom writer.
-		if buf := l.printToWriter(ctx, input); buf != nil {
+		if buf := l.printToWriter(ctx, writer, input); buf != nil {
 			buffer = buf
 		}
 	}
 	return buffer
 }
 
+// getWriterByLevel returns the customized writer of exactly `level` set by SetWriterForLevel,
+// or the default customized writer if no writer is set for `level`.
+func (l *Logger) getWriterByLevel(level int) io.Writer {
+	if writer, ok := l.config.LevelWriters[level]; ok {
+		return writer
+	}
+	return l.config.Writer
+}
+
 // printToWriter writes buffer to writer.
-func (l *Logger) printToWriter(ctx context.Context, input *HandlerInput) *bytes.Buffer {
-	if l.config.Writer != nil {
+func (l *Logger) printToWriter(ctx context.Context, writer io.Writer, input *HandlerInput) *bytes.Buffer {
+	if writer != nil {
 		var buffer = input.getRealBuffer(l.config.WriterColorEnable)
-		if _, err := l.config.Writer.Write(buffer.Bytes()); err != nil {
-			intlog.Errorf(ctx, `%+v`, err)
+		switch writer := writer.(type) {
+		case *NetworkWriter:
+			buffer = input.getNetworkBuffer()
+			l.writeToWriter(ctx, writer, buffer.Bytes())
+
+		case *multiWriter:
+			// The logging content is formatted only once for all the writers.
+			for _, w := range writer.writers {
+				if _, ok := w.(*NetworkWriter); ok {
+					l.writeToWriter(ctx, w, input.getNetworkBuffer().Bytes())
+				} else {
+					l.writeToWriter(ctx, w, buffer.Bytes())
+				}
+			}
+
+		default:
+			l.writeToWriter(ctx, writer, buffer.Bytes())
 		}
 		return buffer
 	}
 	return nil
 }
 
+// writeToWriter writes `content` to `writer`, in which the error is logged internally
+// and does not affect other writers.
+func (l *Logger) writeToWriter(ctx context.Context, writer io.Writer, content []byte) {
+	if _, err := writer.Write(content); err != nil {
+		intlog.Errorf(ctx, `%+v`, err)
+	}
+}
+
 // printToStdout outputs logging content to stdout.
 func (l *Logger) printToStdout(ctx context.Context, input *HandlerInput) *bytes.Buffer {
 	if l.config.StdoutPrint {
@@ -287,8 +400,8 @@ func (l *Logger) printToFile(ctx context.Context, t time.Time, in *HandlerInput)
 	gmlock.Lock(memoryLockKey)
 	defer gmlock.Unlock(memoryLockKey)
 
-	// Rotation file size checks.
-	if l.config.RotateSize > 0 && gfile.Size(logFilePath) > l.config.RotateSize {
+	// Rotation file size and expiration checks.
+	if l.isRotationNeeded(logFilePath, t) {
 		if runtime.GOOS == "windows" {
 			file := l.createFpInPool(ctx, logFilePath)
 			if file == nil {
@@ -303,12 +416,12 @@ func (l *Logger) printToFile(ctx context.Context, t time.Time, in *HandlerInput)
 			if err := file.Close(true); err != nil {
 				intlog.Errorf(ctx, `%+v`, err)
 			}
-			l.rotateFileBySize(ctx, t)
+			l.rotateFile(ctx, t)
 
 			return buffer
 		}
 
-		l.rotateFileBySize(ctx, t)
+		l.rotateFile(ctx, t)
 	}
 	// Logging content outputting to disk file.
 	if file := l.createFpInPool(ctx, logFilePath); file == nil {
@@ -353,6 +466,17 @@ func (l *Logger) getFpFromPool(ctx context.Context, path string) *gfpool.File {
 	return file
 }
 
+// getErrorStack returns the stacks of the errors that have stacks in `values`.
+func (l *Logger) getErrorStack(values []any) string {
+	var stacks []string
+	for _, value := range values {
+		if err, ok := value.(error); ok && gerror.HasStack(err) {
+			stacks = append(stacks, gerror.Stack(err))
+		}
+	}
+	return strings.Join(stacks, "\n")
+}
+
 // printStd prints content `s` without stack.
 func (l *Logger) printStd(ctx context.Context, level int, values ...interface{}) {
 	l.print(ctx, level, "", values...)
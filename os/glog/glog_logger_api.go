@@ -142,5 +142,8 @@ func (l *Logger) Criticalf(ctx context.Context, format string, v ...interface{})
 
 // checkLevel checks whether the given `level` could be output.
 func (l *Logger) checkLevel(level int) bool {
+	if l.module != "" && len(l.config.ModuleLevels) > 0 {
+		return l.GetModuleLevel(l.module)&level > 0
+	}
 	return l.config.Level&level > 0
 }
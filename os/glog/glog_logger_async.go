@@ -0,0 +1,127 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/gogf/gf/v2/container/gtype"
+)
+
+// AsyncOverflow is the policy handling the asynchronous logging content when the buffer is full.
+type AsyncOverflow int
+
+const (
+	AsyncOverflowBlock      AsyncOverflow = iota // Block the caller until the buffer has room, which loses nothing.
+	AsyncOverflowDropNewest                      // Drop the new logging content.
+	AsyncOverflowDropOldest                      // Drop the oldest pending logging content to make room for the new one.
+)
+
+// asyncEntry is the asynchronous logging job pending in the buffer.
+type asyncEntry struct {
+	job     func(ctx context.Context)
+	element *list.Element // element of the entry in pending list, which is nil if it's started or dropped.
+}
+
+// asyncBuffer bounds the asynchronous logging contents pending in asyncPool.
+type asyncBuffer struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	size     int           // Max count of the pending logging contents, which is unbounded if it's 0.
+	overflow AsyncOverflow // Policy handling the new logging content when the buffer is full.
+	pending  *list.List    // Pending entries in the order they are added.
+	dropped  *gtype.Int64  // Count of the dropped logging contents.
+}
+
+// defaultAsyncBuffer is the buffer for asynchronous logging of all loggers, which is unbounded in default.
+var defaultAsyncBuffer = newAsyncBuffer()
+
+func newAsyncBuffer() *asyncBuffer {
+	b := &asyncBuffer{
+		pending: list.New(),
+		dropped: gtype.NewInt64(),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// SetAsyncBufferSize sets the max count of the asynchronous logging contents pending for writing
+// of all loggers, which is handled by the policy set by SetAsyncOverflow if it is exceeded.
+// It is 0 in default, which means the buffer is unbounded and the logging never blocks or drops.
+func SetAsyncBufferSize(size int) {
+	defaultAsyncBuffer.mu.Lock()
+	defer defaultAsyncBuffer.mu.Unlock()
+	defaultAsyncBuffer.size = size
+	defaultAsyncBuffer.cond.Broadcast()
+}
+
+// SetAsyncOverflow sets the policy handling the new asynchronous logging content when the buffer
+// set by SetAsyncBufferSize is full, which is AsyncOverflowBlock in default.
+func SetAsyncOverflow(overflow AsyncOverflow) {
+	defaultAsyncBuffer.mu.Lock()
+	defer defaultAsyncBuffer.mu.Unlock()
+	defaultAsyncBuffer.overflow = overflow
+	defaultAsyncBuffer.cond.Broadcast()
+}
+
+// DroppedCount returns the count of the asynchronous logging contents dropped
+// as the buffer is full, since the process starts.
+func DroppedCount() int64 {
+	return defaultAsyncBuffer.dropped.Val()
+}
+
+// Add adds `job` to asyncPool, which blocks or drops according to the overflow policy
+// if the buffer is full.
+func (b *asyncBuffer) Add(ctx context.Context, job func(ctx context.Context)) error {
+	var entry = &asyncEntry{job: job}
+	b.mu.Lock()
+	for b.size > 0 && b.pending.Len() >= b.size {
+		switch b.overflow {
+		case AsyncOverflowDropNewest:
+			b.mu.Unlock()
+			b.dropped.Add(1)
+			return nil
+
+		case AsyncOverflowDropOldest:
+			oldest := b.pending.Remove(b.pending.Front()).(*asyncEntry)
+			oldest.element = nil
+			oldest.job = nil
+			b.dropped.Add(1)
+
+		default:
+			b.cond.Wait()
+		}
+	}
+	entry.element = b.pending.PushBack(entry)
+	b.mu.Unlock()
+
+	err := asyncPool.Add(ctx, func(ctx context.Context) {
+		if job := b.start(entry); job != nil {
+			job(ctx)
+		}
+	})
+	if err != nil {
+		b.start(entry)
+	}
+	return err
+}
+
+// start removes `entry` from the pending list and returns its job,
+// or returns nil if it's dropped.
+func (b *asyncBuffer) start(entry *asyncEntry) func(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if entry.element == nil {
+		return nil
+	}
+	b.pending.Remove(entry.element)
+	entry.element = nil
+	b.cond.Signal()
+	return entry.job
+}
@@ -62,6 +62,21 @@ func (l *Logger) Cat(category string) *Logger {
 	return logger
 }
 
+// Module is a chaining function,
+// which sets the logical module name to `name` for current logging content output,
+// whose logging level is filtered using the level configured by SetModuleLevel for the module.
+// Param `name` can be hierarchical, eg: payment/alipay.
+func (l *Logger) Module(name string) *Logger {
+	logger := (*Logger)(nil)
+	if l.parent == nil {
+		logger = l.Clone()
+	} else {
+		logger = l
+	}
+	logger.module = name
+	return logger
+}
+
 // File is a chaining function,
 // which sets file name `pattern` for the current logging content output.
 func (l *Logger) File(file string) *Logger {
@@ -136,6 +151,21 @@ func (l *Logger) Stack(enabled bool, skip ...int) *Logger {
 	return logger
 }
 
+// ErrorStack is a chaining function,
+// which enables/disables outputting the stacks of the logged errors that have stacks
+// for the current logging content output.
+func (l *Logger) ErrorStack(enabled ...bool) *Logger {
+	logger := (*Logger)(nil)
+	if l.parent == nil {
+		logger = l.Clone()
+	} else {
+		logger = l
+	}
+	// error stack is enabled if `enabled` is not passed.
+	logger.SetErrorStack(len(enabled) == 0 || enabled[0])
+	return logger
+}
+
 // StackWithFilter is a chaining function,
 // which sets stack filter for the current logging content output .
 func (l *Logger) StackWithFilter(filter string) *Logger {
@@ -224,3 +254,21 @@ func (l *Logger) Async(enabled ...bool) *Logger {
 	}
 	return logger
 }
+
+// Json is a chaining function,
+// which enables/disables outputting each logging entry as a single json object.
+func (l *Logger) Json(enabled ...bool) *Logger {
+	logger := (*Logger)(nil)
+	if l.parent == nil {
+		logger = l.Clone()
+	} else {
+		logger = l
+	}
+	// json format is enabled if `enabled` is not passed.
+	if len(enabled) > 0 && !enabled[0] {
+		logger.SetFormat(FormatText)
+	} else {
+		logger.SetFormat(FormatJson)
+	}
+	return logger
+}
@@ -17,36 +17,45 @@ import (
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/internal/intlog"
 	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gregex"
 	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/gogf/gf/v2/util/gutil"
 )
 
 // Config is the configuration object for logger.
 type Config struct {
-	Handlers             []Handler      `json:"-"`                    // Logger handlers which implement feature similar as middleware.
-	Writer               io.Writer      `json:"-"`                    // Customized io.Writer.
-	Flags                int            `json:"flags"`                // Extra flags for logging output features.
-	TimeFormat           string         `json:"timeFormat"`           // Logging time format
-	Path                 string         `json:"path"`                 // Logging directory path.
-	File                 string         `json:"file"`                 // Format pattern for logging file.
-	Level                int            `json:"level"`                // Output level.
-	Prefix               string         `json:"prefix"`               // Prefix string for every logging content.
-	StSkip               int            `json:"stSkip"`               // Skipping count for stack.
-	StStatus             int            `json:"stStatus"`             // Stack status(1: enabled - default; 0: disabled)
-	StFilter             string         `json:"stFilter"`             // Stack string filter.
-	CtxKeys              []interface{}  `json:"ctxKeys"`              // Context keys for logging, which is used for value retrieving from context.
-	HeaderPrint          bool           `json:"header"`               // Print header or not(true in default).
-	StdoutPrint          bool           `json:"stdout"`               // Output to stdout or not(true in default).
-	LevelPrint           bool           `json:"levelPrint"`           // Print level format string or not(true in default).
-	LevelPrefixes        map[int]string `json:"levelPrefixes"`        // Logging level to its prefix string mapping.
-	RotateSize           int64          `json:"rotateSize"`           // Rotate the logging file if its size > 0 in bytes.
-	RotateExpire         time.Duration  `json:"rotateExpire"`         // Rotate the logging file if its mtime exceeds this duration.
-	RotateBackupLimit    int            `json:"rotateBackupLimit"`    // Max backup for rotated files, default is 0, means no backups.
-	RotateBackupExpire   time.Duration  `json:"rotateBackupExpire"`   // Max expires for rotated files, which is 0 in default, means no expiration.
-	RotateBackupCompress int            `json:"rotateBackupCompress"` // Compress level for rotated files using gzip algorithm. It's 0 in default, means no compression.
-	RotateCheckInterval  time.Duration  `json:"rotateCheckInterval"`  // Asynchronously checks the backups and expiration at intervals. It's 1 hour in default.
-	StdoutColorDisabled  bool           `json:"stdoutColorDisabled"`  // Logging level prefix with color to writer or not (false in default).
-	WriterColorEnable    bool           `json:"writerColorEnable"`    // Logging level prefix with color to writer or not (false in default).
+	Handlers             []Handler         `json:"-"`                    // Logger handlers which implement feature similar as middleware.
+	Writer               io.Writer         `json:"-"`                    // Customized io.Writer.
+	LevelWriters         map[int]io.Writer `json:"-"`                    // Logging level to its customized io.Writer mapping, which overrides Writer for the level.
+	Flags                int               `json:"flags"`                // Extra flags for logging output features.
+	TimeFormat           string            `json:"timeFormat"`           // Logging time format
+	Path                 string            `json:"path"`                 // Logging directory path.
+	File                 string            `json:"file"`                 // Format pattern for logging file.
+	Level                int               `json:"level"`                // Output level.
+	ModuleLevels         map[string]int    `json:"moduleLevels"`         // Logical module name to its output level mapping, which overrides Level for the module.
+	Prefix               string            `json:"prefix"`               // Prefix string for every logging content.
+	StSkip               int               `json:"stSkip"`               // Skipping count for stack.
+	StStatus             int               `json:"stStatus"`             // Stack status(1: enabled - default; 0: disabled)
+	StFilter             string            `json:"stFilter"`             // Stack string filter.
+	CtxKeys              []interface{}     `json:"ctxKeys"`              // Context keys for logging, which is used for value retrieving from context.
+	HeaderPrint          bool              `json:"header"`               // Print header or not(true in default).
+	StdoutPrint          bool              `json:"stdout"`               // Output to stdout or not(true in default).
+	LevelPrint           bool              `json:"levelPrint"`           // Print level format string or not(true in default).
+	LevelPrefixes        map[int]string    `json:"levelPrefixes"`        // Logging level to its prefix string mapping.
+	RotateSize           int64             `json:"rotateSize"`           // Rotate the logging file if its size > 0 in bytes.
+	RotateExpire         time.Duration     `json:"rotateExpire"`         // Rotate the logging file if its mtime exceeds this duration.
+	RotateBackupLimit    int               `json:"rotateBackupLimit"`    // Max backup for rotated files, default is 0, means no backups.
+	RotateBackupExpire   time.Duration     `json:"rotateBackupExpire"`   // Max expires for rotated files, which is 0 in default, means no expiration.
+	RotateBackupCompress int               `json:"rotateBackupCompress"` // Compress level for rotated files using gzip algorithm. It's 0 in default, means no compression.
+	RotateCheckInterval  time.Duration     `json:"rotateCheckInterval"`  // Asynchronously checks the backups and expiration at intervals. It's 1 hour in default.
+	StdoutColorDisabled  bool              `json:"stdoutColorDisabled"`  // Logging level prefix with color to writer or not (false in default).
+	WriterColorEnable    bool              `json:"writerColorEnable"`    // Logging level prefix with color to writer or not (false in default).
+	Format               string            `json:"format"`               // Logging output format, FormatText or FormatJson(FormatText in default).
+	Sampler              Sampler           `json:"-"`                    // Sampler for dropping high-frequency logging content, no sampling in default.
+	RedactKeys           []string          `json:"redactKeys"`           // Keys of map/struct values whose values are replaced with "***", case-insensitively.
+	RedactPattern        string            `json:"redactPattern"`        // Regular expression pattern, the matched parts of string values are replaced with "***".
+	TimePrecision        time.Duration     `json:"timePrecision"`        // Precision of RFC3339 logging time in FormatJson, which is millisecond in default.
+	ErrorStack           bool              `json:"errorStack"`           // Output the stacks of logged errors created by gerror instead of caller stack(false in default).
 	internalConfig
 }
 
@@ -60,6 +69,7 @@ func DefaultConfig() Config {
 		File:                defaultFileFormat,
 		Flags:               F_TIME_STD,
 		TimeFormat:          "",
+		Format:              FormatText,
 		Level:               LEVEL_ALL,
 		CtxKeys:             []interface{}{},
 		StStatus:            1,
@@ -117,6 +127,19 @@ func (l *Logger) SetConfigWithMap(m map[string]interface{}) error {
 			return gerror.NewCodef(gcode.CodeInvalidParameter, `invalid level string: %v`, levelValue)
 		}
 	}
+	// Change string configuration to int value for module levels.
+	moduleLevelsKey, moduleLevelsValue := gutil.MapPossibleItemByKey(m, "ModuleLevels")
+	if moduleLevelsValue != nil {
+		var moduleLevels = make(map[string]int)
+		for name, value := range gconv.Map(moduleLevelsValue) {
+			if level, ok := levelStringMap[strings.ToUpper(gconv.String(value))]; ok {
+				moduleLevels[name] = level | LEVEL_CRIT | LEVEL_PANI | LEVEL_FATA
+			} else {
+				return gerror.NewCodef(gcode.CodeInvalidParameter, `invalid level string: %v`, value)
+			}
+		}
+		m[moduleLevelsKey] = moduleLevels
+	}
 	// Change string configuration to int value for file rotation size.
 	rotateSizeKey, rotateSizeValue := gutil.MapPossibleItemByKey(m, "RotateSize")
 	if rotateSizeValue != nil {
@@ -150,6 +173,12 @@ func (l *Logger) SetAsync(enabled bool) {
 	}
 }
 
+// SetFormat sets the logging output format, which can be FormatText or FormatJson.
+// In FormatJson, each logging entry is output as a single json object per line.
+func (l *Logger) SetFormat(format string) {
+	l.config.Format = format
+}
+
 // SetFlags sets extra flags for logging output features.
 func (l *Logger) SetFlags(flags int) {
 	l.config.Flags = flags
@@ -169,6 +198,13 @@ func (l *Logger) SetStack(enabled bool) {
 	}
 }
 
+// SetErrorStack enables/disables outputting the stacks of the logged errors that have stacks,
+// eg: created by package gerror, which are output instead of the caller stack of the logging content.
+// The errors without stacks are logged as they are.
+func (l *Logger) SetErrorStack(enabled bool) {
+	l.config.ErrorStack = enabled
+}
+
 // SetStackSkip sets the stack offset from the end point.
 func (l *Logger) SetStackSkip(skip int) {
 	l.config.StSkip = skip
@@ -218,6 +254,59 @@ func (l *Logger) SetWriter(writer io.Writer) {
 	l.config.Writer = writer
 }
 
+// SetWriterForLevel sets the customized logging `writer` for the logging content of exactly `level`,
+// eg: LEVEL_ERRO, which is used instead of the writer set by SetWriter for the level, so that each
+// level can be routed independently. The levels without their own writers use the writer set by
+// SetWriter. Note that the logging content is only written to the writer of its exact level, use
+// MultiWriter for fanning out to multiple writers. Use nil `writer` to remove the writer of `level`.
+//
+// Eg, the logging content of error and above levels are written to stderr:
+//
+//	for _, level := range []int{glog.LEVEL_ERRO, glog.LEVEL_CRIT, glog.LEVEL_PANI, glog.LEVEL_FATA} {
+//		logger.SetWriterForLevel(level, os.Stderr)
+//	}
+func (l *Logger) SetWriterForLevel(level int, writer io.Writer) {
+	// It always creates a new mapping, as the previous one might be shared by
+	// the cloned loggers or being used by asynchronous logging.
+	var levelWriters = make(map[int]io.Writer, len(l.config.LevelWriters)+1)
+	for k, v := range l.config.LevelWriters {
+		levelWriters[k] = v
+	}
+	if writer == nil {
+		delete(levelWriters, level)
+	} else {
+		levelWriters[level] = writer
+	}
+	l.config.LevelWriters = levelWriters
+}
+
+// AddWriter adds the customized logging `writer` for logging, which works together with the
+// previously set writers, so that each logging entry is written to all of them. See MultiWriter.
+func (l *Logger) AddWriter(writer io.Writer) {
+	if l.config.Writer == nil {
+		l.config.Writer = writer
+		return
+	}
+	// It always creates a new writer, as the previous one might be being used by asynchronous logging.
+	l.config.Writer = MultiWriter(l.config.Writer, writer)
+}
+
+// SetNetworkWriter sets a NetworkWriter as the logging writer, which ships each logging record
+// in json format of FormatJson to the TCP or UDP endpoint `address`. See NewNetworkWriter.
+//
+// It works with the asynchronous logging feature, and the previously set NetworkWriter is closed.
+func (l *Logger) SetNetworkWriter(network, address string, option ...NetworkWriterOption) error {
+	writer, err := NewNetworkWriter(network, address, option...)
+	if err != nil {
+		return err
+	}
+	if previous, ok := l.config.Writer.(*NetworkWriter); ok {
+		_ = previous.Close()
+	}
+	l.config.Writer = writer
+	return nil
+}
+
 // GetWriter returns the customized writer object, which implements the io.Writer interface.
 // It returns nil if no writer previously set.
 func (l *Logger) GetWriter() io.Writer {
@@ -287,6 +376,83 @@ func (l *Logger) SetWriterColorEnable(enabled bool) {
 	l.config.WriterColorEnable = enabled
 }
 
+// SetRotateSize sets the size in bytes, the logging file is rotated if its size exceeds it.
+// It works together with SetRotateExpire, in which the logging file is rotated by whichever comes first.
+func (l *Logger) SetRotateSize(size int64) {
+	l.config.RotateSize = size
+}
+
+// SetRotateExpire sets the expiration, the logging file is rotated before writing if the duration
+// since it was opened exceeds it, and it is also rotated by the timely checks if its mtime exceeds it.
+// It works together with SetRotateSize, in which the logging file is rotated by whichever comes first.
+func (l *Logger) SetRotateExpire(expire time.Duration) {
+	l.config.RotateExpire = expire
+}
+
+// SetRotateBackupLimit sets the max count of the rotated files, which keeps only the most recent ones.
+// It is 0 in default, which means no backups and the logging file is removed when rotated.
+func (l *Logger) SetRotateBackupLimit(limit int) {
+	l.config.RotateBackupLimit = limit
+}
+
+// SetSampler sets the sampler for logging, which drops the logging content that the sampler decides,
+// and appends marker "sampled=true" to the logging content it keeps.
+// It is nil in default, which means no sampling. Use nil `sampler` to disable sampling.
+func (l *Logger) SetSampler(sampler Sampler) {
+	l.config.Sampler = sampler
+}
+
+// SetSampling sets the sampler for logging that outputs only 1 of every `n` occurrences of identical
+// logging content, which is identified by its level and raw values. The counts of the dropped
+// logging content are output as summary lines like "... repeated 4213 times" every minute and by Flush.
+// Use `n` <= 1 to disable sampling.
+func (l *Logger) SetSampling(n int) {
+	if n <= 1 {
+		l.config.Sampler = nil
+		return
+	}
+	l.config.Sampler = newCountSampler(n, 0)
+}
+
+// SetSamplingRate sets the sampler for logging that outputs at most `perSecond` occurrences of identical
+// logging content per second, which is identified by its level and raw values. The counts of the
+// dropped logging content are output as summary lines like "... repeated 4213 times" every minute and by Flush.
+// Use `perSecond` <= 0 to disable sampling.
+func (l *Logger) SetSamplingRate(perSecond int) {
+	if perSecond <= 0 {
+		l.config.Sampler = nil
+		return
+	}
+	l.config.Sampler = newCountSampler(0, perSecond)
+}
+
+// SetRedactKeys sets the keys whose values are replaced with "***" when logging map/struct values,
+// which are matched case-insensitively and recursively for nested map/struct values.
+// Use nil `keys` to disable the key redaction.
+func (l *Logger) SetRedactKeys(keys []string) {
+	l.config.RedactKeys = keys
+}
+
+// SetRedactPattern sets the regular expression `pattern`, the matched parts of string values
+// are replaced with "***" when logging, for example the credit card numbers.
+// It returns error if `pattern` is not a valid regular expression.
+// Use empty `pattern` to disable the pattern redaction.
+func (l *Logger) SetRedactPattern(pattern string) error {
+	if pattern != "" {
+		if err := gregex.Validate(pattern); err != nil {
+			return err
+		}
+	}
+	l.config.RedactPattern = pattern
+	return nil
+}
+
+// SetTimePrecision sets the precision of the RFC3339 logging time in FormatJson, which can be
+// time.Second, time.Millisecond, time.Microsecond or time.Nanosecond. It is millisecond in default.
+func (l *Logger) SetTimePrecision(precision time.Duration) {
+	l.config.TimePrecision = precision
+}
+
 // SetStdoutColorDisabled disables stdout logging with color.
 func (l *Logger) SetStdoutColorDisabled(disabled bool) {
 	l.config.StdoutColorDisabled = disabled
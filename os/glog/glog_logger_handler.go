@@ -62,6 +62,10 @@ type HandlerInput struct {
 	// It's empty if no Config.CtxKeys configured.
 	CtxStr string
 
+	// (ReadOnly) The retrieved context key-value pairs from context, only available if Config.CtxKeys
+	// configured or context keys registered by RegisterCtxKeys. The key whose value is missing is omitted.
+	CtxFields map[string]interface{}
+
 	// Trace id, only available if OpenTelemetry is enabled, or else it's an empty string.
 	TraceId string
 
@@ -84,8 +88,11 @@ type HandlerInput struct {
 }
 
 type internalHandlerInfo struct {
-	index    int       // Middleware handling index for internal usage.
-	handlers []Handler // Handler array calling bu index.
+	index       int           // Middleware handling index for internal usage.
+	handlers    []Handler     // Handler array calling bu index.
+	colorBuffer *bytes.Buffer // Formatted logging content with color, which is shared by all outputs.
+	plainBuffer *bytes.Buffer // Formatted logging content without color, which is shared by all outputs.
+	jsonBuffer  *bytes.Buffer // Logging content in json format of FormatJson, which is shared by all network writers.
 }
 
 // defaultHandler is the default handler for package.
@@ -206,11 +213,38 @@ func (in *HandlerInput) getDefaultBuffer(withColor bool) *bytes.Buffer {
 	return buffer
 }
 
+// getRealBuffer returns the formatted logging content for outputting, which is formatted only once
+// for each color option and shared by stdout, file and writer outputs.
 func (in *HandlerInput) getRealBuffer(withColor bool) *bytes.Buffer {
 	if in.Buffer.Len() > 0 {
 		return in.Buffer
 	}
-	return in.getDefaultBuffer(withColor)
+	if in.Logger.config.Format == FormatJson {
+		// Json format has no color.
+		if in.plainBuffer == nil {
+			in.plainBuffer = in.getJsonFormatBuffer()
+		}
+		return in.plainBuffer
+	}
+	if withColor {
+		if in.colorBuffer == nil {
+			in.colorBuffer = in.getDefaultBuffer(true)
+		}
+		return in.colorBuffer
+	}
+	if in.plainBuffer == nil {
+		in.plainBuffer = in.getDefaultBuffer(false)
+	}
+	return in.plainBuffer
+}
+
+// getNetworkBuffer returns the logging content shipped by NetworkWriter, which is always in json format
+// of FormatJson whatever the logging format is, and is formatted only once for all the network writers.
+func (in *HandlerInput) getNetworkBuffer() *bytes.Buffer {
+	if in.jsonBuffer == nil {
+		in.jsonBuffer = in.getJsonFormatBuffer()
+	}
+	return in.jsonBuffer
 }
 
 func (in *HandlerInput) addStringToBuffer(buffer *bytes.Buffer, strings ...string) {
@@ -7,8 +7,13 @@
 package glog
 
 import (
+	"bytes"
 	"context"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/gogf/gf/v2/internal/intlog"
 	"github.com/gogf/gf/v2/internal/json"
 )
 
@@ -25,8 +30,27 @@ type HandlerOutputJson struct {
 	Stack      string `json:",omitempty"` // Stack string produced by logger, only available if Config.StStatus configured.
 }
 
+// jsonFormatReservedKeys is the keys of the built-in fields in FormatJson.
+// The context fields whose keys collide with them are output with key prefixed with "fields.".
+var jsonFormatReservedKeys = map[string]struct{}{
+	"time": {}, "level": {}, "traceId": {}, "ctx": {}, "prefix": {},
+	"callerFunc": {}, "callerPath": {}, "content": {}, "stack": {},
+}
+
 // HandlerJson is a handler for output logging content as a single json string.
 func HandlerJson(ctx context.Context, in *HandlerInput) {
+	// Output json content.
+	jsonBytes, err := in.getJsonBytes()
+	if err != nil {
+		panic(err)
+	}
+	in.Buffer.Write(jsonBytes)
+	in.Buffer.Write([]byte("\n"))
+	in.Next(ctx)
+}
+
+// getJsonBytes returns the logging content as a single json.
+func (in *HandlerInput) getJsonBytes() ([]byte, error) {
 	output := HandlerOutputJson{
 		Time:       in.TimeFormat,
 		TraceId:    in.TraceId,
@@ -44,12 +68,119 @@ func HandlerJson(ctx context.Context, in *HandlerInput) {
 		}
 		output.Content += in.ValuesContent()
 	}
-	// Output json content.
-	jsonBytes, err := json.Marshal(output)
+	return json.Marshal(output)
+}
+
+// getJsonFormatBuffer returns the logging content as a single line json object for FormatJson.
+// The keys are output in stable order: the built-in fields "time", "level", "traceId", "ctx", "prefix",
+// "callerFunc", "callerPath", "content", "stack", and then the context fields as top-level keys
+// sorted by their keys, in which "stack" is the array of stack lines. The empty optional built-in
+// fields are omitted.
+// It falls back to the default text format if the json marshaling fails.
+func (in *HandlerInput) getJsonFormatBuffer() *bytes.Buffer {
+	var content = in.Content
+	if len(in.Values) > 0 {
+		if content != "" {
+			content += " "
+		}
+		content += in.ValuesContent()
+	}
+	var (
+		buffer = bytes.NewBuffer(nil)
+		writer = &jsonObjectWriter{buffer: buffer}
+	)
+	writer.Write("time", in.Time.Format(in.getJsonTimeLayout()))
+	writer.Write("level", in.LevelFormat)
+	writer.WriteNotEmpty("traceId", in.TraceId)
+	writer.WriteNotEmpty("ctx", in.CtxStr)
+	writer.WriteNotEmpty("prefix", in.Prefix)
+	writer.WriteNotEmpty("callerFunc", in.CallerFunc)
+	writer.WriteNotEmpty("callerPath", in.CallerPath)
+	writer.Write("content", content)
+	if in.Stack != "" {
+		writer.Write("stack", in.getStackLines())
+	}
+	var fieldKeys = make([]string, 0, len(in.CtxFields))
+	for key := range in.CtxFields {
+		fieldKeys = append(fieldKeys, key)
+	}
+	sort.Strings(fieldKeys)
+	for _, key := range fieldKeys {
+		var outputKey = key
+		if _, ok := jsonFormatReservedKeys[key]; ok {
+			outputKey = "fields." + key
+		}
+		writer.Write(outputKey, in.CtxFields[key])
+	}
+	if writer.err != nil {
+		intlog.Errorf(context.TODO(), `marshal logging content to json failed: %+v`, writer.err)
+		return in.getDefaultBuffer(false)
+	}
+	buffer.WriteString("}\n")
+	return buffer
+}
+
+// getStackLines returns the non-empty lines of the stack, which keep their indents.
+func (in *HandlerInput) getStackLines() []string {
+	var lines = make([]string, 0)
+	for _, line := range strings.Split(in.Stack, "\n") {
+		if line = strings.TrimRight(line, " \t\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// getJsonTimeLayout returns the RFC3339 time layout of Config.TimePrecision for FormatJson.
+func (in *HandlerInput) getJsonTimeLayout() string {
+	var precision = in.Logger.config.TimePrecision
+	switch {
+	case precision >= time.Second:
+		return time.RFC3339
+	case precision >= time.Millisecond || precision <= 0:
+		return "2006-01-02T15:04:05.000Z07:00"
+	case precision >= time.Microsecond:
+		return "2006-01-02T15:04:05.000000Z07:00"
+	default:
+		return "2006-01-02T15:04:05.000000000Z07:00"
+	}
+}
+
+// jsonObjectWriter writes the key-value pairs of a json object in the order they are written.
+// It stops writing at the first marshaling error, which is kept in err.
+type jsonObjectWriter struct {
+	buffer *bytes.Buffer
+	err    error
+}
+
+// Write writes the key-value pair to the json object.
+func (w *jsonObjectWriter) Write(key string, value interface{}) {
+	if w.err != nil {
+		return
+	}
+	keyBytes, err := json.Marshal(key)
 	if err != nil {
-		panic(err)
+		w.err = err
+		return
+	}
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		w.err = err
+		return
+	}
+	if w.buffer.Len() == 0 {
+		w.buffer.WriteByte('{')
+	} else {
+		w.buffer.WriteByte(',')
+	}
+	w.buffer.Write(keyBytes)
+	w.buffer.WriteByte(':')
+	w.buffer.Write(valueBytes)
+}
+
+// WriteNotEmpty writes the key-value pair to the json object if `value` is not empty.
+func (w *jsonObjectWriter) WriteNotEmpty(key string, value string) {
+	if value != "" {
+		w.Write(key, value)
 	}
-	in.Buffer.Write(jsonBytes)
-	in.Buffer.Write([]byte("\n"))
-	in.Next(ctx)
 }
@@ -84,6 +84,49 @@ func (l *Logger) SetLevelStr(levelStr string) error {
 	return nil
 }
 
+// SetModuleLevel sets the logging level for logical module `name`,
+// which overrides the logging level of the logger for the logging content of the module.
+// The module name can be hierarchical, eg: payment/alipay, and the module whose level is not
+// configured inherits the level of its nearest configured parent module, or else the logger.
+// Note that levels ` LEVEL_CRIT | LEVEL_PANI | LEVEL_FATA ` cannot be removed for logging content,
+// which are automatically added to levels.
+func (l *Logger) SetModuleLevel(name string, level int) {
+	// It always creates a new mapping, as the previous one might be shared by
+	// the cloned loggers or being used by concurrent logging.
+	var moduleLevels = make(map[string]int, len(l.config.ModuleLevels)+1)
+	for k, v := range l.config.ModuleLevels {
+		moduleLevels[k] = v
+	}
+	moduleLevels[name] = level | LEVEL_CRIT | LEVEL_PANI | LEVEL_FATA
+	l.config.ModuleLevels = moduleLevels
+}
+
+// SetModuleLevelStr sets the logging level for logical module `name` by level string.
+func (l *Logger) SetModuleLevelStr(name string, levelStr string) error {
+	if level, ok := levelStringMap[strings.ToUpper(levelStr)]; ok {
+		l.SetModuleLevel(name, level)
+	} else {
+		return gerror.NewCodef(gcode.CodeInvalidParameter, `invalid level string: %s`, levelStr)
+	}
+	return nil
+}
+
+// GetModuleLevel returns the logging level for logical module `name`,
+// which is the level of its nearest configured module in hierarchy, or else the level of the logger.
+func (l *Logger) GetModuleLevel(name string) int {
+	for name != "" {
+		if level, ok := l.config.ModuleLevels[name]; ok {
+			return level
+		}
+		pos := strings.LastIndex(name, "/")
+		if pos == -1 {
+			break
+		}
+		name = name[:pos]
+	}
+	return l.config.Level
+}
+
 // SetLevelPrefix sets the prefix string for specified level.
 func (l *Logger) SetLevelPrefix(level int, prefix string) {
 	l.config.LevelPrefixes[level] = prefix
@@ -0,0 +1,114 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+const (
+	// redactedValue is the replacement of the redacted sensitive content.
+	redactedValue = "***"
+	// redactMaxDepth is the max depth of nested map/struct values for redaction,
+	// which prevents endless recursion of values referring to themselves.
+	redactMaxDepth = 32
+)
+
+// redactValues returns a copy of `values` in which the sensitive content is redacted
+// according to the RedactKeys and RedactPattern configuration.
+func (l *Logger) redactValues(values []any) []any {
+	var redacted = make([]any, len(values))
+	for i, value := range values {
+		redacted[i] = l.redactValue(value, 0)
+	}
+	return redacted
+}
+
+// redactValue redacts and returns `value`, in which map/struct values are converted to maps
+// with values of RedactKeys replaced, and string values are replaced by RedactPattern.
+func (l *Logger) redactValue(value any, depth int) any {
+	if value == nil || depth > redactMaxDepth {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		return l.redactString(v)
+	case []byte:
+		return l.redactString(string(v))
+	case error, fmt.Stringer, json.Marshaler:
+		// The value has its own string representation, which is kept as it is.
+		if l.config.RedactPattern == "" {
+			return value
+		}
+		return l.redactString(gconv.String(v))
+	}
+	var reflectValue = reflect.ValueOf(value)
+	for reflectValue.Kind() == reflect.Ptr {
+		if reflectValue.IsNil() {
+			return value
+		}
+		reflectValue = reflectValue.Elem()
+	}
+	switch reflectValue.Kind() {
+	case reflect.Map, reflect.Struct:
+		var m = gconv.Map(value)
+		if m == nil {
+			return value
+		}
+		var redacted = make(map[string]any, len(m))
+		for k, v := range m {
+			if l.isRedactKey(k) {
+				redacted[k] = redactedValue
+			} else {
+				redacted[k] = l.redactValue(v, depth+1)
+			}
+		}
+		return redacted
+
+	case reflect.Slice, reflect.Array:
+		var redacted = make([]any, reflectValue.Len())
+		for i := range redacted {
+			redacted[i] = l.redactValue(reflectValue.Index(i).Interface(), depth+1)
+		}
+		return redacted
+
+	case reflect.String:
+		return l.redactString(reflectValue.String())
+
+	default:
+		return value
+	}
+}
+
+// redactString replaces the parts of `s` matching RedactPattern with redactedValue.
+func (l *Logger) redactString(s string) string {
+	if l.config.RedactPattern == "" {
+		return s
+	}
+	// The pattern is validated in SetRedactPattern, the error is returned only if it is
+	// configured by other ways, in which case the content is kept as it is.
+	if redacted, err := gregex.ReplaceString(l.config.RedactPattern, redactedValue, s); err == nil {
+		return redacted
+	}
+	return s
+}
+
+// isRedactKey checks and returns whether `key` is one of RedactKeys, case-insensitively.
+func (l *Logger) isRedactKey(key string) bool {
+	for _, redactKey := range l.config.RedactKeys {
+		if strings.EqualFold(key, redactKey) {
+			return true
+		}
+	}
+	return false
+}
@@ -9,10 +9,12 @@ package glog
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/gogf/gf/v2/container/garray"
+	"github.com/gogf/gf/v2/container/gmap"
 	"github.com/gogf/gf/v2/encoding/gcompress"
 	"github.com/gogf/gf/v2/internal/intlog"
 	"github.com/gogf/gf/v2/os/gfile"
@@ -26,12 +28,42 @@ const (
 	memoryLockPrefixForRotating = "glog.rotateChecksTimely:"
 )
 
-// rotateFileBySize rotates the current logging file according to the
-// configured rotation size.
-func (l *Logger) rotateFileBySize(ctx context.Context, now time.Time) {
-	if l.config.RotateSize <= 0 {
-		return
+var (
+	// The opened time of the logging files for the expiration checks before writing,
+	// as the mtime of the logging file is refreshed by every writing.
+	// It is shared by all loggers, as the same logging file might be written by different loggers.
+	fileOpenedTimeMap = gmap.NewStrAnyMap(true)
+)
+
+// isRotationNeeded checks and returns whether the logging file `filePath` should be rotated before
+// writing, which is true if its size exceeds RotateSize, or the duration since it was opened exceeds
+// RotateExpire, whichever comes first.
+func (l *Logger) isRotationNeeded(filePath string, now time.Time) bool {
+	if l.config.RotateSize > 0 && gfile.Size(filePath) > l.config.RotateSize {
+		return true
+	}
+	if l.config.RotateExpire > 0 && now.Sub(l.getFileOpenedTime(filePath, now)) > l.config.RotateExpire {
+		return true
+	}
+	return false
+}
+
+// getFileOpenedTime returns the time when the logging file `filePath` was opened for writing,
+// which is the time of its first writing, or its mtime if it already exists when it is checked
+// for the first time, for example, after the process restarts.
+func (l *Logger) getFileOpenedTime(filePath string, now time.Time) time.Time {
+	if !gfile.Exists(filePath) {
+		fileOpenedTimeMap.Set(filePath, now)
+		return now
 	}
+	return fileOpenedTimeMap.GetOrSetFuncLock(filePath, func() interface{} {
+		return gfile.MTime(filePath)
+	}).(time.Time)
+}
+
+// rotateFile rotates the current logging file according to the
+// configured rotation size and expiration.
+func (l *Logger) rotateFile(ctx context.Context, now time.Time) {
 	if err := l.doRotateFile(ctx, l.getFilePath(now)); err != nil {
 		// panic(err)
 		intlog.Errorf(ctx, `%+v`, err)
@@ -58,6 +90,7 @@ func (l *Logger) doRotateFile(ctx context.Context, filePath string) error {
 		if err := gfile.Remove(filePath); err != nil {
 			return err
 		}
+		fileOpenedTimeMap.Remove(filePath)
 		intlog.Printf(
 			ctx,
 			`%d size exceeds, no backups set, remove original logging file: %s`,
@@ -104,9 +137,42 @@ func (l *Logger) doRotateFile(ctx context.Context, filePath string) error {
 	if err := gfile.Rename(filePath, newFilePath); err != nil {
 		return err
 	}
+	fileOpenedTimeMap.Remove(filePath)
+	// It prunes the exceeded backup files right after rotation, rather than waiting for the timely checks.
+	l.pruneBackupFiles(ctx, dirPath, fileName, fileExtName)
 	return nil
 }
 
+// pruneBackupFiles removes the oldest backup files of the logging file exceeding RotateBackupLimit,
+// which keeps only the most recent ones. The backup files are sorted by their datetime suffix, like:
+// access.20200326101301899002.log, access.20200326101301899002.log.gz.
+func (l *Logger) pruneBackupFiles(ctx context.Context, dirPath, fileName, fileExtName string) {
+	if l.config.RotateBackupLimit <= 0 {
+		return
+	}
+	files, err := gfile.ScanDirFile(dirPath, fileName+".*")
+	if err != nil {
+		intlog.Errorf(ctx, `%+v`, err)
+		return
+	}
+	var (
+		backupFiles   = make([]string, 0)
+		backupPattern = fmt.Sprintf(`^%s\.\d{20}\.%s(\.gz)?$`, gregex.Quote(fileName), gregex.Quote(fileExtName))
+	)
+	for _, file := range files {
+		if gregex.IsMatchString(backupPattern, gfile.Basename(file)) {
+			backupFiles = append(backupFiles, file)
+		}
+	}
+	sort.Strings(backupFiles)
+	for i := 0; i < len(backupFiles)-l.config.RotateBackupLimit; i++ {
+		intlog.Printf(ctx, `remove exceeded backup limit file: %s`, backupFiles[i])
+		if err = gfile.Remove(backupFiles[i]); err != nil {
+			intlog.Errorf(ctx, `%+v`, err)
+		}
+	}
+}
+
 // rotateChecksTimely timely checks the backups expiration and the compression.
 func (l *Logger) rotateChecksTimely(ctx context.Context) {
 	defer gtimer.AddOnce(ctx, l.config.RotateCheckInterval, l.rotateChecksTimely)
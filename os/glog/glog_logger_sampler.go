@@ -0,0 +1,226 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/encoding/ghash"
+)
+
+const (
+	// sampledMarker is appended to the logging content that is kept by the sampler set by SetSampler.
+	sampledMarker = "sampled=true"
+	// defaultSamplingSummaryInterval is the interval for outputting the summaries of the logging content
+	// dropped by the sampler created by SetSampling or SetSamplingRate.
+	defaultSamplingSummaryInterval = time.Minute
+)
+
+// Sampler is the interface for logging sampling, which decides whether the logging content should be
+// output or dropped. It is commonly used to reduce the volume of high-frequency logging in hot paths.
+type Sampler interface {
+	// Sample checks and returns whether the logging content of `in` should be output.
+	Sample(ctx context.Context, in *HandlerInput) bool
+}
+
+// firstSampler is the sampler that outputs the first N occurrences of identical logging content
+// in every interval and drops the rest.
+type firstSampler struct {
+	mu          sync.Mutex
+	first       int            // Max occurrences count of identical logging content in every interval.
+	interval    time.Duration  // Interval for occurrences counting.
+	windowStart time.Time      // Start time of current interval.
+	counts      map[uint64]int // Logging content hash => occurrences count in current interval.
+}
+
+// NewSampler creates and returns a sampler that outputs the first `first` occurrences of
+// identical logging content in every `interval` and drops the rest.
+// The identical logging content is identified by the hash of its level and raw values, see samplingKey.
+func NewSampler(first int, interval time.Duration) Sampler {
+	return &firstSampler{
+		first:    first,
+		interval: interval,
+		counts:   make(map[uint64]int),
+	}
+}
+
+// Sample implements interface Sampler.
+func (s *firstSampler) Sample(ctx context.Context, in *HandlerInput) bool {
+	var (
+		now = time.Now()
+		key = samplingKey(in)
+	)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// It resets all the counts for new interval, which also limits the memory usage.
+	if now.Sub(s.windowStart) >= s.interval {
+		s.windowStart = now
+		s.counts = make(map[uint64]int)
+	}
+	s.counts[key]++
+	return s.counts[key] <= s.first
+}
+
+// samplingSummary is appended to the logging values of the identical logging content dropped by countSampler
+// as the summary line, which is output without sampling.
+type samplingSummary struct {
+	count int // Count of the dropped logging content.
+}
+
+// countSampler is the sampler that outputs 1 of every N occurrences, or at most N occurrences per second,
+// of identical logging content, and periodically outputs the counts of the dropped ones as summary lines.
+type countSampler struct {
+	mu          sync.Mutex
+	every       int                          // Outputs 1 of every `every` occurrences if it is greater than 0.
+	perSecond   int                          // Outputs at most `perSecond` occurrences per second if it is greater than 0.
+	interval    time.Duration                // Interval for outputting the summaries.
+	lastSummary time.Time                    // Time of last summaries outputting.
+	items       map[uint64]*countSamplerItem // Logging content hash => its counting item.
+}
+
+// countSamplerItem is the counting item of identical logging content in countSampler.
+type countSamplerItem struct {
+	level       int       // Logging level of the content.
+	values      []any     // Logging values of the first occurrence.
+	count       int       // Occurrences count, which is the count in current second for rate-based sampling.
+	windowStart time.Time // Start time of current second for rate-based sampling.
+	dropped     int       // Dropped count since last summaries outputting.
+}
+
+// newCountSampler creates and returns a countSampler, in which either `every` or `perSecond` is given.
+func newCountSampler(every, perSecond int) *countSampler {
+	return &countSampler{
+		every:       every,
+		perSecond:   perSecond,
+		interval:    defaultSamplingSummaryInterval,
+		lastSummary: time.Now(),
+		items:       make(map[uint64]*countSamplerItem),
+	}
+}
+
+// String implements interface fmt.Stringer.
+func (s *samplingSummary) String() string {
+	return fmt.Sprintf(`... repeated %d times`, s.count)
+}
+
+// Sample implements interface Sampler.
+// It also outputs the summaries of dropped logging content if the summary interval is reached.
+func (s *countSampler) Sample(ctx context.Context, in *HandlerInput) bool {
+	var (
+		now = time.Now()
+		key = samplingKey(in)
+	)
+	s.mu.Lock()
+	item, ok := s.items[key]
+	if !ok {
+		item = &countSamplerItem{
+			level:       in.Level,
+			values:      in.Values,
+			windowStart: now,
+		}
+		s.items[key] = item
+	}
+	if s.perSecond > 0 && now.Sub(item.windowStart) >= time.Second {
+		item.windowStart = now
+		item.count = 0
+	}
+	item.count++
+	var sampled bool
+	if s.perSecond > 0 {
+		sampled = item.count <= s.perSecond
+	} else {
+		sampled = (item.count-1)%s.every == 0
+	}
+	if !sampled {
+		item.dropped++
+	}
+	var dueItems []*countSamplerItem
+	if now.Sub(s.lastSummary) >= s.interval {
+		dueItems = s.takeDroppedItems(now)
+	}
+	s.mu.Unlock()
+
+	// It prints the summaries without lock, as the printing might be synchronous.
+	s.printSummaries(in.Logger, dueItems)
+	return sampled
+}
+
+// Flush outputs the summaries of all the dropped logging content immediately using logger `l`.
+func (s *countSampler) Flush(l *Logger) {
+	s.mu.Lock()
+	dueItems := s.takeDroppedItems(time.Now())
+	s.mu.Unlock()
+	s.printSummaries(l, dueItems)
+}
+
+// takeDroppedItems returns the items having dropped logging content and resets all the counting items,
+// which also limits the memory usage. It should be called with lock.
+func (s *countSampler) takeDroppedItems(now time.Time) []*countSamplerItem {
+	var dueItems []*countSamplerItem
+	for _, item := range s.items {
+		if item.dropped > 0 {
+			dueItems = append(dueItems, item)
+		}
+	}
+	s.lastSummary = now
+	s.items = make(map[uint64]*countSamplerItem)
+	return dueItems
+}
+
+// printSummaries prints the summary lines of `items` in their own levels using logger `l`.
+func (s *countSampler) printSummaries(l *Logger, items []*countSamplerItem) {
+	if l == nil {
+		return
+	}
+	for _, item := range items {
+		// It uses full slice expression to avoid modifying the underlying array of the logging values.
+		values := append(item.values[:len(item.values):len(item.values)], &samplingSummary{
+			count: item.dropped,
+		})
+		l.print(context.Background(), item.level, "", values...)
+	}
+}
+
+// samplingKey returns the hash key identifying the identical logging content of `in` for sampling.
+// It uses the level and the raw values of `in` without formatting them for performance, in which
+// the string and error values are identified by their content and the others are identified by
+// their types. Note that the format string of formatted logging like Infof is already applied
+// to its values, which are identified by the formatted string.
+func samplingKey(in *HandlerInput) uint64 {
+	var builder strings.Builder
+	builder.WriteString(strconv.Itoa(in.Level))
+	for _, v := range in.Values {
+		builder.WriteByte(0)
+		switch value := v.(type) {
+		case nil:
+		case string:
+			builder.WriteString(value)
+		case []byte:
+			builder.Write(value)
+		case error:
+			builder.WriteString(value.Error())
+		default:
+			builder.WriteString(reflect.TypeOf(v).String())
+		}
+	}
+	return ghash.BKDR64([]byte(builder.String()))
+}
+
+// isSamplingSummary checks and returns whether `values` is the summary line of sampler, which is not sampled.
+func isSamplingSummary(values []any) bool {
+	if len(values) == 0 {
+		return false
+	}
+	_, ok := values[len(values)-1].(*samplingSummary)
+	return ok
+}
@@ -0,0 +1,49 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"io"
+)
+
+// multiWriter is the writer fanning out each logging entry to all its writers.
+type multiWriter struct {
+	writers []io.Writer
+}
+
+// MultiWriter creates and returns a writer that writes each logging entry to all given writers,
+// which can be set as the logging writer using SetWriter. The nested writers created by MultiWriter
+// are flattened, and the nil writers are ignored.
+//
+// Unlike io.MultiWriter, a failing writer does not stop writing to the other writers,
+// and the error of the first failing writer is returned after all writers are written.
+// The NetworkWriter in it still ships records in json format of FormatJson.
+func MultiWriter(writers ...io.Writer) io.Writer {
+	var w = &multiWriter{
+		writers: make([]io.Writer, 0, len(writers)),
+	}
+	for _, writer := range writers {
+		switch v := writer.(type) {
+		case nil:
+		case *multiWriter:
+			w.writers = append(w.writers, v.writers...)
+		default:
+			w.writers = append(w.writers, v)
+		}
+	}
+	return w
+}
+
+// Write implements the io.Writer interface, which writes `p` to all writers.
+func (w *multiWriter) Write(p []byte) (n int, err error) {
+	for _, writer := range w.writers {
+		if _, writeErr := writer.Write(p); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+	return len(p), err
+}
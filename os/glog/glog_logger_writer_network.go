@@ -0,0 +1,206 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/gogf/gf/v2/container/gtype"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/internal/intlog"
+)
+
+// NetworkWriter is a writer that ships logging records to a TCP or UDP endpoint,
+// which is used for centralized log collection without a local agent.
+//
+// The records are buffered locally in a bounded queue and sent in a background goroutine,
+// which reconnects with exponential backoff if the TCP connection fails.
+type NetworkWriter struct {
+	network   string              // Network type: tcp, tcp4, tcp6, udp, udp4, udp6.
+	address   string              // Remote endpoint address, like: 127.0.0.1:5000.
+	option    NetworkWriterOption // Option for this writer.
+	records   chan []byte         // Bounded local buffer for records during network outages.
+	dropped   *gtype.Int64        // Count of records dropped as the buffer is full.
+	closed    *gtype.Bool         // Marks the writer is closed.
+	closeChan chan struct{}       // Used for notifying the background goroutine to stop.
+	doneChan  chan struct{}       // Closed when the background goroutine stops.
+}
+
+// NetworkWriterOption is the option for NetworkWriter.
+type NetworkWriterOption struct {
+	BufferSize       int           // Max count of records buffered locally, default is 1024.
+	Block            bool          // Block logging if the buffer is full, or else the record is dropped (false in default).
+	DialTimeout      time.Duration // Timeout for dialing to the endpoint, default is 5 seconds.
+	RetryInterval    time.Duration // Initial interval for reconnecting, which doubles on each failure, default is 100ms.
+	MaxRetryInterval time.Duration // Max interval for reconnecting, default is 10 seconds.
+}
+
+const (
+	defaultNetworkBufferSize       = 1024
+	defaultNetworkDialTimeout      = 5 * time.Second
+	defaultNetworkRetryInterval    = 100 * time.Millisecond
+	defaultNetworkMaxRetryInterval = 10 * time.Second
+)
+
+// NewNetworkWriter creates and returns a NetworkWriter shipping records to `address` using `network`,
+// which should be one of: tcp, tcp4, tcp6, udp, udp4, udp6.
+// The connection is established lazily in background, so it does not fail if the endpoint
+// is temporarily unavailable.
+func NewNetworkWriter(network, address string, option ...NetworkWriterOption) (*NetworkWriter, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+	default:
+		return nil, gerror.NewCodef(
+			gcode.CodeInvalidParameter,
+			`invalid network "%s" for logging, it should be one of: tcp, tcp4, tcp6, udp, udp4, udp6`,
+			network,
+		)
+	}
+	var opt NetworkWriterOption
+	if len(option) > 0 {
+		opt = option[0]
+	}
+	if opt.BufferSize <= 0 {
+		opt.BufferSize = defaultNetworkBufferSize
+	}
+	if opt.DialTimeout <= 0 {
+		opt.DialTimeout = defaultNetworkDialTimeout
+	}
+	if opt.RetryInterval <= 0 {
+		opt.RetryInterval = defaultNetworkRetryInterval
+	}
+	if opt.MaxRetryInterval < opt.RetryInterval {
+		opt.MaxRetryInterval = defaultNetworkMaxRetryInterval
+	}
+	w := &NetworkWriter{
+		network:   network,
+		address:   address,
+		option:    opt,
+		records:   make(chan []byte, opt.BufferSize),
+		dropped:   gtype.NewInt64(),
+		closed:    gtype.NewBool(),
+		closeChan: make(chan struct{}),
+		doneChan:  make(chan struct{}),
+	}
+	go w.loop()
+	return w, nil
+}
+
+// Write implements the io.Writer interface.
+// It puts a copy of `p` into the local buffer as one record, which is sent in background.
+// If the buffer is full, it blocks or drops the record according to NetworkWriterOption.Block.
+func (w *NetworkWriter) Write(p []byte) (n int, err error) {
+	if w.closed.Val() {
+		return 0, gerror.NewCode(gcode.CodeInvalidOperation, `network writer is closed`)
+	}
+	record := make([]byte, len(p))
+	copy(record, p)
+	if w.option.Block {
+		select {
+		case w.records <- record:
+		case <-w.closeChan:
+			return 0, gerror.NewCode(gcode.CodeInvalidOperation, `network writer is closed`)
+		}
+		return len(p), nil
+	}
+	select {
+	case w.records <- record:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Dropped returns the count of records dropped as the local buffer is full.
+func (w *NetworkWriter) Dropped() int64 {
+	return w.dropped.Val()
+}
+
+// Close stops the writer after sending the buffered records with best effort.
+func (w *NetworkWriter) Close() error {
+	if !w.closed.Cas(false, true) {
+		return nil
+	}
+	close(w.closeChan)
+	<-w.doneChan
+	return nil
+}
+
+// loop sends the buffered records to the endpoint in background until the writer is closed.
+func (w *NetworkWriter) loop() {
+	var (
+		ctx  = context.Background()
+		conn net.Conn
+	)
+	defer func() {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		close(w.doneChan)
+	}()
+	for {
+		select {
+		case record := <-w.records:
+			if conn = w.send(ctx, conn, record); conn == nil {
+				// Closed while reconnecting.
+				return
+			}
+
+		case <-w.closeChan:
+			// Sends the remaining records without retrying.
+			for {
+				select {
+				case record := <-w.records:
+					if conn == nil {
+						return
+					}
+					if _, err := conn.Write(record); err != nil {
+						intlog.Errorf(ctx, `%+v`, err)
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send writes `record` using `conn`, it reconnects with exponential backoff until the record is sent.
+// It returns the connection for next sending, or nil if the writer is closed during reconnecting.
+func (w *NetworkWriter) send(ctx context.Context, conn net.Conn, record []byte) net.Conn {
+	var (
+		err      error
+		interval = w.option.RetryInterval
+	)
+	for {
+		if conn == nil {
+			if conn, err = net.DialTimeout(w.network, w.address, w.option.DialTimeout); err != nil {
+				intlog.Errorf(ctx, `dial to "%s" failed: %+v`, w.address, err)
+				select {
+				case <-time.After(interval):
+				case <-w.closeChan:
+					return nil
+				}
+				if interval *= 2; interval > w.option.MaxRetryInterval {
+					interval = w.option.MaxRetryInterval
+				}
+				continue
+			}
+		}
+		if _, err = conn.Write(record); err != nil {
+			intlog.Errorf(ctx, `write to "%s" failed: %+v`, w.address, err)
+			_ = conn.Close()
+			conn = nil
+			continue
+		}
+		return conn
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 
 	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/glog"
 )
 
 func ExampleContext() {
@@ -21,3 +22,15 @@ func ExampleContext() {
 	// Stack:
 	// ...
 }
+
+func ExampleFlush() {
+	var ctx = context.Background()
+	glog.SetAsync(true)
+	// The asynchronous logging contents are written before exit of the process.
+	defer glog.Flush()
+
+	glog.Info(ctx, "asynchronous logging")
+
+	// May Output:
+	// 2020-06-08 20:17:03.630 [INFO] asynchronous logging
+}
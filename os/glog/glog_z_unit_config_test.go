@@ -64,3 +64,45 @@ func Test_SetConfigWithMap_LevelStr(t *testing.T) {
 		t.Assert(strings.Contains(buffer.String(), "WARN"), true)
 	})
 }
+
+func Test_SetConfigWithMap_ModuleLevels(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		buffer := bytes.NewBuffer(nil)
+		l := New()
+		m := map[string]interface{}{
+			"level": "warn",
+			"moduleLevels": map[string]interface{}{
+				"payment": "debug",
+			},
+		}
+		err := l.SetConfigWithMap(m)
+		t.AssertNil(err)
+		l.SetWriter(buffer)
+		l.Debug(ctx, "test")
+		l.Module("payment").Debug(ctx, "payment")
+		t.Assert(strings.Contains(buffer.String(), "test"), false)
+		t.Assert(strings.Contains(buffer.String(), "payment"), true)
+	})
+
+	// Levels CRIT, PANI and FATA cannot be removed for modules.
+	gtest.C(t, func(t *gtest.T) {
+		l := New()
+		m := map[string]interface{}{
+			"moduleLevels": map[string]interface{}{
+				"payment": "error",
+			},
+		}
+		t.AssertNil(l.SetConfigWithMap(m))
+		t.Assert(l.GetModuleLevel("payment"), LEVEL_ERRO|LEVEL_CRIT|LEVEL_PANI|LEVEL_FATA)
+	})
+
+	gtest.C(t, func(t *gtest.T) {
+		l := New()
+		m := map[string]interface{}{
+			"moduleLevels": map[string]interface{}{
+				"payment": "invalid",
+			},
+		}
+		t.AssertNE(l.SetConfigWithMap(m), nil)
+	})
+}
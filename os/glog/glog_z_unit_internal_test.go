@@ -9,8 +9,13 @@ package glog
 import (
 	"bytes"
 	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/grpool"
 	"github.com/gogf/gf/v2/test/gtest"
 	"github.com/gogf/gf/v2/text/gstr"
 )
@@ -129,3 +134,249 @@ func Test_LevelPrefix(t *testing.T) {
 		t.Assert(gstr.Contains(buffer.String(), "error"), true)
 	})
 }
+
+// slowWriter is the writer that writes slowly, for testing asynchronous logging flushing.
+type slowWriter struct {
+	buffer bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (n int, err error) {
+	time.Sleep(20 * time.Millisecond)
+	return w.buffer.Write(p)
+}
+
+func Test_Flush_Close(t *testing.T) {
+	defer func() {
+		asyncPool = grpool.New(1)
+	}()
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = &slowWriter{}
+			l = NewWithWriter(w)
+		)
+		l.SetAsync(true)
+		for i := 0; i < 5; i++ {
+			l.Print(ctx, "flush")
+		}
+		l.Flush()
+		t.Assert(gstr.Count(w.buffer.String(), "flush"), 5)
+
+		l.Print(ctx, "close")
+		Close()
+		t.Assert(gstr.Count(w.buffer.String(), "close"), 1)
+		t.Assert(asyncPool.IsClosed(), true)
+
+		// Synchronous writing after closed.
+		l.Print(ctx, "after close")
+		t.Assert(gstr.Count(w.buffer.String(), "after close"), 1)
+		// Flushing after closed returns immediately.
+		Flush()
+	})
+}
+
+func Test_Flush_TestMode(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		SetTestMode(true)
+		defer SetTestMode(false)
+
+		// The asynchronous worker is busy, but flushing does not wait for it in test mode.
+		var release = make(chan struct{})
+		defer close(release)
+		t.AssertNil(asyncPool.Add(ctx, func(ctx context.Context) {
+			<-release
+		}))
+		var done = make(chan struct{})
+		go func() {
+			Flush()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("Flush blocks in test mode")
+		}
+	})
+}
+
+func Test_RegisterCtxKeys(t *testing.T) {
+	defer registeredCtxKeys.Clear()
+	gtest.C(t, func(t *gtest.T) {
+		RegisterCtxKeys("UserId", "RequestId")
+
+		var (
+			w        = bytes.NewBuffer(nil)
+			l        = NewWithWriter(w)
+			fields   map[string]interface{}
+			valueCtx = context.WithValue(ctx, gctx.StrKey("UserId"), "10000")
+		)
+		l.SetCtxKeys("UserId")
+		t.Assert(l.getCtxKeys(), []interface{}{"UserId", "RequestId"})
+
+		l.Print(valueCtx, "text")
+		t.Assert(gstr.Contains(w.String(), "{10000} text"), true)
+
+		// Structured fields, in which the missing key is omitted.
+		w.Reset()
+		l.SetHandlers(func(ctx context.Context, in *HandlerInput) {
+			fields = in.CtxFields
+			in.Next(ctx)
+		})
+		l.Json().Print(valueCtx, "json")
+		t.Assert(fields, map[string]interface{}{"UserId": "10000"})
+		t.Assert(gstr.Contains(w.String(), `"UserId":"10000"`), true)
+	})
+}
+
+// blockingWriter is the writer whose first writing blocks until `release` is closed.
+type blockingWriter struct {
+	buffer  *bytes.Buffer
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() {
+		close(w.started)
+		<-w.release
+	})
+	return w.buffer.Write(p)
+}
+
+func Test_AsyncOverflow(t *testing.T) {
+	defer SetAsyncBufferSize(0)
+	defer SetAsyncOverflow(AsyncOverflowBlock)
+	for _, c := range []struct {
+		overflow AsyncOverflow
+		expect   string
+	}{
+		{AsyncOverflowDropNewest, "1 2 3"},
+		{AsyncOverflowDropOldest, "1 3 4"},
+	} {
+		gtest.C(t, func(t *gtest.T) {
+			var (
+				w = &blockingWriter{
+					buffer:  bytes.NewBuffer(nil),
+					started: make(chan struct{}),
+					release: make(chan struct{}),
+				}
+				l       = NewWithWriter(w)
+				dropped = DroppedCount()
+			)
+			SetAsyncBufferSize(2)
+			SetAsyncOverflow(c.overflow)
+			l.SetHeaderPrint(false)
+			l.SetAsync(true)
+			// The worker is blocked in writing the first one, in which the others are pending.
+			l.Print(ctx, 1)
+			<-w.started
+			l.Print(ctx, 2)
+			l.Print(ctx, 3)
+			l.Print(ctx, 4)
+			close(w.release)
+			Flush()
+			t.Assert(gstr.Join(gstr.SplitAndTrim(w.buffer.String(), "\n"), " "), c.expect)
+			t.Assert(DroppedCount()-dropped, 1)
+		})
+	}
+	// Block.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = &blockingWriter{
+				buffer:  bytes.NewBuffer(nil),
+				started: make(chan struct{}),
+				release: make(chan struct{}),
+			}
+			l       = NewWithWriter(w)
+			dropped = DroppedCount()
+			done    = make(chan struct{})
+		)
+		SetAsyncBufferSize(1)
+		SetAsyncOverflow(AsyncOverflowBlock)
+		l.SetHeaderPrint(false)
+		l.SetAsync(true)
+		l.Print(ctx, 1)
+		<-w.started
+		l.Print(ctx, 2)
+		go func() {
+			l.Print(ctx, 3)
+			close(done)
+		}()
+		select {
+		case <-done:
+			t.Error("logging is not blocked when the buffer is full")
+		case <-time.After(100 * time.Millisecond):
+		}
+		close(w.release)
+		<-done
+		Flush()
+		t.Assert(gstr.Join(gstr.SplitAndTrim(w.buffer.String(), "\n"), " "), "1 2 3")
+		t.Assert(DroppedCount()-dropped, 0)
+	})
+}
+
+func Test_CountSampler_Summary(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w       = bytes.NewBuffer(nil)
+			l       = NewWithWriter(w)
+			sampler = newCountSampler(2, 0)
+		)
+		sampler.interval = 100 * time.Millisecond
+		l.SetHeaderPrint(false)
+		l.SetSampler(sampler)
+		for i := 0; i < 4; i++ {
+			l.Print(ctx, "hot path")
+		}
+		time.Sleep(150 * time.Millisecond)
+		// The summary is output periodically by the next logging.
+		l.Print(ctx, "another")
+		t.Assert(
+			gstr.SplitAndTrim(w.String(), "\n"),
+			[]string{"hot path", "hot path", "hot path ... repeated 2 times", "another"},
+		)
+	})
+	// Neither dropping nor summaries in test mode.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w       = bytes.NewBuffer(nil)
+			l       = NewWithWriter(w)
+			sampler = newCountSampler(2, 0)
+		)
+		l.SetHeaderPrint(false)
+		l.SetSampler(sampler)
+		l.Print(ctx, "hot path")
+		l.Print(ctx, "hot path")
+
+		SetTestMode(true)
+		defer SetTestMode(false)
+		for i := 0; i < 3; i++ {
+			l.Print(ctx, "hot path")
+		}
+		l.Flush()
+		t.Assert(
+			gstr.SplitAndTrim(w.String(), "\n"),
+			[]string{"hot path", "hot path", "hot path", "hot path"},
+		)
+	})
+}
+
+func Test_SamplingKey(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		newInput := func(level int, values ...any) *HandlerInput {
+			return &HandlerInput{Level: level, Values: values}
+		}
+		t.Assert(samplingKey(newInput(LEVEL_INFO, "hot path")), samplingKey(newInput(LEVEL_INFO, "hot path")))
+		t.AssertNE(samplingKey(newInput(LEVEL_INFO, "hot path")), samplingKey(newInput(LEVEL_WARN, "hot path")))
+		t.AssertNE(samplingKey(newInput(LEVEL_INFO, "hot path")), samplingKey(newInput(LEVEL_INFO, "another")))
+		t.AssertNE(samplingKey(newInput(LEVEL_INFO, "a", "b")), samplingKey(newInput(LEVEL_INFO, "ab")))
+		t.AssertNE(
+			samplingKey(newInput(LEVEL_INFO, errors.New("error 1"))),
+			samplingKey(newInput(LEVEL_INFO, errors.New("error 2"))),
+		)
+		// Values other than string and error are identified by their types without formatting.
+		t.Assert(samplingKey(newInput(LEVEL_INFO, "user:", 1)), samplingKey(newInput(LEVEL_INFO, "user:", 2)))
+		t.AssertNE(samplingKey(newInput(LEVEL_INFO, "user:", 1)), samplingKey(newInput(LEVEL_INFO, "user:", "1")))
+		t.Assert(samplingKey(newInput(LEVEL_INFO, nil)), samplingKey(newInput(LEVEL_INFO, nil)))
+	})
+}
@@ -8,13 +8,17 @@ package glog
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/gogf/gf/v2/internal/json"
+	"github.com/gogf/gf/v2/os/gctx"
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/text/gregex"
 	"github.com/gogf/gf/v2/text/gstr"
 )
 
@@ -80,6 +84,52 @@ func Test_Level(t *testing.T) {
 	})
 }
 
+func Test_Module(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			buffer = bytes.NewBuffer(nil)
+			l      = NewWithWriter(buffer)
+		)
+		l.SetLevel(LEVEL_WARN)
+		l.SetModuleLevel("payment", LEVEL_ALL)
+		l.SetModuleLevel("payment/refund", LEVEL_ERRO)
+
+		l.Debug(ctx, "default debug")
+		l.Module("order").Debug(ctx, "order debug")
+		l.Module("order").Warning(ctx, "order warning")
+		l.Module("payment").Debug(ctx, "payment debug")
+		l.Module("payment/alipay").Debug(ctx, "alipay debug")
+		l.Module("payment/refund").Warning(ctx, "refund warning")
+		l.Module("payment/refund").Error(ctx, "refund error")
+
+		content := buffer.String()
+		t.Assert(gstr.Contains(content, "default debug"), false)
+		t.Assert(gstr.Contains(content, "order debug"), false)
+		t.Assert(gstr.Contains(content, "order warning"), true)
+		t.Assert(gstr.Contains(content, "payment debug"), true)
+		t.Assert(gstr.Contains(content, "alipay debug"), true)
+		t.Assert(gstr.Contains(content, "refund warning"), false)
+		t.Assert(gstr.Contains(content, "refund error"), true)
+
+		t.Assert(l.GetModuleLevel("order"), l.GetLevel())
+		t.Assert(l.GetModuleLevel("payment/alipay/app"), l.GetModuleLevel("payment"))
+		t.AssertNE(l.SetModuleLevelStr("payment", "invalid"), nil)
+		t.AssertNil(l.SetModuleLevelStr("payment", "info"))
+		t.Assert(l.GetModuleLevel("payment")&LEVEL_DEBU, 0)
+	})
+	// Setting module level of the cloned logger does not affect the original one.
+	gtest.C(t, func(t *gtest.T) {
+		l := New()
+		l.SetModuleLevel("payment", LEVEL_ERRO)
+		cloned := l.Clone()
+		cloned.SetModuleLevel("payment", LEVEL_ALL)
+		cloned.SetModuleLevel("order", LEVEL_ALL)
+		t.Assert(l.GetModuleLevel("payment")&LEVEL_DEBU, 0)
+		t.Assert(l.GetModuleLevel("order"), l.GetLevel())
+		t.Assert(cloned.GetModuleLevel("payment")&LEVEL_DEBU, LEVEL_DEBU)
+	})
+}
+
 func Test_Skip(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
 		path := gfile.Temp(gtime.TimestampNanoStr())
@@ -225,7 +275,7 @@ func Test_Async(t *testing.T) {
 		defer gfile.Remove(path)
 
 		Path(path).File(file).Async().Stdout(false).Debug(ctx, 1, 2, 3)
-		time.Sleep(1000 * time.Millisecond)
+		Flush()
 
 		content := gfile.GetContents(gfile.Join(path, file))
 		t.Assert(gstr.Count(content, defaultLevelPrefixes[LEVEL_DEBU]), 1)
@@ -246,3 +296,84 @@ func Test_Async(t *testing.T) {
 		t.Assert(gstr.Count(content, "1 2 3"), 1)
 	})
 }
+
+func Test_Json(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		w := bytes.NewBuffer(nil)
+		To(w).Json().Stdout(false).Error(ctx, "quote\" and\nnewline", 1)
+
+		var m map[string]interface{}
+		lines := gstr.Split(gstr.Trim(w.String()), "\n")
+		t.Assert(len(lines), 1)
+		t.AssertNil(json.UnmarshalUseNumber([]byte(lines[0]), &m))
+		t.Assert(m["level"], "ERRO")
+		t.Assert(m["content"], "quote\" and\nnewline 1")
+		t.AssertNE(m["time"], "")
+		t.AssertNE(m["stack"], nil)
+	})
+	// Async.
+	gtest.C(t, func(t *gtest.T) {
+		path := gfile.Temp(gtime.TimestampNanoStr())
+		file := fmt.Sprintf(`%d.log`, gtime.TimestampNano())
+
+		err := gfile.Mkdir(path)
+		t.AssertNil(err)
+		defer gfile.Remove(path)
+
+		Path(path).File(file).Json().Async().Stdout(false).Info(ctx, 1, 2, 3)
+		Flush()
+
+		var m map[string]interface{}
+		content := gfile.GetContents(gfile.Join(path, file))
+		t.AssertNil(json.UnmarshalUseNumber([]byte(content), &m))
+		t.Assert(m["level"], "INFO")
+		t.Assert(m["content"], "1 2 3")
+	})
+	// Stable key order and context fields as top-level keys.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w        = bytes.NewBuffer(nil)
+			l        = NewWithWriter(w)
+			valueCtx = context.WithValue(ctx, gctx.StrKey("UserId"), "10000")
+		)
+		valueCtx = context.WithValue(valueCtx, gctx.StrKey("level"), "vip")
+		l.SetCtxKeys("UserId", "level")
+		l.SetStack(false)
+		l.SetFormat(FormatJson)
+		l.Info(valueCtx, "content")
+		t.Assert(gregex.IsMatchString(
+			`^\{"time":"[^"]+","level":"INFO","ctx":"10000, vip","content":"content","UserId":"10000","fields.level":"vip"\}\n$`,
+			w.String(),
+		), true)
+	})
+	// Time precision.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = bytes.NewBuffer(nil)
+			l = NewWithWriter(w)
+			m map[string]interface{}
+		)
+		l.SetFormat(FormatJson)
+		for precision, pattern := range map[time.Duration]string{
+			0:                `\.\d{3}(Z|[+-]\d{2}:\d{2})$`,
+			time.Second:      `:\d{2}(Z|[+-]\d{2}:\d{2})$`,
+			time.Millisecond: `\.\d{3}(Z|[+-]\d{2}:\d{2})$`,
+			time.Microsecond: `\.\d{6}(Z|[+-]\d{2}:\d{2})$`,
+			time.Nanosecond:  `\.\d{9}(Z|[+-]\d{2}:\d{2})$`,
+		} {
+			w.Reset()
+			l.SetTimePrecision(precision)
+			l.Print(ctx, 1)
+			t.AssertNil(json.UnmarshalUseNumber(w.Bytes(), &m))
+			_, err := time.Parse(time.RFC3339Nano, m["time"].(string))
+			t.AssertNil(err)
+			t.Assert(gregex.IsMatchString(pattern, m["time"].(string)), true)
+		}
+	})
+	// Disabled.
+	gtest.C(t, func(t *gtest.T) {
+		w := bytes.NewBuffer(nil)
+		To(w).Json(false).Stdout(false).Info(ctx, 1, 2, 3)
+		t.Assert(gstr.Contains(w.String(), "[INFO] 1 2 3"), true)
+	})
+}
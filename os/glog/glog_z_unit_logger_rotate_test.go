@@ -8,6 +8,7 @@ package glog_test
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -111,3 +112,92 @@ func Test_Rotate_Expire(t *testing.T) {
 		t.Assert(len(files), 0)
 	})
 }
+
+func Test_Rotate_Size_Expire_Combined(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := glog.New()
+		p := gfile.Temp(gtime.TimestampNanoStr())
+		t.AssertNil(l.SetPath(p))
+		defer gfile.Remove(p)
+		l.SetFile("access.log")
+		l.SetStdoutPrint(false)
+		l.SetRotateSize(100)
+		l.SetRotateExpire(time.Second)
+		l.SetRotateBackupLimit(2)
+
+		// Rotated by size, and the exceeded backups are pruned right after rotation.
+		s := "1234567890abcdefg"
+		for i := 0; i < 20; i++ {
+			l.Print(ctx, s)
+		}
+		files, err := gfile.ScanDirFile(p, "access.*.log")
+		t.AssertNil(err)
+		t.Assert(len(files), 2)
+		t.Assert(gstr.Count(gfile.GetContents(gfile.Join(p, "access.log")), s) < 20, true)
+
+		// Rotated by expiration before the size exceeds.
+		l.SetRotateSize(1024 * 1024)
+		l.Print(ctx, "expire1")
+		time.Sleep(1500 * time.Millisecond)
+		l.Print(ctx, "expire2")
+		content := gfile.GetContents(gfile.Join(p, "access.log"))
+		t.Assert(gstr.Contains(content, "expire1"), false)
+		t.Assert(gstr.Contains(content, "expire2"), true)
+		files, err = gfile.ScanDirFile(p, "access.*.log")
+		t.AssertNil(err)
+		t.Assert(len(files), 2)
+	})
+
+	// Rotated by expiration since the logging file was opened, though its mtime is refreshed by writing.
+	gtest.C(t, func(t *gtest.T) {
+		l := glog.New()
+		p := gfile.Temp(gtime.TimestampNanoStr())
+		t.AssertNil(l.SetPath(p))
+		defer gfile.Remove(p)
+		l.SetFile("access.log")
+		l.SetStdoutPrint(false)
+		l.SetRotateExpire(time.Second)
+		l.SetRotateBackupLimit(2)
+
+		for i := 0; i < 4; i++ {
+			l.Printf(ctx, "expire%d", i)
+			time.Sleep(400 * time.Millisecond)
+		}
+		content := gfile.GetContents(gfile.Join(p, "access.log"))
+		t.Assert(gstr.Contains(content, "expire0"), false)
+		t.Assert(gstr.Contains(content, "expire3"), true)
+		files, err := gfile.ScanDirFile(p, "access.*.log")
+		t.AssertNil(err)
+		t.Assert(len(files), 1)
+	})
+
+	// Concurrent and asynchronous writing.
+	gtest.C(t, func(t *gtest.T) {
+		l := glog.New()
+		p := gfile.Temp(gtime.TimestampNanoStr())
+		t.AssertNil(l.SetPath(p))
+		defer gfile.Remove(p)
+		l.SetFile("access.log")
+		l.SetStdoutPrint(false)
+		l.SetAsync(true)
+		l.SetRotateSize(200)
+		l.SetRotateBackupLimit(3)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					l.Print(ctx, "1234567890abcdefg")
+				}
+			}()
+		}
+		wg.Wait()
+		l.Flush()
+
+		files, err := gfile.ScanDirFile(p, "access.*.log")
+		t.AssertNil(err)
+		t.Assert(len(files), 3)
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package glog_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/internal/json"
+	"github.com/gogf/gf/v2/os/glog"
+	"github.com/gogf/gf/v2/test/gtest"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+// startNetworkLogServer starts a tcp server on `address` that sends each received line to `lines`.
+func startNetworkLogServer(t *gtest.T, address string, lines chan string) net.Listener {
+	listener, err := net.Listen("tcp", address)
+	t.AssertNil(err)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+	return listener
+}
+
+func TestLogger_SetNetworkWriter(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx      = context.TODO()
+			lines    = make(chan string, 100)
+			listener = startNetworkLogServer(t, "127.0.0.1:0", lines)
+			address  = listener.Addr().String()
+			l        = glog.New()
+		)
+		l.SetStdoutPrint(false)
+		l.SetAsync(true)
+		err := l.SetNetworkWriter("tcp", address, glog.NetworkWriterOption{
+			RetryInterval: 10 * time.Millisecond,
+		})
+		t.AssertNil(err)
+		defer l.GetWriter().(*glog.NetworkWriter).Close()
+
+		l.Info(ctx, "network logging")
+		select {
+		case line := <-lines:
+			var output map[string]interface{}
+			t.AssertNil(json.Unmarshal([]byte(line), &output))
+			t.Assert(output["level"], "INFO")
+			t.Assert(output["content"], "network logging")
+			t.AssertNE(output["time"], nil)
+		case <-time.After(5 * time.Second):
+			t.Error("timeout receiving logging record")
+		}
+
+		// Brief outage of the listener.
+		t.AssertNil(listener.Close())
+		listener = startNetworkLogServer(t, address, lines)
+		defer listener.Close()
+
+		// Records may be lost before the broken connection is detected.
+		var received bool
+		for i := 0; i < 50 && !received; i++ {
+			l.Info(ctx, "after reconnection")
+			select {
+			case line := <-lines:
+				received = line != ""
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+		t.Assert(received, true)
+	})
+}
+
+func TestLogger_MultiWriter_NetworkWriter(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			ctx      = context.TODO()
+			lines    = make(chan string, 100)
+			listener = startNetworkLogServer(t, "127.0.0.1:0", lines)
+			buffer   = bytes.NewBuffer(nil)
+			l        = glog.New()
+		)
+		defer listener.Close()
+		networkWriter, err := glog.NewNetworkWriter("tcp", listener.Addr().String(), glog.NetworkWriterOption{
+			RetryInterval: 10 * time.Millisecond,
+		})
+		t.AssertNil(err)
+		defer networkWriter.Close()
+		l.SetStdoutPrint(false)
+		l.SetWriter(glog.MultiWriter(buffer, networkWriter))
+
+		l.Info(ctx, "multi writer")
+		t.Assert(gstr.Contains(buffer.String(), "[INFO] multi writer"), true)
+		select {
+		case line := <-lines:
+			var output map[string]interface{}
+			t.AssertNil(json.Unmarshal([]byte(line), &output))
+			t.Assert(output["level"], "INFO")
+			t.Assert(output["content"], "multi writer")
+		case <-time.After(5 * time.Second):
+			t.Error("timeout receiving logging record")
+		}
+	})
+}
+
+func TestNewNetworkWriter(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		_, err := glog.NewNetworkWriter("unix", "/tmp/glog.sock")
+		t.AssertNE(err, nil)
+	})
+	// Drops records if the buffer is full during outage.
+	gtest.C(t, func(t *gtest.T) {
+		w, err := glog.NewNetworkWriter("tcp", "127.0.0.1:1", glog.NetworkWriterOption{
+			BufferSize:    1,
+			RetryInterval: time.Second,
+		})
+		t.AssertNil(err)
+		defer w.Close()
+		for i := 0; i < 5; i++ {
+			_, err = w.Write([]byte("record\n"))
+			t.AssertNil(err)
+		}
+		t.AssertGE(w.Dropped(), 3)
+		t.AssertNil(w.Close())
+		_, err = w.Write([]byte("record\n"))
+		t.AssertNE(err, nil)
+	})
+}
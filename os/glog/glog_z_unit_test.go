@@ -9,18 +9,22 @@ package glog_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/internal/json"
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/gogf/gf/v2/os/glog"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/test/gtest"
 	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
 )
 
 func TestCase(t *testing.T) {
@@ -128,6 +132,23 @@ func Test_SetAsync(t *testing.T) {
 	})
 }
 
+func Test_SetTestMode(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		glog.SetTestMode(true)
+		defer glog.SetTestMode(false)
+		t.Assert(glog.IsTestMode(), true)
+
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+		)
+		l.SetAsync(true)
+		l.Print(ctx, "test mode")
+		// No waiting for the asynchronous writing.
+		t.Assert(gstr.Count(w.String(), "test mode"), 1)
+	})
+}
+
 func Test_SetStdoutPrint(t *testing.T) {
 	defaultLog := glog.DefaultLogger().Clone()
 	defer glog.SetDefaultLogger(defaultLog)
@@ -180,6 +201,141 @@ func Test_SetWriter(t *testing.T) {
 	})
 }
 
+// failingWriter is the writer that always fails writing.
+type failingWriter struct{}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func Test_MultiWriter(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w1 = bytes.NewBuffer(nil)
+			w2 = bytes.NewBuffer(nil)
+			w3 = bytes.NewBuffer(nil)
+			l  = glog.New()
+		)
+		l.SetStdoutPrint(false)
+		// The failing writer does not stop writing to the other writers.
+		l.SetWriter(glog.MultiWriter(w1, failingWriter{}, glog.MultiWriter(w2, nil)))
+		l.Info(ctx, "tee")
+		t.Assert(gstr.Contains(w1.String(), "[INFO] tee"), true)
+		t.Assert(w1.String(), w2.String())
+
+		// AddWriter works together with the previously set writers.
+		w1.Reset()
+		w2.Reset()
+		l.AddWriter(w3)
+		l.Info(ctx, "add")
+		t.Assert(gstr.Contains(w1.String(), "[INFO] add"), true)
+		t.Assert(w1.String(), w2.String())
+		t.Assert(w1.String(), w3.String())
+	})
+	// File and writers.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w    = bytes.NewBuffer(nil)
+			path = gfile.Temp(gtime.TimestampNanoStr())
+			l    = glog.New()
+		)
+		defer gfile.Remove(path)
+		l.SetStdoutPrint(false)
+		t.AssertNil(l.SetPath(path))
+		l.AddWriter(w)
+		l.Info(ctx, "file and writer")
+		files, err := gfile.ScanDirFile(path, "*.log")
+		t.AssertNil(err)
+		t.Assert(len(files), 1)
+		t.Assert(gfile.GetContents(files[0]), w.String())
+	})
+}
+
+func Test_SetWriterForLevel(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			defaultWriter = bytes.NewBuffer(nil)
+			errorWriter   = bytes.NewBuffer(nil)
+			fanoutWriter  = bytes.NewBuffer(nil)
+			l             = glog.NewWithWriter(defaultWriter)
+		)
+		l.SetWriterForLevel(glog.LEVEL_ERRO, errorWriter)
+		l.SetWriterForLevel(glog.LEVEL_CRIT, glog.MultiWriter(errorWriter, fanoutWriter))
+		l.Info(ctx, "info")
+		l.Error(ctx, "error")
+		l.Critical(ctx, "critical")
+		t.Assert(gstr.Contains(defaultWriter.String(), "info"), true)
+		t.Assert(gstr.Contains(defaultWriter.String(), "error"), false)
+		t.Assert(gstr.Contains(defaultWriter.String(), "critical"), false)
+		// The logging content is only written to the writer of its exact level.
+		t.Assert(gstr.Contains(errorWriter.String(), "info"), false)
+		t.Assert(gstr.Contains(errorWriter.String(), "error"), true)
+		t.Assert(gstr.Contains(errorWriter.String(), "critical"), true)
+		t.Assert(gstr.Contains(fanoutWriter.String(), "error"), false)
+		t.Assert(gstr.Contains(fanoutWriter.String(), "critical"), true)
+
+		// Remove the writer of the level, and the cloned logger does not affect its parent.
+		defaultWriter.Reset()
+		errorWriter.Reset()
+		l.Clone().SetWriterForLevel(glog.LEVEL_INFO, errorWriter)
+		l.SetWriterForLevel(glog.LEVEL_ERRO, nil)
+		l.Info(ctx, "info")
+		l.Error(ctx, "error")
+		t.Assert(gstr.Contains(defaultWriter.String(), "info"), true)
+		t.Assert(gstr.Contains(defaultWriter.String(), "error"), true)
+		t.Assert(errorWriter.String(), "")
+	})
+}
+
+func Test_SetErrorStack(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w   = bytes.NewBuffer(nil)
+			l   = glog.NewWithWriter(w)
+			err = gerror.New("error with stack")
+		)
+		// Disabled in default.
+		l.Info(ctx, err)
+		t.Assert(gstr.Contains(w.String(), "error with stack"), true)
+		t.Assert(gstr.Contains(w.String(), "Stack:"), false)
+
+		w.Reset()
+		l.SetErrorStack(true)
+		l.Info(ctx, err)
+		t.Assert(gstr.Contains(w.String(), "error with stack"), true)
+		t.Assert(gstr.Contains(w.String(), "Stack:"), true)
+		t.Assert(gstr.Contains(w.String(), "Test_SetErrorStack"), true)
+
+		// The errors without stacks are logged as they are.
+		w.Reset()
+		l.Info(ctx, errors.New("error without stack"))
+		t.Assert(gstr.Contains(w.String(), "error without stack"), true)
+		t.Assert(gstr.Contains(w.String(), "Stack:"), false)
+
+		// Chaining.
+		w.Reset()
+		l.SetErrorStack(false)
+		l.ErrorStack().Info(ctx, err)
+		t.Assert(gstr.Contains(w.String(), "Stack:"), true)
+	})
+	// Json format.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+			m map[string]interface{}
+		)
+		l.SetFormat(glog.FormatJson)
+		l.SetErrorStack(true)
+		l.Info(ctx, gerror.New("error with stack"))
+		t.AssertNil(json.UnmarshalUseNumber(w.Bytes(), &m))
+		t.Assert(m["content"], "error with stack")
+		stack, ok := m["stack"].([]interface{})
+		t.Assert(ok, true)
+		t.Assert(gstr.Contains(gstr.Join(gconv.Strings(stack), "\n"), "Test_SetErrorStack"), true)
+	})
+}
+
 func Test_SetFlags(t *testing.T) {
 	defaultLog := glog.DefaultLogger().Clone()
 	defer glog.SetDefaultLogger(defaultLog)
@@ -487,3 +643,212 @@ func Test_Concurrent(t *testing.T) {
 		t.Assert(gstr.Count(content, s), c)
 	})
 }
+
+func Test_SetSampler(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+		)
+		l.SetSampler(glog.NewSampler(2, time.Hour))
+		for i := 0; i < 5; i++ {
+			l.Print(ctx, "hot path")
+		}
+		l.Print(ctx, "another")
+		// Identical content in different level is not identical.
+		l.Info(ctx, "hot path")
+		t.Assert(gstr.Count(w.String(), "hot path sampled=true"), 3)
+		t.Assert(gstr.Count(w.String(), "another sampled=true"), 1)
+
+		// No sampling.
+		w.Reset()
+		l.SetSampler(nil)
+		l.Print(ctx, "hot path")
+		t.Assert(gstr.Count(w.String(), "hot path"), 1)
+		t.Assert(gstr.Contains(w.String(), "sampled=true"), false)
+	})
+	// New interval.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+		)
+		l.SetSampler(glog.NewSampler(1, 100*time.Millisecond))
+		l.Print(ctx, "hot path")
+		l.Print(ctx, "hot path")
+		time.Sleep(150 * time.Millisecond)
+		l.Print(ctx, "hot path")
+		t.Assert(gstr.Count(w.String(), "hot path"), 2)
+	})
+	// Async.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+		)
+		l.SetAsync(true)
+		l.SetSampler(glog.NewSampler(3, time.Hour))
+		for i := 0; i < 10; i++ {
+			l.Print(ctx, "hot path")
+		}
+		l.Flush()
+		t.Assert(gstr.Count(w.String(), "hot path sampled=true"), 3)
+	})
+	// No sampling in test mode.
+	gtest.C(t, func(t *gtest.T) {
+		glog.SetTestMode(true)
+		defer glog.SetTestMode(false)
+
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+		)
+		l.SetSampler(glog.NewSampler(2, time.Hour))
+		for i := 0; i < 5; i++ {
+			l.Print(ctx, "hot path")
+		}
+		t.Assert(gstr.Count(w.String(), "hot path"), 5)
+		t.Assert(gstr.Contains(w.String(), "sampled=true"), false)
+	})
+}
+
+func Test_SetRedactKeys(t *testing.T) {
+	type User struct {
+		Name     string `json:"name"`
+		Password string `json:"password"`
+	}
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w     = bytes.NewBuffer(nil)
+			l     = glog.NewWithWriter(w)
+			value = g.Map{
+				"name":  "john",
+				"Token": "abc",
+				"inner": g.Map{"PASSWORD": "123456"},
+				"users": []User{{Name: "smith", Password: "654321"}},
+			}
+		)
+		l.SetRedactKeys([]string{"password", "token"})
+		l.Print(ctx, value)
+		t.Assert(gstr.Contains(w.String(), `"name":"john"`), true)
+		t.Assert(gstr.Contains(w.String(), `"Token":"***"`), true)
+		t.Assert(gstr.Contains(w.String(), `"PASSWORD":"***"`), true)
+		t.Assert(gstr.Contains(w.String(), `"password":"***"`), true)
+		t.Assert(gstr.Contains(w.String(), `"name":"smith"`), true)
+		t.Assert(gstr.Contains(w.String(), "123456"), false)
+		t.Assert(gstr.Contains(w.String(), "654321"), false)
+		// The given value is not modified.
+		t.Assert(value["Token"], "abc")
+
+		// Json format.
+		w.Reset()
+		l.SetFormat(glog.FormatJson)
+		l.Print(ctx, User{Name: "john", Password: "123456"})
+		t.Assert(gstr.Contains(w.String(), "123456"), false)
+		t.Assert(gstr.Contains(w.String(), "***"), true)
+
+		// No redaction.
+		w.Reset()
+		l.SetRedactKeys(nil)
+		l.Print(ctx, User{Name: "john", Password: "123456"})
+		t.Assert(gstr.Contains(w.String(), "123456"), true)
+	})
+}
+
+func Test_SetRedactPattern(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+		)
+		t.AssertNil(l.SetRedactPattern(`\b\d{4}[- ]?\d{4}[- ]?\d{4}[- ]?\d{4}\b`))
+		l.Print(ctx, "card:", "4111-1111-1111-1111")
+		l.Printf(ctx, "card: %s", "4111111111111111")
+		l.Print(ctx, g.Map{"card": "4111 1111 1111 1111"})
+		t.Assert(gstr.Contains(w.String(), "4111"), false)
+		t.Assert(gstr.Count(w.String(), "***"), 3)
+
+		t.AssertNE(l.SetRedactPattern(`(`), nil)
+		t.AssertNil(l.SetRedactPattern(""))
+		w.Reset()
+		l.Print(ctx, "card:", "4111-1111-1111-1111")
+		t.Assert(gstr.Contains(w.String(), "4111-1111-1111-1111"), true)
+	})
+}
+
+func Test_SetSampling(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+		)
+		l.SetSampling(3)
+		for i := 0; i < 7; i++ {
+			l.Print(ctx, "hot path")
+		}
+		l.Print(ctx, "another")
+		t.Assert(gstr.Count(w.String(), "hot path"), 3)
+		t.Assert(gstr.Count(w.String(), "another"), 1)
+		t.Assert(gstr.Contains(w.String(), "repeated"), false)
+		// No marker for the kept logging content, as the dropped ones are output as summary lines.
+		t.Assert(gstr.Contains(w.String(), "sampled=true"), false)
+
+		// Summary of the dropped ones.
+		w.Reset()
+		l.Flush()
+		t.Assert(gstr.Count(w.String(), "hot path ... repeated 4 times"), 1)
+		t.Assert(gstr.Contains(w.String(), "another"), false)
+		t.Assert(gstr.Contains(w.String(), "sampled=true"), false)
+
+		// No sampling.
+		w.Reset()
+		l.SetSampling(1)
+		l.Print(ctx, "hot path")
+		t.Assert(gstr.Count(w.String(), "hot path"), 1)
+		t.Assert(gstr.Contains(w.String(), "sampled=true"), false)
+	})
+	// Level filtering and async.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+		)
+		l.SetAsync(true)
+		l.SetLevel(glog.LEVEL_INFO | glog.LEVEL_WARN)
+		l.SetSampling(5)
+		for i := 0; i < 10; i++ {
+			l.Debug(ctx, "hot path")
+			l.Info(ctx, "hot path")
+		}
+		l.Flush()
+		t.Assert(gstr.Count(w.String(), "[INFO] hot path\n"), 2)
+		t.Assert(gstr.Count(w.String(), "[INFO] hot path ... repeated 8 times"), 1)
+		t.Assert(gstr.Contains(w.String(), "[DEBU]"), false)
+	})
+}
+
+func Test_SetSamplingRate(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			w = bytes.NewBuffer(nil)
+			l = glog.NewWithWriter(w)
+		)
+		l.SetSamplingRate(2)
+		for i := 0; i < 5; i++ {
+			l.Print(ctx, "hot path")
+		}
+		time.Sleep(1100 * time.Millisecond)
+		l.Print(ctx, "hot path")
+		t.Assert(gstr.Count(w.String(), "hot path"), 3)
+
+		w.Reset()
+		l.Flush()
+		t.Assert(gstr.Count(w.String(), "hot path ... repeated 3 times"), 1)
+
+		// No sampling.
+		w.Reset()
+		l.SetSamplingRate(0)
+		l.Print(ctx, "hot path")
+		t.Assert(gstr.Contains(w.String(), "sampled=true"), false)
+	})
+}